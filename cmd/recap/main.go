@@ -1,94 +1,591 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata" // --tz must work on machines without a zoneinfo database
 
 	"github.com/dennislee928/github-recap-2025/internal/analyze"
 	"github.com/dennislee928/github-recap-2025/internal/config"
 	"github.com/dennislee928/github-recap-2025/internal/githubapi"
 	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
 )
 
+// options are the per-user collection settings shared by single-user and
+// team runs.
+type options struct {
+	window period.Window
+	loc *time.Location
+	maxSearch int
+	skipGrowth bool
+	growth githubapi.GrowthSelection
+	growthTop int // add the user's N most contributed-to repos to growth
+	commits bool
+	reviews bool
+	languages string // analyze.LanguagesChanges or analyze.LanguagesBytes
+	languageOpts analyze.LanguageOptions
+	weekdayStreak bool
+	preflight *model.Preflight
+}
+
+// sections lists the recap sections opts will collect, as named in the
+// preflight and diagnostics.
+func (o options) sections() []string {
+	s := []string{"contributions", "pull_requests", "issues", "languages"}
+	if o.commits {
+		s = append(s, "commits")
+	}
+	if o.reviews {
+		s = append(s, "reviews")
+	}
+	if !o.skipGrowth {
+		s = append(s, "growth")
+	}
+	return s
+}
+
 func main() {
 	var (
 		user      = flag.String("user", "", "GitHub username (login)")
+		org       = flag.String("org", "", "Team mode: recap every member of this organization")
+		team      = flag.String("team", "", "Team mode: recap every member of this team (org/slug)")
+		members   = flag.String("members", "", "Team mode: file with one login per line")
+		concurrency = flag.Int("concurrency", 4, "Team mode: members collected in parallel")
 		year      = flag.Int("year", 2025, "Year for recap (e.g., 2025)")
-		out       = flag.String("out", "./web/recap_2025.json", "Output JSON path")
+		fromDate  = flag.String("from", "", "Start date YYYY-MM-DD (overrides --year; use with --to)")
+		toDate    = flag.String("to", "", "End date YYYY-MM-DD, inclusive (default today when --from is set)")
+		quarter   = flag.String("quarter", "", "Calendar quarter preset, e.g. 2025Q3 (overrides --year)")
+		tz        = flag.String("tz", "", "IANA time zone for window bounds, days and hour histograms (default: from the GitHub profile location, else the local zone)")
+		fiscal    = flag.Int("fiscal-start", 1, "First month (1-12) of the --year window; 7 makes --year 2025 mean Jul 2025 - Jun 2026")
+		out       = flag.String("out", "./web/recap_2025.json", "Output JSON path (team mode: the team recap; member recaps are written next to it)")
+		compareYear = flag.Int("compare-year", 0, "Also build the same window for this earlier year and add a comparison section")
+		compareFile = flag.String("compare-file", "", "Compare against a previously written recap JSON (e.g. recap_2024.json)")
+		commits   = flag.Bool("commits", false, "Walk default-branch history of contributed repos for commit-level stats (one GraphQL query per 100 commits)")
+		reviews   = flag.Bool("reviews", false, "Collect review timelines for review depth metrics (approvals vs change requests, time to first review, who you review)")
+		languages = flag.String("languages", analyze.LanguagesChanges, "Language attribution: changes (lines changed by your PRs, per file extension) or bytes (today's repo language bytes)")
+		langWeight = flag.String("language-weight", analyze.WeightRaw, "How repos are combined for --languages bytes: raw (sum bytes), activity (weight each repo by your activity in it) or equal (every repo counts the same)")
+		langIgnore = flag.String("language-ignore", strings.Join(analyze.DefaultIgnore, ","), "Comma-separated languages to leave out (e.g. \"Jupyter Notebook,HTML\"; \"\" keeps everything); \"forks\" and \"archived\" leave out such repos in bytes mode")
+		weekdayStreak = flag.Bool("weekday-streak", false, "Also report the longest weekday-only streak, which weekends neither break nor extend")
 		skipGrowth = flag.Bool("skip-growth", false, "Skip stars/forks gained calculation (rate-limit heavy)")
-		maxSearch = flag.Int("max-search", 1000, "Max results to pull from GraphQL search queries")
+		growthOrgs = flag.Bool("growth-orgs", false, "Growth: also count org repos the user has admin or maintain permission on (token must be the user's)")
+		growthTop = flag.Int("growth-top", 0, "Growth: also count the N repos the user contributed to most (from top repos)")
+		growthRepos = flag.String("growth-repos", "", "Growth: also count these repos, comma-separated owner/name (forks included)")
+		growthForks = flag.Bool("growth-forks", false, "Growth: keep forks of upstream projects (excluded by default)")
+		maxSearch = flag.Int("max-search", 10000, "Max results to pull per GraphQL search (ranges over GitHub's 1000-result cap are split automatically)")
+		cacheDir  = flag.String("cache-dir", "", "Directory for the on-disk API response cache (default $RECAP_CACHE_DIR; empty disables)")
+		cacheTTL  = flag.Duration("cache-ttl", 6*time.Hour, "How long cached GraphQL responses for still-open windows are reused")
+		timeout   = flag.Duration("timeout", 0, "Stop collecting after this long and write what was gathered, marked incomplete (0 = no limit)")
+		record    = flag.String("record", "", "Save every API request and response to this directory, for --replay")
+		replay    = flag.String("replay", "", "Answer every API request from a --record directory instead of GitHub (offline; no credentials needed)")
+		checkAuth = flag.Bool("check-auth", false, "Only run the credential preflight: report missing scopes/permissions per section and exit")
 	)
 	flag.Parse()
 
-	if *user == "" {
-		log.Fatal("--user is required")
+	teamMode := *org != "" || *team != "" || *members != ""
+	switch {
+	case teamMode && *user != "":
+		log.Fatal("use either --user or one of --org/--team/--members")
+	case !teamMode && *user == "":
+		log.Fatal("--user is required (or --org/--team/--members for a team recap)")
+	case *compareYear != 0 && *compareFile != "":
+		log.Fatal("use either --compare-year or --compare-file, not both")
+	case teamMode && (*compareYear != 0 || *compareFile != ""):
+		log.Fatal("--compare-year/--compare-file are not supported in team mode")
+	case *languages != analyze.LanguagesChanges && *languages != analyze.LanguagesBytes:
+		log.Fatalf("--languages must be %s or %s", analyze.LanguagesChanges, analyze.LanguagesBytes)
+	case *record != "" && *replay != "":
+		log.Fatal("use either --record or --replay, not both")
+	case *langWeight != analyze.WeightRaw && *langWeight != analyze.WeightActivity && *langWeight != analyze.WeightEqual:
+		log.Fatalf("--language-weight must be %s, %s or %s", analyze.WeightRaw, analyze.WeightActivity, analyze.WeightEqual)
 	}
 
 	cfg, err := config.FromEnv()
 	if err != nil {
 		log.Fatalf("config error: %v", err)
 	}
+	if *cacheDir != "" {
+		cfg.CacheDir = *cacheDir
+	}
+	cfg.CacheTTL = *cacheTTL
+	cfg.RecordDir = *record
+	cfg.ReplayDir = *replay
+
+	ctx, stop := runContext(*timeout)
+	defer stop()
+
+	client, err := githubapi.New(cfg)
+	if err != nil {
+		log.Fatalf("client: %v", err)
+	}
+	if cfg.IsEnterprise() {
+		version, err := client.ServerVersion(ctx)
+		if err != nil {
+			log.Printf("WARN: could not read Enterprise Server version: %v", err)
+		} else {
+			log.Printf("Using %s at %s", version, cfg.APIBase)
+		}
+	}
+
+	sections := options{skipGrowth: *skipGrowth, commits: *commits, reviews: *reviews}.sections()
+	if *org != "" || *team != "" {
+		sections = append(sections, "team")
+	}
+	auth, err := preflight(ctx, client, *user, sections)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *checkAuth {
+		if auth == nil {
+			log.Fatal("auth preflight could not run")
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(auth); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	loc, err := resolveZone(ctx, client, *tz, *user)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Using time zone %s", loc)
+
+	win, err := resolveWindow(*year, *fiscal, *quarter, *fromDate, *toDate, loc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := options{window: win, loc: loc, maxSearch: *maxSearch, skipGrowth: *skipGrowth, commits: *commits, reviews: *reviews, languages: *languages, preflight: auth}
+	opts.growth = githubapi.GrowthSelection{OrgMaintained: *growthOrgs, Repos: splitList(*growthRepos), IncludeForks: *growthForks}
+	opts.growthTop = *growthTop
+	opts.languageOpts = analyze.LanguageOptions{Weighting: *langWeight, Ignore: splitList(*langIgnore)}
+	opts.weekdayStreak = *weekdayStreak
+
+	if teamMode {
+		spec := teamSpec{org: *org, team: *team, membersFile: *members}
+		err := runTeam(ctx, client, spec, opts, *concurrency, *out)
+		logUsage(client, cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		exitIfInterrupted(ctx, *out)
+		return
+	}
+
+	in, err := collectUser(ctx, client, *user, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	recap := analyze.BuildRecap(in)
+
+	prev, err := previousRecap(ctx, client, *user, opts, *compareYear, *compareFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if prev != nil {
+		recap.Comparison = analyze.Compare(prev, recap)
+	}
+	recap.Meta.APIUsage = logUsage(client, cfg)
+
+	if err := writeJSON(*out, recap); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("OK: wrote %s\n", *out)
+	exitIfInterrupted(ctx, *out)
+}
+
+// preflight checks the credentials and logs what each section will miss. A
+// token GitHub rejects outright is an error; a preflight that cannot run for
+// other reasons is only a warning.
+func preflight(ctx context.Context, client *githubapi.Client, user string, sections []string) (*model.Preflight, error) {
+	p, err := client.Preflight(ctx, user, sections)
+	if err != nil {
+		if f := githubapi.Failures("preflight", "check_auth", err); f[0].Class == "unauthorized" {
+			return nil, fmt.Errorf("credentials rejected (%s auth): %v", client.AuthMethod(), err)
+		}
+		log.Printf("WARN: auth preflight failed: %v", err)
+		return nil, nil
+	}
+	who := p.Viewer
+	if who == "" {
+		who = "app installation"
+	}
+	log.Printf("Authenticated via %s (%s) as %s", p.Method, p.TokenKind, who)
+	for _, w := range p.Warnings {
+		log.Printf("WARN: %s", w)
+	}
+	for _, s := range p.Sections {
+		switch {
+		case len(s.Missing) > 0:
+			log.Printf("WARN: %s: token lacks %s; %s", s.Section, strings.Join(s.Missing, ", "), s.Effect)
+		case !s.Verified:
+			log.Printf("NOTE: %s: needs %s (cannot be verified for this token type); otherwise %s", s.Section, strings.Join(s.Needs, ", "), s.Effect)
+		}
+	}
+	return p, nil
+}
+
+// runContext returns the context every API call runs under. It is cancelled
+// by SIGINT/SIGTERM or once timeout (if non-zero) elapses, with a cause saying
+// which. After the first signal the default handling is restored, so a second
+// Ctrl-C kills the process without writing anything.
+func runContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(sigs)
+		select {
+		case sig := <-sigs:
+			log.Printf("Received %s: stopping and writing partial results (again to abort)", sig)
+			cancel(interruption{fmt.Sprintf("received %s", sig), context.Canceled})
+		case <-ctx.Done():
+		}
+	}()
+	stop := func() { cancel(context.Canceled) }
+
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	tctx, tcancel := context.WithTimeoutCause(ctx, timeout, interruption{fmt.Sprintf("--timeout %s elapsed", timeout), context.DeadlineExceeded})
+	return tctx, func() {
+		tcancel()
+		stop()
+	}
+}
+
+// interruption is the cause runContext cancels with. It wraps the matching
+// context error so requests it cut short are classified as canceled or
+// timed out in the diagnostics.
+type interruption struct {
+	reason string
+	err    error
+}
+
+func (e interruption) Error() string { return e.reason }
+func (e interruption) Unwrap() error { return e.err }
+
+// stopped reports whether the run was cancelled and, if so, records why in
+// in so the partial recap is marked incomplete.
+func stopped(ctx context.Context, in *analyze.Input) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+	if in.Interrupted == "" {
+		in.Interrupted = context.Cause(ctx).Error()
+		log.Printf("[%s] WARN: interrupted (%s); keeping what was collected", in.User, in.Interrupted)
+	}
+	if in.Contributions == nil {
+		in.Contributions = &model.ContributionsCollection{}
+	}
+	return true
+}
+
+// exitIfInterrupted exits non-zero after partial results were written, so
+// schedulers can tell a cut-short run from a complete one.
+func exitIfInterrupted(ctx context.Context, out string) {
+	if ctx.Err() != nil {
+		log.Fatalf("interrupted: %v (partial results in %s)", context.Cause(ctx), out)
+	}
+}
 
-	from := time.Date(*year, 1, 1, 0, 0, 0, 0, time.UTC)
-	to := time.Date(*year, 12, 31, 23, 59, 59, 0, time.UTC)
+// collectUser runs every collector for one user. Contributions and searches
+// are required; languages and growth degrade to warnings. If ctx is cancelled
+// part-way, collection stops and what was gathered is returned without an
+// error, marked as interrupted.
+func collectUser(ctx context.Context, client *githubapi.Client, login string, opts options) (analyze.Input, error) {
+	from, to := opts.window.From, opts.window.To
+	in := analyze.Input{
+		User: login,
+		Window: opts.window,
+		Location: opts.loc,
+		LanguageOptions: opts.languageOpts,
+		WeekdayStreak: opts.weekdayStreak,
+	}
+	if opts.skipGrowth {
+		in.Skipped = append(in.Skipped, "--skip-growth")
+	}
+	in.Preflight = opts.preflight
 
-	client := githubapi.New(cfg)
+	log.Printf("[%s] Fetching contributionsCollection for %s (%s..%s)...", login, opts.window.Label, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	cc, err := client.FetchContributionsCollection(ctx, login, from, to)
+	in.Contributions = cc
+	recordFailures(ctx, &in, "contributions", "contributions_collection", err)
+	if stopped(ctx, &in) {
+		return in, nil
+	}
+	if err != nil {
+		return in, fmt.Errorf("FetchContributionsCollection: %w", err)
+	}
+	for _, rc := range cc.RepoCoverage {
+		if !rc.Complete || !rc.Consistent {
+			log.Printf("[%s] WARN: %s by repository: %d repos sum to %d of %d total (complete=%v)", login, rc.Kind, rc.Repos, rc.SumByRepo, rc.Total, rc.Complete)
+		}
+	}
 
-	log.Printf("Fetching contributionsCollection for %s %d...", *user, *year)
-	cc, err := client.FetchContributionsCollection(*user, from, to)
+	log.Printf("[%s] Fetching PR details via GraphQL search (max=%d)...", login, opts.maxSearch)
+	in.PRs, in.PRSearch, err = client.SearchPullRequests(ctx, login, from, to, opts.maxSearch)
+	recordFailures(ctx, &in, "pull_requests", "search_pull_requests", err)
+	if stopped(ctx, &in) {
+		return in, nil
+	}
 	if err != nil {
-		log.Fatalf("FetchContributionsCollection: %v", err)
+		return in, fmt.Errorf("SearchPullRequests: %w", err)
 	}
+	warnIncomplete(login, "PR search", in.PRSearch)
 
-	log.Printf("Fetching PR details via GraphQL search (max=%d)...", *maxSearch)
-	prs, err := client.SearchPullRequests(*user, from, to, *maxSearch)
+	log.Printf("[%s] Fetching Issue details via GraphQL search (opened/closed)...", login)
+	in.IssuesOpened, in.IssuesClosed, in.IssuesOpenedSearch, in.IssuesClosedSearch, err = client.SearchIssuesOpenedClosed(ctx, login, from, to, opts.maxSearch)
+	recordFailures(ctx, &in, "issues", "search_issues", err)
+	if stopped(ctx, &in) {
+		return in, nil
+	}
 	if err != nil {
-		log.Fatalf("SearchPullRequests: %v", err)
+		return in, fmt.Errorf("SearchIssuesOpenedClosed: %w", err)
+	}
+	warnIncomplete(login, "issues opened search", in.IssuesOpenedSearch)
+	warnIncomplete(login, "issues closed search", in.IssuesClosedSearch)
+
+	if opts.commits {
+		recordFailures(ctx, &in, "commits", "commit_history", collectCommits(ctx, client, login, opts, &in))
+	}
+
+	if opts.reviews {
+		log.Printf("[%s] Fetching review timelines via GraphQL search...", login)
+		recordFailures(ctx, &in, "reviews", "search_reviews", collectReviews(ctx, client, login, opts, &in))
+	}
+	if stopped(ctx, &in) {
+		return in, nil
+	}
+
+	if opts.languages == analyze.LanguagesChanges && len(in.PRs) > 0 {
+		log.Printf("[%s] Fetching files changed by %d PRs for language attribution...", login, len(in.PRs))
+		in.ChangedFiles, err = client.FetchPRFiles(ctx, in.PRs)
+		recordFailures(ctx, &in, "languages", "pull_request_files", err)
+		if stopped(ctx, &in) {
+			return in, nil
+		}
+	}
+	if !analyze.HasCountedChanges(in.ChangedFiles, opts.languageOpts) {
+		if opts.languages == analyze.LanguagesChanges {
+			log.Printf("[%s] No PR changes in a recognised language; falling back to repo language bytes", login)
+		}
+		log.Printf("[%s] Fetching repo language stats via REST (weighted bytes)...", login)
+		in.ChangedFiles = nil
+		in.Languages, err = client.FetchLanguagesForContributedRepos(ctx, cc)
+		recordFailures(ctx, &in, "languages", "list_languages", err)
+		if stopped(ctx, &in) {
+			return in, nil
+		}
 	}
 
-	log.Printf("Fetching Issue details via GraphQL search (opened/closed)...")
-	issuesOpened, issuesClosed, err := client.SearchIssuesOpenedClosed(*user, from, to, *maxSearch)
+	log.Printf("[%s] Fetching metadata of the top repos...", login)
+	in.Repos, err = client.RepoMetadata(ctx, analyze.TopRepoNames(cc, analyze.MaxTopRepos))
+	recordFailures(ctx, &in, "repositories", "repo_metadata", err)
+	if stopped(ctx, &in) {
+		return in, nil
+	}
+
+	if !opts.skipGrowth {
+		sel := opts.growth
+		sel.Top = analyze.TopRepoNames(cc, opts.growthTop)
+		log.Printf("[%s] Calculating stars/forks gained in %s for owned and selected repos (may be slow)...", login, opts.window.Label)
+		in.Growth, err = client.CalcStarsForksGained(ctx, login, from, to, sel)
+		recordFailures(ctx, &in, "growth", "list_owned_repos", err)
+		stopped(ctx, &in)
+	}
+	return in, nil
+}
+
+// splitList parses a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// recordFailures adds err's failed sub-requests to the recap diagnostics and
+// logs a summary. Failures caused by the run being interrupted are recorded
+// but not logged; stopped reports the interruption once.
+func recordFailures(ctx context.Context, in *analyze.Input, section, op string, err error) {
+	failures := githubapi.Failures(section, op, err)
+	if len(failures) == 0 {
+		return
+	}
+	in.Failures = append(in.Failures, failures...)
+	if ctx.Err() != nil {
+		return
+	}
+	first := failures[0]
+	if first.Repo != "" {
+		log.Printf("[%s] WARN: %s: %d failed request(s), e.g. %s %s: %s (%s)", in.User, section, len(failures), first.Operation, first.Repo, first.Error, first.Class)
+	} else {
+		log.Printf("[%s] WARN: %s: %s: %s (%s)", in.User, section, first.Operation, first.Error, first.Class)
+	}
+}
+
+func collectCommits(ctx context.Context, client *githubapi.Client, login string, opts options, in *analyze.Input) error {
+	profile, err := client.FetchUser(ctx, login)
 	if err != nil {
-		log.Fatalf("SearchIssuesOpenedClosed: %v", err)
+		return err
+	}
+	repos := make([]string, 0, len(in.Contributions.ByRepoCommits))
+	for repo := range in.Contributions.ByRepoCommits {
+		repos = append(repos, repo)
 	}
+	sort.Strings(repos)
+
+	log.Printf("[%s] Walking commit history of %d repos...", login, len(repos))
+	in.Commits, err = client.FetchCommits(ctx, profile.ID, repos, opts.window.From, opts.window.To)
+	// Partial history is still worth reporting.
+	in.CommitsCollected = true
+	return err
+}
 
-	log.Printf("Fetching repo language stats via REST (weighted bytes)...")
-	langAgg, err := client.FetchLanguagesForContributedRepos(cc)
+func collectReviews(ctx context.Context, client *githubapi.Client, login string, opts options, in *analyze.Input) error {
+	var err error
+	in.ReviewsGiven, in.ReviewsGivenSearch, err = client.SearchReviewsGiven(ctx, login, opts.window.From, opts.window.To, opts.maxSearch)
 	if err != nil {
-		log.Printf("WARN: FetchLanguagesForContributedRepos: %v", err)
+		return err
 	}
+	warnIncomplete(login, "reviews given search", in.ReviewsGivenSearch)
+	in.ReviewsReceived, in.ReviewsReceivedSearch, err = client.SearchReviewsReceived(ctx, login, opts.window.From, opts.window.To, opts.maxSearch)
+	if err != nil {
+		return err
+	}
+	warnIncomplete(login, "reviews received search", in.ReviewsReceivedSearch)
+	in.ReviewsCollected = true
+	return nil
+}
 
-	var growth *model.GrowthMetrics
-	if !*skipGrowth {
-		log.Printf("Calculating stars/forks gained in %d for owned repos (may be slow)...", *year)
-		growth, err = client.CalcStarsForksGainedOwnedRepos(*user, from, to)
+// previousRecap builds (--compare-year) or loads (--compare-file) the recap
+// to compare against; it returns nil when neither is set, or when the run was
+// interrupted and the comparison would be skewed.
+func previousRecap(ctx context.Context, client *githubapi.Client, user string, opts options, year int, file string) (*model.Recap, error) {
+	switch {
+	case file != "":
+		b, err := os.ReadFile(file)
 		if err != nil {
-			log.Printf("WARN: CalcStarsForksGainedOwnedRepos: %v", err)
+			return nil, fmt.Errorf("read --compare-file: %w", err)
+		}
+		var prev model.Recap
+		if err := json.Unmarshal(b, &prev); err != nil {
+			return nil, fmt.Errorf("decode --compare-file %s: %w", file, err)
 		}
+		return &prev, nil
+	case year != 0:
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+		prevOpts := opts
+		prevOpts.window = opts.window.AddYears(year - opts.window.From.Year())
+		prevOpts.skipGrowth = true // not compared, and the slowest collector
+		log.Printf("Building comparison recap for %s...", prevOpts.window.Label)
+		in, err := collectUser(ctx, client, user, prevOpts)
+		if err != nil {
+			return nil, fmt.Errorf("comparison recap %s: %w", prevOpts.window.Label, err)
+		}
+		if in.Interrupted != "" {
+			log.Printf("WARN: comparison recap %s interrupted; leaving the comparison out", prevOpts.window.Label)
+			return nil, nil
+		}
+		return analyze.BuildRecap(in), nil
 	}
+	return nil, nil
+}
 
-	recap := analyze.BuildRecap(*user, *year, cc, prs, issuesOpened, issuesClosed, langAgg, growth)
+func logUsage(client *githubapi.Client, cfg config.Config) []model.APIUsage {
+	usage := client.RateLimitUsage()
+	for _, u := range usage {
+		log.Printf("API usage [%s]: %d requests, ~%d points used, %d/%d remaining (resets %s), %d retries, waited %.0fs",
+			u.Resource, u.Requests, u.Used, u.Remaining, u.Limit, u.Reset.Format(time.Kitchen), u.Retries, u.WaitedSeconds)
+	}
+	if st, ok := client.CacheStats(); ok {
+		log.Printf("Cache %s: %d hits, %d revalidated (304), %d fetched", cfg.CacheDir, st.Hits, st.Revalidated, st.Misses)
+	}
+	return usage
+}
 
-	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
-		log.Fatalf("mkdir out dir: %v", err)
+func writeJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("mkdir out dir: %w", err)
 	}
 
-	f, err := os.Create(*out)
+	f, err := os.Create(path)
 	if err != nil {
-		log.Fatalf("create out: %v", err)
+		return fmt.Errorf("create out: %w", err)
 	}
 	defer f.Close()
 
 	enc := json.NewEncoder(f)
 	enc.SetIndent("", "  ")
-	if err := enc.Encode(recap); err != nil {
-		log.Fatalf("write json: %v", err)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("write json: %w", err)
 	}
+	return f.Close()
+}
 
-	fmt.Printf("OK: wrote %s\n", *out)
+// resolveZone returns the --tz zone, else one guessed from the user's GitHub
+// profile location, else the machine's local zone (always, in team mode).
+func resolveZone(ctx context.Context, client *githubapi.Client, tz, user string) (*time.Location, error) {
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --tz %q: %w", tz, err)
+		}
+		return loc, nil
+	}
+	if user == "" {
+		// Team mode: members may be anywhere, so there is no profile to go by.
+		return period.Local(), nil
+	}
+	profile, err := client.FetchUser(ctx, user)
+	if err != nil {
+		log.Printf("WARN: FetchUser: %v (falling back to local time zone)", err)
+		return period.Local(), nil
+	}
+	if loc, ok := period.ZoneForLocation(profile.Location); ok {
+		log.Printf("Time zone %s guessed from profile location %q (override with --tz)", loc, profile.Location)
+		return loc, nil
+	}
+	return period.Local(), nil
+}
+
+// resolveWindow picks the recap window from the flags: --from/--to win over
+// --quarter, which wins over --year (shifted by --fiscal-start).
+func resolveWindow(year, fiscalStart int, quarter, from, to string, loc *time.Location) (period.Window, error) {
+	switch {
+	case from != "" && quarter != "":
+		return period.Window{}, fmt.Errorf("use either --from/--to or --quarter, not both")
+	case from != "":
+		return period.Range(from, to, loc)
+	case to != "":
+		return period.Window{}, fmt.Errorf("--to requires --from")
+	case quarter != "":
+		return period.Quarter(quarter, loc)
+	}
+	if fiscalStart < 1 || fiscalStart > 12 {
+		return period.Window{}, fmt.Errorf("--fiscal-start must be 1-12, got %d", fiscalStart)
+	}
+	return period.FiscalYear(year, time.Month(fiscalStart), loc), nil
+}
+
+func warnIncomplete(login, what string, cov model.SearchCoverage) {
+	if !cov.Complete {
+		log.Printf("[%s] WARN: %s incomplete: fetched %d of %d results across %d sub-ranges (raise --max-search?)", login, what, cov.Fetched, cov.TotalCount, cov.Windows)
+	}
 }
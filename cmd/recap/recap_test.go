@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/analyze"
+	"github.com/dennislee928/github-recap-2025/internal/config"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/golden and re-record testdata/replay from the fake GitHub")
+
+const (
+	goldenFile = "testdata/golden/octocat.json"
+	replayDir  = "testdata/replay"
+)
+
+// goldenNow pins Meta.GeneratedAt so the output is byte-for-byte stable.
+var goldenNow = time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+// goldenOptions mirror a default run of
+//
+//	recap --user octocat --from 2025-01-01 --to 2025-12-31 --tz UTC --growth-orgs --growth-top 3 --commits
+func goldenOptions(t *testing.T) options {
+	t.Helper()
+	win, err := resolveWindow(2025, 1, "", "2025-01-01", "2025-12-31", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return options{
+		window:    win,
+		loc:       time.UTC,
+		maxSearch: 10000,
+		commits:   true,
+		growth:    githubapi.GrowthSelection{OrgMaintained: true},
+		growthTop: 3,
+		languages: analyze.LanguagesChanges,
+		languageOpts: analyze.LanguageOptions{
+			Weighting: analyze.WeightRaw,
+			Ignore:    analyze.DefaultIgnore,
+		},
+	}
+}
+
+// runRecap collects octocat's recap through cfg the way main does.
+func runRecap(t *testing.T, cfg config.Config) *model.Recap {
+	t.Helper()
+	ctx := context.Background()
+	client, err := githubapi.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := goldenOptions(t)
+	if opts.preflight, err = preflight(ctx, client, "octocat", opts.sections()); err != nil {
+		t.Fatal(err)
+	}
+	in, err := collectUser(ctx, client, "octocat", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.Now = goldenNow
+	return analyze.BuildRecap(in)
+}
+
+func marshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(b, '\n')
+}
+
+// TestGolden runs the collectors against the fake GitHub and compares the
+// recap with testdata/golden. Run with -update to accept a change; that also
+// re-records testdata/replay.
+func TestGolden(t *testing.T) {
+	srv, err := githubtest.NewServer(githubtest.FixtureDir("octocat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	cfg := srv.Config()
+	if *update {
+		if err := os.RemoveAll(replayDir); err != nil {
+			t.Fatal(err)
+		}
+		cfg.RecordDir = replayDir
+	}
+	got := marshal(t, runRecap(t, cfg))
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenFile, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("%v (run go test ./cmd/recap -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		f, err := os.CreateTemp("", "octocat-*.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(got)
+		f.Close()
+		t.Errorf("recap differs from %s; got written to %s (run go test ./cmd/recap -update to accept)", goldenFile, f.Name())
+	}
+}
+
+// TestReplay runs the collectors offline from testdata/replay, as CI does,
+// against github.com endpoints, and expects the golden recap.
+func TestReplay(t *testing.T) {
+	if *update {
+		t.Skip("recordings are being rewritten by TestGolden")
+	}
+	cfg := config.Config{
+		APIBase:    "https://api.github.com",
+		GraphQLEnd: "https://api.github.com/graphql",
+		ReplayDir:  replayDir,
+	}
+	got := runRecap(t, cfg)
+	if p := got.Diagnostics.Preflight; p == nil || p.Method != githubapi.AuthReplay {
+		t.Fatalf("preflight = %+v, want method %s", p, githubapi.AuthReplay)
+	}
+
+	b, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want model.Recap
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Fatal(err)
+	}
+	// Replayed credentials are a placeholder token, so only the preflight
+	// differs from the live run.
+	got.Diagnostics.Preflight, want.Diagnostics.Preflight = nil, nil
+	if g, w := marshal(t, got), marshal(t, &want); !bytes.Equal(g, w) {
+		t.Errorf("replayed recap differs from %s", goldenFile)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dennislee928/github-recap-2025/internal/analyze"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// teamSpec is how the team was chosen; exactly one field is set.
+type teamSpec struct {
+	org         string
+	team        string
+	membersFile string
+}
+
+func (s teamSpec) String() string {
+	switch {
+	case s.org != "":
+		return "org:" + s.org
+	case s.team != "":
+		return "team:" + s.team
+	}
+	return "members:" + filepath.Base(s.membersFile)
+}
+
+func (s teamSpec) resolve(ctx context.Context, client *githubapi.Client) ([]string, error) {
+	switch {
+	case s.org != "":
+		return client.ListOrgMembers(ctx, s.org)
+	case s.team != "":
+		return client.ListTeamMembers(ctx, s.team)
+	}
+	return readMembersFile(s.membersFile)
+}
+
+// runTeam collects every member with bounded concurrency, writes each
+// member's recap next to out and the combined team recap to out. A member
+// that fails is reported in the team recap instead of aborting the run; if ctx
+// is cancelled, members not started yet are reported as not collected.
+func runTeam(ctx context.Context, client *githubapi.Client, spec teamSpec, opts options, concurrency int, out string) error {
+	logins, err := spec.resolve(ctx, client)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", spec, err)
+	}
+	if len(logins) == 0 {
+		return fmt.Errorf("%s has no members", spec)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	log.Printf("Team %s: %d members, %d at a time", spec, len(logins), concurrency)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		members []analyze.TeamMember
+		failed  []model.MemberError
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, login := range logins {
+		wg.Add(1)
+		go func(login string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				failed = append(failed, model.MemberError{Login: login, Error: "not collected: " + context.Cause(ctx).Error()})
+				return
+			}
+			m, err := collectMember(ctx, client, login, opts, filepath.Dir(out))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("[%s] ERROR: %v", login, err)
+				failed = append(failed, model.MemberError{Login: login, Error: err.Error()})
+				return
+			}
+			members = append(members, m)
+		}(login)
+	}
+	wg.Wait()
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Login < failed[j].Login })
+
+	var interrupted string
+	if ctx.Err() != nil {
+		interrupted = context.Cause(ctx).Error()
+	}
+	teamRecap := analyze.BuildTeamRecap(analyze.TeamInput{
+		Team:        spec.String(),
+		Window:      opts.window,
+		Location:    opts.loc,
+		Members:     members,
+		Failed:      failed,
+		Interrupted: interrupted,
+	})
+	teamRecap.Meta.APIUsage = client.RateLimitUsage()
+	if err := writeJSON(out, teamRecap); err != nil {
+		return err
+	}
+	fmt.Printf("OK: wrote %s (%d members, %d failed)\n", out, len(members), len(failed))
+	return nil
+}
+
+func collectMember(ctx context.Context, client *githubapi.Client, login string, opts options, dir string) (analyze.TeamMember, error) {
+	in, err := collectUser(ctx, client, login, opts)
+	if err != nil {
+		return analyze.TeamMember{}, err
+	}
+	recap := analyze.BuildRecap(in)
+
+	var (
+		reviewed  map[string]int
+		reviewErr string
+	)
+	if ctx.Err() == nil {
+		var cov model.SearchCoverage
+		reviewed, cov, err = client.SearchReviewedPRAuthors(ctx, login, opts.window.From, opts.window.To, opts.maxSearch)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("[%s] WARN: SearchReviewedPRAuthors: %v", login, err)
+			reviewErr = "reviewed PRs search: " + err.Error()
+		case err == nil && !cov.Complete:
+			warnIncomplete(login, "reviewed PRs search", cov)
+			reviewErr = fmt.Sprintf("reviewed PRs search incomplete: fetched %d of %d results", cov.Fetched, cov.TotalCount)
+		}
+	}
+
+	path := filepath.Join(dir, memberFileName(login, opts.window.Label))
+	if err := writeJSON(path, recap); err != nil {
+		return analyze.TeamMember{}, err
+	}
+	log.Printf("[%s] wrote %s", login, path)
+
+	return analyze.TeamMember{
+		Login:           login,
+		Recap:           recap,
+		Contributions:   in.Contributions,
+		ReviewedAuthors: reviewed,
+		ReviewsError:    reviewErr,
+	}, nil
+}
+
+func memberFileName(login, period string) string {
+	safe := strings.NewReplacer("/", "-", ".", "-", ":", "-").Replace(period)
+	return fmt.Sprintf("recap_%s_%s.json", safe, login)
+}
+
+// readMembersFile reads one login per line; blank lines and # comments are
+// ignored, as is a leading "@".
+func readMembersFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seen := map[string]bool{}
+	var logins []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		login := strings.TrimPrefix(strings.TrimSpace(line), "@")
+		if login == "" || seen[login] {
+			continue
+		}
+		seen[login] = true
+		logins = append(logins, login)
+	}
+	return logins, sc.Err()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadMembersFile(t *testing.T) {
+	tests := []struct {
+		name, content string
+		want          []string
+	}{
+		{"one per line", "alice\nbob\n", []string{"alice", "bob"}},
+		{"comments and blanks", "# platform team\nalice  # lead\n\n   \n#bob\ncarol", []string{"alice", "carol"}},
+		{"at signs and spaces", "  @alice\n\t@bob \n", []string{"alice", "bob"}},
+		{"duplicates keep the first", "bob\nalice\n@bob\n", []string{"bob", "alice"}},
+		{"CRLF", "alice\r\nbob\r\n", []string{"alice", "bob"}},
+		{"empty", "# nobody yet\n", nil},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "members.txt")
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readMembersFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := readMembersFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("missing file: no error")
+	}
+}
+
+func TestMemberFileName(t *testing.T) {
+	for period, want := range map[string]string{
+		"2025":                   "recap_2025_octocat.json",
+		"FY2025":                 "recap_FY2025_octocat.json",
+		"2025-01-01..2025-06-30": "recap_2025-01-01--2025-06-30_octocat.json",
+	} {
+		if got := memberFileName("octocat", period); got != want {
+			t.Errorf("memberFileName(%q) = %q, want %q", period, got, want)
+		}
+	}
+}
@@ -6,3 +6,5 @@ require (
 	github.com/google/go-github/v62 v62.0.0
 	golang.org/x/oauth2 v0.24.0
 )
+
+require github.com/google/go-querystring v1.1.0 // indirect
@@ -7,13 +7,89 @@ import (
 	"time"
 
 	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
 )
 
-func BuildRecap(user string, year int, cc *model.ContributionsCollection, prs []model.PRItem, issuesOpened, issuesClosed []model.IssueItem, langs model.LanguageBytes, growth *model.GrowthMetrics) *model.Recap {
+// Input is everything the collectors gathered for one recap.
+type Input struct {
+	User string
+	Window period.Window
+	// Location is the zone used for hours, weekdays and ISO weeks; nil means UTC.
+	Location *time.Location
+
+	Contributions *model.ContributionsCollection
+
+	PRs []model.PRItem
+	PRSearch model.SearchCoverage
+
+	IssuesOpened []model.IssueItem
+	IssuesClosed []model.IssueItem
+	IssuesOpenedSearch model.SearchCoverage
+	IssuesClosedSearch model.SearchCoverage
+
+	// Commits is set only when commit history was collected.
+	Commits []model.CommitItem
+	CommitsCollected bool
+
+	// ReviewsGiven / ReviewsReceived are set only when review timelines were
+	// collected.
+	ReviewsGiven []model.ReviewedPR
+	ReviewsReceived []model.ReviewedPR
+	ReviewsGivenSearch model.SearchCoverage
+	ReviewsReceivedSearch model.SearchCoverage
+	ReviewsCollected bool
+
+	// ChangedFiles are the files the user's PRs changed, for languages by
+	// lines changed; Languages is the per-repo byte-snapshot fallback.
+	ChangedFiles []model.ChangedFile
+	Languages []model.RepoLanguages
+	LanguageOptions LanguageOptions
+	// WeekdayStreak adds the weekday-only streak to the calendar.
+	WeekdayStreak bool
+	// Repos is the metadata of the top repos (see MaxTopRepos), by name.
+	Repos map[string]*model.RepoMeta
+	Growth *model.GrowthMetrics
+
+	// Failures are the sub-requests that failed in collectors that carried
+	// on without them; Skipped lists the flags that turned sections off.
+	Failures []model.Failure
+	Skipped []string
+	// Preflight is the credential check run before collecting, if any.
+	Preflight *model.Preflight
+
+	// Interrupted is set when collection was cancelled part-way; whatever
+	// was gathered is still reported, marked incomplete.
+	Interrupted string
+
+	// Now stamps Meta.GeneratedAt; zero means the current time. Golden tests
+	// pin it.
+	Now time.Time
+}
+
+// MaxTopRepos is how many repositories Recap.TopRepos lists.
+const MaxTopRepos = 12
+
+func BuildRecap(in Input) *model.Recap {
+	cc := in.Contributions
+	if cc == nil {
+		// Interrupted before contributions came back.
+		cc = &model.ContributionsCollection{}
+	}
+	prs := in.PRs
+	issuesOpened, issuesClosed := in.IssuesOpened, in.IssuesClosed
+	loc := in.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
 	var recap model.Recap
-	recap.Meta.User = user
-	recap.Meta.Year = year
-	recap.Meta.GeneratedAt = time.Now().UTC()
+	recap.Meta.User = in.User
+	recap.Meta.Year = in.Window.From.Year()
+	recap.Meta.From = in.Window.From
+	recap.Meta.To = in.Window.To
+	recap.Meta.Period = in.Window.Label
+	recap.Meta.Timezone = period.ZoneName(loc, in.Window.From)
+	recap.Meta.GeneratedAt = generatedAt(in.Now)
 
 	// Totals
 	recap.Totals.Commits = cc.TotalCommits
@@ -25,52 +101,30 @@ func BuildRecap(user string, year int, cc *model.ContributionsCollection, prs []
 	// Calendar + streak + most productive day/week
 	recap.Calendar.Days = cc.Calendar
 	recap.Calendar.LongestStreak = longestStreak(cc.Calendar)
+	asOf, open := streakEnd(in.Window, recap.Meta.GeneratedAt, loc)
+	recap.Calendar.Streaks = streakStats(cc.Calendar, asOf, open, in.WeekdayStreak)
 	recap.Calendar.MostProductiveDay = mostProductiveDay(cc.Calendar)
-	week, cnt := mostProductiveISOWeek(cc.Calendar)
+	week, cnt := mostProductiveISOWeek(cc.Calendar, loc)
 	recap.Calendar.MostProductiveISOWeek.ISOWeek = week
 	recap.Calendar.MostProductiveISOWeek.Count = cnt
+	recap.Seasons = seasons(cc.Calendar, asOf, in.Window)
 
 	// Top repos by activity
-	repoMap := map[string]*model.RepoContrib{}
-	mergeLite := func(m map[string]model.RepoContribLite, field string) {
-		for repo, v := range m {
-			r, ok := repoMap[repo]
-			if !ok {
-				r = &model.RepoContrib{Repo: repo, IsPrivate: v.IsPrivate}
-				repoMap[repo] = r
-			}
-			switch field {
-			case "commit":
-				r.CommitCount = v.Count
-			case "pr":
-				r.PRCount = v.Count
-			case "issue":
-				r.IssueCount = v.Count
-			case "review":
-				r.ReviewCount = v.Count
-			}
-		}
-	}
-	mergeLite(cc.ByRepoCommits, "commit")
-	mergeLite(cc.ByRepoPRs, "pr")
-	mergeLite(cc.ByRepoIssues, "issue")
-	mergeLite(cc.ByRepoReviews, "review")
-
-	top := make([]model.RepoContrib, 0, len(repoMap))
-	for _, r := range repoMap {
-		r.TotalActivity = r.CommitCount + r.PRCount + r.IssueCount + r.ReviewCount
-		top = append(top, *r)
+	top := repoActivity(cc)
+	if len(top) > MaxTopRepos {
+		top = top[:MaxTopRepos]
 	}
-	sort.Slice(top, func(i, j int) bool {
-		if top[i].TotalActivity == top[j].TotalActivity {
-			return top[i].Repo < top[j].Repo
+	for i := range top {
+		if m := in.Repos[top[i].Repo]; m != nil {
+			top[i].Description = m.Description
+			top[i].PrimaryLanguage = m.PrimaryLanguage
+			top[i].Topics = m.Topics
+			top[i].Stars = m.Stars
+			top[i].OwnerType = m.OwnerType
 		}
-		return top[i].TotalActivity > top[j].TotalActivity
-	})
-	if len(top) > 12 {
-		top = top[:12]
 	}
 	recap.TopRepos = top
+	recap.RepoCoverage = cc.RepoCoverage
 
 	// PR stats
 	recap.PRStats.Opened = len(prs)
@@ -82,8 +136,12 @@ func BuildRecap(user string, year int, cc *model.ContributionsCollection, prs []
 		p := prs[i]
 		if p.Merged && p.MergedAt != nil {
 			merged++
-			mergedCount++
-			totalMergeHours += p.MergedAt.Sub(p.CreatedAt).Hours()
+			// Clock skew can put mergedAt before createdAt; such PRs count
+			// as merged but stay out of the average.
+			if h := p.MergedAt.Sub(p.CreatedAt).Hours(); h >= 0 {
+				mergedCount++
+				totalMergeHours += h
+			}
 		}
 		// biggest PR by churn
 		score := p.Additions + p.Deletions
@@ -99,40 +157,144 @@ func BuildRecap(user string, year int, cc *model.ContributionsCollection, prs []
 	if mergedCount > 0 {
 		recap.PRStats.AvgTimeToMergeHours = totalMergeHours / float64(mergedCount)
 	}
+	recap.PRStats.MergeTime = mergeTimeStats(prs)
 	recap.PRStats.BiggestPR = biggest
-	recap.PRStats.TimeOfDayHistogram = hourHistogramPR(prs)
+	prTimes := make([]time.Time, 0, len(prs))
+	for _, p := range prs {
+		prTimes = append(prTimes, p.CreatedAt)
+	}
+	recap.PRStats.TimeOfDayHistogram = hourHistogram(prTimes, loc)
+	recap.PRStats.WeekdayHistogram = weekdayHistogram(prTimes, loc)
+	recap.PRStats.Search = in.PRSearch
 
 	// Issue stats
 	recap.IssueStats.Opened = len(issuesOpened)
 	recap.IssueStats.Closed = len(issuesClosed)
-	recap.IssueStats.TimeOfDayHistogram = hourHistogramIssues(issuesOpened)
+	issueTimes := make([]time.Time, 0, len(issuesOpened))
+	for _, it := range issuesOpened {
+		issueTimes = append(issueTimes, it.CreatedAt)
+	}
+	recap.IssueStats.TimeOfDayHistogram = hourHistogram(issueTimes, loc)
+	recap.IssueStats.WeekdayHistogram = weekdayHistogram(issueTimes, loc)
+	recap.IssueStats.SearchOpened = in.IssuesOpenedSearch
+	recap.IssueStats.SearchClosed = in.IssuesClosedSearch
+
+	var commits []model.CommitItem
+	if in.CommitsCollected {
+		recap.CommitStats = commitStats(in.Commits, loc)
+		commits = in.Commits
+	}
+	recap.PunchCard = punchCard(in.Window, loc, prs, issuesOpened, issuesClosed, commits)
 
 	// Reviews
 	recap.Reviews.Total = cc.TotalReviews
 	recap.Reviews.ByRepo = topReviewsByRepo(cc.ByRepoReviews)
+	if in.ReviewsCollected {
+		recap.Reviews.Depth = reviewDepth(in.User, in.Window.From, in.Window.To, in.ReviewsGiven, in.ReviewsReceived)
+		recap.Reviews.Depth.SearchGiven = in.ReviewsGivenSearch
+		recap.Reviews.Depth.SearchReceived = in.ReviewsReceivedSearch
+	}
 
 	// Languages
+	var langs model.LanguageBytes
+	if in.ChangedFiles != nil {
+		var monthly []model.LanguageMonth
+		langs, monthly = changedLanguages(in.ChangedFiles, in.Window, loc, in.LanguageOptions)
+		recap.Languages.Mode = LanguagesChanges
+		recap.Languages.Monthly = monthly
+		recap.Languages.Note = "Languages are weighted by the lines your PRs in " + in.Window.Label + " added and deleted, by file extension (vendored and generated files excluded). Commits pushed without a PR are not counted."
+	} else {
+		activity := map[string]int{}
+		for _, r := range repoActivity(cc) {
+			activity[r.Repo] = r.TotalActivity
+		}
+		langs, recap.Languages.ExcludedRepos = weightedLanguages(in.Languages, activity, in.LanguageOptions)
+		recap.Languages.Mode = LanguagesBytes
+		recap.Languages.Weighting = in.LanguageOptions.Weighting
+		if recap.Languages.Weighting == "" {
+			recap.Languages.Weighting = WeightRaw
+		}
+		recap.Languages.Note = "Language bytes are aggregated from the current repo language breakdown (not time-series), across repos you contributed to in " + in.Window.Label + ". " + weightingNotes[recap.Languages.Weighting]
+	}
+	recap.Languages.Ignored = in.LanguageOptions.Ignore
 	recap.Languages.WeightedBytes = langs
-	recap.Languages.Note = "Language bytes are aggregated from the current repo language breakdown (not time-series). Weighted by bytes across repos you contributed to in 2025."
 	recap.Languages.Top = topLanguages(langs, 10)
 
-	recap.Growth = growth
+	if in.Growth != nil {
+		recap.Growth = growth(in.Growth, in.Window, loc)
+	}
+
+	recap.Diagnostics = diagnostics(in)
+	recap.Meta.Complete = true
+	for _, sec := range recap.Diagnostics.Sections {
+		if sec.Collected && !sec.Complete {
+			recap.Meta.Complete = false
+		}
+	}
+	if in.Interrupted != "" {
+		recap.Meta.Complete = false
+		recap.Meta.Interrupted = in.Interrupted
+	}
 
 	return &recap
 }
 
-func longestStreak(days []model.ContributionDay) int {
-	cur := 0
-	best := 0
-	for _, d := range days {
-		if d.Count > 0 {
-			cur++
-			if cur > best { best = cur }
-		} else {
-			cur = 0
+// TopRepoNames returns the n repositories cc has the most activity in, as
+// "owner/name", most active first.
+func TopRepoNames(cc *model.ContributionsCollection, n int) []string {
+	if cc == nil || n <= 0 {
+		return nil
+	}
+	var names []string
+	for _, r := range repoActivity(cc) {
+		if len(names) == n {
+			break
 		}
+		names = append(names, r.Repo)
 	}
-	return best
+	return names
+}
+
+// repoActivity merges the per-repo breakdowns into one row per repository,
+// most active first.
+func repoActivity(cc *model.ContributionsCollection) []model.RepoContrib {
+	repoMap := map[string]*model.RepoContrib{}
+	mergeLite := func(m map[string]model.RepoContribLite, field string) {
+		for repo, v := range m {
+			r, ok := repoMap[repo]
+			if !ok {
+				r = &model.RepoContrib{Repo: repo, IsPrivate: v.IsPrivate}
+				repoMap[repo] = r
+			}
+			switch field {
+			case "commit":
+				r.CommitCount = v.Count
+			case "pr":
+				r.PRCount = v.Count
+			case "issue":
+				r.IssueCount = v.Count
+			case "review":
+				r.ReviewCount = v.Count
+			}
+		}
+	}
+	mergeLite(cc.ByRepoCommits, "commit")
+	mergeLite(cc.ByRepoPRs, "pr")
+	mergeLite(cc.ByRepoIssues, "issue")
+	mergeLite(cc.ByRepoReviews, "review")
+
+	top := make([]model.RepoContrib, 0, len(repoMap))
+	for _, r := range repoMap {
+		r.TotalActivity = r.CommitCount + r.PRCount + r.IssueCount + r.ReviewCount
+		top = append(top, *r)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].TotalActivity == top[j].TotalActivity {
+			return top[i].Repo < top[j].Repo
+		}
+		return top[i].TotalActivity > top[j].TotalActivity
+	})
+	return top
 }
 
 func mostProductiveDay(days []model.ContributionDay) model.ContributionDay {
@@ -146,10 +308,10 @@ func mostProductiveDay(days []model.ContributionDay) model.ContributionDay {
 	return best
 }
 
-func mostProductiveISOWeek(days []model.ContributionDay) (string, int) {
+func mostProductiveISOWeek(days []model.ContributionDay, loc *time.Location) (string, int) {
 	weekSum := map[string]int{}
 	for _, d := range days {
-		t, err := time.Parse("2006-01-02", d.Date)
+		t, err := time.ParseInLocation("2006-01-02", d.Date, loc)
 		if err != nil { continue }
 		y, w := t.ISOWeek()
 		key := fmtISOWeek(y, w)
@@ -158,7 +320,8 @@ func mostProductiveISOWeek(days []model.ContributionDay) (string, int) {
 	bestKey := ""
 	best := -1
 	for k, v := range weekSum {
-		if v > best {
+		// Ties go to the earliest week, as they do for days.
+		if v > best || (v == best && k < bestKey) {
 			best = v
 			bestKey = k
 		}
@@ -171,24 +334,25 @@ func fmtISOWeek(y, w int) string {
 	return fmt.Sprintf("%04d-W%02d", y, w)
 }
 
-func hourHistogramPR(prs []model.PRItem) map[string]int {
+func hourHistogram(ts []time.Time, loc *time.Location) map[string]int {
 	h := make(map[string]int, 24)
 	for i := 0; i < 24; i++ {
 		h[fmt2(i)] = 0
 	}
-	for _, p := range prs {
-		h[fmt2(p.CreatedAt.UTC().Hour())]++
+	for _, t := range ts {
+		h[fmt2(t.In(loc).Hour())]++
 	}
 	return h
 }
 
-func hourHistogramIssues(issues []model.IssueItem) map[string]int {
-	h := make(map[string]int, 24)
-	for i := 0; i < 24; i++ {
-		h[fmt2(i)] = 0
+// weekdayHistogram buckets by local weekday, keyed "Mon".."Sun".
+func weekdayHistogram(ts []time.Time, loc *time.Location) map[string]int {
+	h := make(map[string]int, 7)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		h[d.String()[:3]] = 0
 	}
-	for _, it := range issues {
-		h[fmt2(it.CreatedAt.UTC().Hour())]++
+	for _, t := range ts {
+		h[t.In(loc).Weekday().String()[:3]]++
 	}
 	return h
 }
@@ -213,11 +377,7 @@ func topReviewsByRepo(m map[string]model.RepoContribLite) []model.RepoContribLit
 	return out
 }
 
-func topLanguages(langs model.LanguageBytes, n int) []struct{
-	Language string `json:"language"`
-	Bytes int64 `json:"bytes"`
-	Share float64 `json:"share"`
-} {
+func topLanguages(langs model.LanguageBytes, n int) []model.LanguageShare {
 	type kv struct{ k string; v int64 }
 	var items []kv
 	var total int64
@@ -230,21 +390,21 @@ func topLanguages(langs model.LanguageBytes, n int) []struct{
 		return items[i].v > items[j].v
 	})
 	if n > len(items) { n = len(items) }
-	out := make([]struct{
-		Language string `json:"language"`
-		Bytes int64 `json:"bytes"`
-		Share float64 `json:"share"`
-	}, 0, n)
+	out := make([]model.LanguageShare, 0, n)
 	for i := 0; i < n; i++ {
 		share := 0.0
 		if total > 0 {
 			share = float64(items[i].v) / float64(total)
 		}
-		out = append(out, struct{
-			Language string `json:"language"`
-			Bytes int64 `json:"bytes"`
-			Share float64 `json:"share"`
-		}{Language: items[i].k, Bytes: items[i].v, Share: share})
+		out = append(out, model.LanguageShare{Language: items[i].k, Bytes: items[i].v, Share: share})
 	}
 	return out
 }
+
+// generatedAt is now in UTC, or the current time when now is zero.
+func generatedAt(now time.Time) time.Time {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return now.UTC()
+}
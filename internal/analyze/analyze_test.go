@@ -0,0 +1,399 @@
+package analyze
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestLongestStreak(t *testing.T) {
+	tests := []struct {
+		name string
+		days []model.ContributionDay
+		want int
+	}{
+		{"empty", nil, 0},
+		{"no activity", calendar("2025-01-01", 0, 0, 0), 0},
+		{"single day", calendar("2025-01-01", 0, 3, 0), 1},
+		{"every day", calendar("2025-01-01", 1, 2, 3, 4), 4},
+		{"longest of several", calendar("2025-01-01", 1, 1, 0, 1, 1, 1, 0, 1), 3},
+		{"runs to the end", calendar("2025-01-01", 0, 1, 0, 1, 1), 2},
+		{"ties", calendar("2025-01-01", 1, 1, 0, 1, 1), 2},
+		{"across leap day", calendar("2024-02-27", 1, 1, 1, 1, 1, 0), 5},
+		{"across new year", calendar("2024-12-30", 2, 2, 2, 2), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := longestStreak(tt.days); got != tt.want {
+				t.Errorf("longestStreak = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostProductiveDay(t *testing.T) {
+	tests := []struct {
+		name string
+		days []model.ContributionDay
+		want model.ContributionDay
+	}{
+		{"empty", nil, model.ContributionDay{}},
+		{"no activity", calendar("2025-01-01", 0, 0), model.ContributionDay{Date: "2025-01-01"}},
+		{"max", calendar("2025-01-01", 1, 9, 3), model.ContributionDay{Date: "2025-01-02", Count: 9}},
+		{"tie goes to the earliest", calendar("2025-01-01", 4, 9, 9), model.ContributionDay{Date: "2025-01-02", Count: 9}},
+		{"leap day", calendar("2024-02-28", 1, 7, 2), model.ContributionDay{Date: "2024-02-29", Count: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mostProductiveDay(tt.days); got != tt.want {
+				t.Errorf("mostProductiveDay = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostProductiveISOWeek(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		days      []model.ContributionDay
+		loc       *time.Location
+		wantWeek  string
+		wantCount int
+	}{
+		{"empty", nil, time.UTC, "", 0},
+		{"no activity", calendar("2025-03-03", 0, 0), time.UTC, "2025-W10", 0},
+		// Mon 2025-03-03 .. Sun 03-09 is W10; Mon 03-10 starts W11.
+		{"sums the week", calendar("2025-03-03", 1, 1, 1, 1, 1, 1, 1, 5), time.UTC, "2025-W10", 7},
+		{"later week wins", calendar("2025-03-03", 1, 0, 0, 0, 0, 0, 0, 5), time.UTC, "2025-W11", 5},
+		{"tie goes to the earliest", calendar("2025-03-03", 3, 0, 0, 0, 0, 0, 0, 3), time.UTC, "2025-W10", 3},
+		// Mon 2024-12-30 belongs to ISO year 2025.
+		{"ISO year differs", calendar("2024-12-28", 1, 1, 4, 4), time.UTC, "2025-W01", 8},
+		// 2020 has 53 ISO weeks; Fri 2021-01-01 is still 2020-W53.
+		{"week 53", calendar("2020-12-31", 2, 2, 2, 0, 1), time.UTC, "2020-W53", 6},
+		{"leap day", calendar("2024-02-26", 0, 0, 0, 9, 0, 0, 0, 1), time.UTC, "2024-W09", 9},
+		{"dates are local days", calendar("2025-03-09", 4, 1), tokyo, "2025-W10", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			week, count := mostProductiveISOWeek(tt.days, tt.loc)
+			if week != tt.wantWeek || count != tt.wantCount {
+				t.Errorf("mostProductiveISOWeek = %s %d, want %s %d", week, count, tt.wantWeek, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestTopLanguages(t *testing.T) {
+	tests := []struct {
+		name  string
+		langs model.LanguageBytes
+		n     int
+		want  []model.LanguageShare
+	}{
+		{"empty", nil, 10, []model.LanguageShare{}},
+		{"shares", model.LanguageBytes{"Go": 300, "Shell": 100}, 10, []model.LanguageShare{
+			{Language: "Go", Bytes: 300, Share: 0.75},
+			{Language: "Shell", Bytes: 100, Share: 0.25},
+		}},
+		{"cut shares are of the full total", model.LanguageBytes{"Go": 50, "Rust": 30, "C": 20}, 2, []model.LanguageShare{
+			{Language: "Go", Bytes: 50, Share: 0.5},
+			{Language: "Rust", Bytes: 30, Share: 0.3},
+		}},
+		{"ties by name", model.LanguageBytes{"Zig": 10, "Ada": 10, "Go": 10}, 10, []model.LanguageShare{
+			{Language: "Ada", Bytes: 10, Share: 1.0 / 3},
+			{Language: "Go", Bytes: 10, Share: 1.0 / 3},
+			{Language: "Zig", Bytes: 10, Share: 1.0 / 3},
+		}},
+		{"all zero", model.LanguageBytes{"Go": 0}, 10, []model.LanguageShare{{Language: "Go"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := topLanguages(tt.langs, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("topLanguages = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.Language != w.Language || g.Bytes != w.Bytes || math.Abs(g.Share-w.Share) > 1e-12 {
+					t.Errorf("[%d] = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestTopReposCut(t *testing.T) {
+	cc := &model.ContributionsCollection{ByRepoCommits: map[string]model.RepoContribLite{}, ByRepoReviews: map[string]model.RepoContribLite{}}
+	// 20 repos; repo-NN has NN commits, and repo-05 also 30 reviews.
+	for i := 1; i <= 20; i++ {
+		r := fmt.Sprintf("o/repo-%02d", i)
+		cc.ByRepoCommits[r] = model.RepoContribLite{Repo: r, Count: i}
+	}
+	cc.ByRepoReviews["o/repo-05"] = model.RepoContribLite{Repo: "o/repo-05", Count: 30}
+	// Ties are ordered by name.
+	cc.ByRepoCommits["o/a-tie"] = model.RepoContribLite{Repo: "o/a-tie", Count: 20}
+
+	recap := BuildRecap(Input{Contributions: cc})
+	if len(recap.TopRepos) != MaxTopRepos {
+		t.Fatalf("len(TopRepos) = %d, want %d", len(recap.TopRepos), MaxTopRepos)
+	}
+	want := []string{"o/repo-05", "o/a-tie", "o/repo-20", "o/repo-19"}
+	for i, w := range want {
+		if got := recap.TopRepos[i].Repo; got != w {
+			t.Errorf("TopRepos[%d] = %s, want %s", i, got, w)
+		}
+	}
+	if r := recap.TopRepos[0]; r.TotalActivity != 35 || r.CommitCount != 5 || r.ReviewCount != 30 {
+		t.Errorf("TopRepos[0] = %+v, want 5 commits + 30 reviews", r)
+	}
+	if last := recap.TopRepos[MaxTopRepos-1].Repo; last != "o/repo-11" {
+		t.Errorf("last top repo = %s, want o/repo-11", last)
+	}
+	if got := TopRepoNames(cc, 3); fmt.Sprint(got) != "[o/repo-05 o/a-tie o/repo-20]" {
+		t.Errorf("TopRepoNames = %v", got)
+	}
+	if got := TopRepoNames(nil, 3); got != nil {
+		t.Errorf("TopRepoNames(nil) = %v, want nil", got)
+	}
+}
+
+func TestMergeTime(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := created.Add(d)
+		return &t
+	}
+	pr := func(merged bool, mergedAt *time.Time, size int) model.PRItem {
+		return model.PRItem{Repo: "o/r", CreatedAt: created, Merged: merged, MergedAt: mergedAt, Additions: size}
+	}
+	tests := []struct {
+		name     string
+		prs      []model.PRItem
+		merged   int
+		rate     float64
+		avgHours float64
+		biggest  int // Additions of the biggest PR, -1 for none
+	}{
+		{"none", nil, 0, 0, 0, -1},
+		{"unmerged", []model.PRItem{pr(false, nil, 1)}, 0, 0, 0, 1},
+		{"average", []model.PRItem{pr(true, at(2*time.Hour), 1), pr(true, at(4*time.Hour), 2), pr(false, nil, 3)}, 2, 2.0 / 3, 3, 3},
+		{"merged instantly", []model.PRItem{pr(true, at(0), 1)}, 1, 1, 0, 1},
+		// Clock skew: merged counts, the negative duration is left out.
+		{"merged before created", []model.PRItem{pr(true, at(-90*time.Second), 1), pr(true, at(6*time.Hour), 1)}, 2, 1, 6, 1},
+		{"only skewed", []model.PRItem{pr(true, at(-time.Minute), 1)}, 1, 1, 0, 1},
+		// Merged only counts with a timestamp.
+		{"merged without timestamp", []model.PRItem{pr(true, nil, 1)}, 0, 0, 0, 1},
+		{"first of equal size is biggest", []model.PRItem{pr(false, nil, 5), pr(true, at(time.Hour), 5)}, 1, 0.5, 1, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := BuildRecap(Input{Contributions: &model.ContributionsCollection{}, PRs: tt.prs}).PRStats
+			if s.Opened != len(tt.prs) || s.Merged != tt.merged || math.Abs(s.MergeRate-tt.rate) > 1e-12 || math.Abs(s.AvgTimeToMergeHours-tt.avgHours) > 1e-12 {
+				t.Errorf("opened %d merged %d rate %v avg %vh, want %d %d %v %vh", s.Opened, s.Merged, s.MergeRate, s.AvgTimeToMergeHours, len(tt.prs), tt.merged, tt.rate, tt.avgHours)
+			}
+			switch {
+			case tt.biggest < 0 && s.BiggestPR != nil:
+				t.Errorf("BiggestPR = %+v, want none", s.BiggestPR)
+			case tt.biggest >= 0 && (s.BiggestPR == nil || s.BiggestPR.Additions != tt.biggest):
+				t.Errorf("BiggestPR = %+v, want %d additions", s.BiggestPR, tt.biggest)
+			case tt.name == "first of equal size is biggest" && s.BiggestPR.Merged:
+				t.Errorf("BiggestPR is the second of two equal PRs")
+			}
+		})
+	}
+}
+
+func TestBuildRecapEmpty(t *testing.T) {
+	for name, cc := range map[string]*model.ContributionsCollection{
+		"nil contributions":   nil,
+		"empty contributions": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := BuildRecap(Input{User: "octocat", Window: yearWindow(2025, time.UTC), Contributions: cc, Interrupted: "interrupted"})
+			if r.Totals.Overall != 0 || r.Calendar.LongestStreak != 0 || len(r.TopRepos) != 0 || len(r.Languages.Top) != 0 {
+				t.Errorf("empty input gave %+v", r)
+			}
+			if r.Meta.Complete {
+				t.Error("interrupted recap marked complete")
+			}
+			if r.Meta.Timezone != "UTC" || r.Meta.GeneratedAt.IsZero() {
+				t.Errorf("meta = %+v", r.Meta)
+			}
+		})
+	}
+}
+
+func TestGeneratedAt(t *testing.T) {
+	now := time.Date(2026, 1, 15, 21, 0, 0, 0, time.FixedZone("JST", 9*3600))
+	r := BuildRecap(Input{Contributions: &model.ContributionsCollection{}, Now: now})
+	if !r.Meta.GeneratedAt.Equal(now) || r.Meta.GeneratedAt.Location() != time.UTC {
+		t.Errorf("GeneratedAt = %v, want %v in UTC", r.Meta.GeneratedAt, now)
+	}
+}
+
+// TestProperties checks invariants of BuildRecap over many synthetic inputs.
+func TestProperties(t *testing.T) {
+	zones := []string{"UTC", "America/Los_Angeles", "Asia/Kolkata", "Pacific/Auckland"}
+	for seed := int64(1); seed <= 200; seed++ {
+		loc, err := time.LoadLocation(zones[seed%int64(len(zones))])
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 2024 and 2028 are leap years.
+		w := yearWindow(2024+int(seed%5), loc)
+		in := genInput(seed, w, loc)
+		if seed%2 == 0 {
+			in.LanguageOptions = LanguageOptions{Weighting: []string{WeightRaw, WeightActivity, WeightEqual}[seed%3]}
+		}
+		r := BuildRecap(in)
+		fail := func(format string, args ...any) {
+			t.Helper()
+			t.Errorf("seed %d: "+format, append([]any{seed}, args...)...)
+		}
+
+		active := 0
+		for _, d := range in.Contributions.Calendar {
+			if d.Count > 0 {
+				active++
+			}
+		}
+		if r.Calendar.LongestStreak > active {
+			fail("longest streak %d > %d active days", r.Calendar.LongestStreak, active)
+		}
+		if active > 0 && r.Calendar.LongestStreak < 1 {
+			fail("no streak with %d active days", active)
+		}
+		if got, want := len(r.Calendar.Days), w.To.YearDay(); got != want {
+			fail("%d calendar days, want %d", got, want)
+		}
+		if r.Calendar.MostProductiveISOWeek.Count < r.Calendar.MostProductiveDay.Count {
+			fail("best week %d < best day %d", r.Calendar.MostProductiveISOWeek.Count, r.Calendar.MostProductiveDay.Count)
+		}
+
+		var monthly, quarterly, monthDays int
+		for _, m := range r.Seasons.Monthly {
+			monthly += m.Contributions
+			monthDays += m.Days
+		}
+		for _, q := range r.Seasons.Quarters {
+			quarterly += q.Contributions
+		}
+		if monthly != quarterly {
+			fail("months sum to %d, quarters to %d", monthly, quarterly)
+		}
+		if monthDays > len(r.Calendar.Days) {
+			fail("months cover %d of %d days", monthDays, len(r.Calendar.Days))
+		}
+		if b, q := r.Seasons.BusiestMonth, r.Seasons.QuietestMonth; b != nil && b.Contributions < q.Contributions {
+			fail("busiest month %+v quieter than %+v", b, q)
+		}
+
+		cells := 0
+		for _, row := range r.PunchCard.Cells {
+			for _, n := range row {
+				cells += n
+			}
+		}
+		if cells != r.PunchCard.Total || histogramSum(r.PunchCard.Sources) != r.PunchCard.Total {
+			fail("punch card cells %d, sources %v, total %d", cells, r.PunchCard.Sources, r.PunchCard.Total)
+		}
+		if n := r.PunchCard.Sources["pr_created"]; n != len(in.PRs) {
+			fail("punch card holds %d of %d PRs", n, len(in.PRs))
+		}
+
+		if r.Totals.Overall != r.Totals.Commits+r.Totals.PullRequests+r.Totals.Issues+r.Totals.Reviews {
+			fail("overall %d is not the sum of the totals %+v", r.Totals.Overall, r.Totals)
+		}
+		if len(r.TopRepos) > MaxTopRepos {
+			fail("%d top repos", len(r.TopRepos))
+		}
+		for i := 1; i < len(r.TopRepos); i++ {
+			if r.TopRepos[i].TotalActivity > r.TopRepos[i-1].TotalActivity {
+				fail("top repos out of order at %d", i)
+			}
+		}
+
+		if r.PRStats.MergeRate < 0 || r.PRStats.MergeRate > 1 {
+			fail("merge rate %v", r.PRStats.MergeRate)
+		}
+		if r.PRStats.AvgTimeToMergeHours < 0 {
+			fail("average time to merge %vh", r.PRStats.AvgTimeToMergeHours)
+		}
+		if mt := r.PRStats.MergeTime; mt != nil {
+			if mt.Count > r.PRStats.Merged {
+				fail("merge time of %d PRs, %d merged", mt.Count, r.PRStats.Merged)
+			}
+			if !(0 <= mt.MinHours && mt.MinHours <= mt.MedianHours && mt.MedianHours <= mt.P75Hours && mt.P75Hours <= mt.P90Hours && mt.P90Hours <= mt.P99Hours && mt.P99Hours <= mt.MaxHours) {
+				fail("merge time percentiles out of order: %+v", mt)
+			}
+			if mt.MinHours > r.PRStats.AvgTimeToMergeHours || r.PRStats.AvgTimeToMergeHours > mt.MaxHours {
+				fail("average %vh outside [%v, %v]", r.PRStats.AvgTimeToMergeHours, mt.MinHours, mt.MaxHours)
+			}
+			hist, sizes := 0, 0
+			for _, b := range mt.Histogram {
+				hist += b.Count
+			}
+			for _, g := range mt.BySize {
+				sizes += g.Count
+			}
+			if hist != mt.Count || sizes != mt.Count {
+				fail("histogram holds %d and size buckets %d of %d merged PRs", hist, sizes, mt.Count)
+			}
+		}
+		if n := histogramSum(r.PRStats.TimeOfDayHistogram); n != len(in.PRs) {
+			fail("hour histogram holds %d of %d PRs", n, len(in.PRs))
+		}
+		if n := histogramSum(r.PRStats.WeekdayHistogram); n != len(in.PRs) {
+			fail("weekday histogram holds %d of %d PRs", n, len(in.PRs))
+		}
+
+		var sum float64
+		for _, l := range r.Languages.Top {
+			sum += l.Share
+		}
+		if len(r.Languages.Top) == len(r.Languages.WeightedBytes) && len(r.Languages.Top) > 0 && math.Abs(sum-1) > 1e-9 {
+			fail("language shares sum to %v", sum)
+		}
+		if sum > 1+1e-9 {
+			fail("language shares sum to %v", sum)
+		}
+	}
+}
+
+// TestTopLanguagesShares checks that shares of the full list sum to 1 and a
+// cut list never exceeds it.
+func TestTopLanguagesShares(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 500; i++ {
+		langs := model.LanguageBytes{}
+		for j := rng.Intn(20); j >= 0; j-- {
+			langs[fmt.Sprintf("L%d", rng.Intn(30))] += int64(1 + rng.Intn(1<<40))
+		}
+		var all, cut float64
+		for _, l := range topLanguages(langs, len(langs)) {
+			all += l.Share
+		}
+		for _, l := range topLanguages(langs, 3) {
+			cut += l.Share
+		}
+		if math.Abs(all-1) > 1e-9 || cut > all+1e-12 {
+			t.Fatalf("%v: shares sum to %v (top 3: %v)", langs, all, cut)
+		}
+	}
+}
+
+func histogramSum(h map[string]int) int {
+	n := 0
+	for _, v := range h {
+		n += v
+	}
+	return n
+}
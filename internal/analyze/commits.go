@@ -0,0 +1,80 @@
+package analyze
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// conventionalRe matches Conventional Commits headlines: type(scope)!: subject.
+var conventionalRe = regexp.MustCompile(`^([a-z]+)(\([^)]*\))?!?: `)
+
+func commitStats(commits []model.CommitItem, loc *time.Location) *model.CommitStats {
+	var st model.CommitStats
+	st.Total = len(commits)
+	st.Messages.ConventionalTypes = map[string]int{}
+
+	times := make([]time.Time, 0, len(commits))
+	byRepo := map[string]*model.CommitRepoStats{}
+	var headlineLen, messageLen int
+	for i := range commits {
+		c := commits[i]
+		times = append(times, c.CommittedAt)
+		st.Additions += c.Additions
+		st.Deletions += c.Deletions
+
+		r, ok := byRepo[c.Repo]
+		if !ok {
+			r = &model.CommitRepoStats{Repo: c.Repo}
+			byRepo[c.Repo] = r
+		}
+		r.Commits++
+		r.Additions += c.Additions
+		r.Deletions += c.Deletions
+
+		if st.LargestCommit == nil || c.Additions+c.Deletions > st.LargestCommit.Additions+st.LargestCommit.Deletions {
+			cp := c
+			st.LargestCommit = &cp
+		}
+
+		headlineLen += utf8.RuneCountInString(c.Headline)
+		messageLen += c.MessageLength
+		if c.HasBody {
+			st.Messages.WithBody++
+		}
+		if strings.HasPrefix(c.Headline, "Merge ") {
+			st.Messages.Merges++
+		}
+		if m := conventionalRe.FindStringSubmatch(c.Headline); m != nil {
+			st.Messages.Conventional++
+			st.Messages.ConventionalTypes[m[1]]++
+		}
+	}
+	if st.Total > 0 {
+		st.Messages.AvgHeadlineLength = float64(headlineLen) / float64(st.Total)
+		st.Messages.AvgMessageLength = float64(messageLen) / float64(st.Total)
+	}
+
+	st.TimeOfDayHistogram = hourHistogram(times, loc)
+	st.WeekdayHistogram = weekdayHistogram(times, loc)
+
+	st.Repos = len(byRepo)
+	st.ByRepo = make([]model.CommitRepoStats, 0, len(byRepo))
+	for _, r := range byRepo {
+		st.ByRepo = append(st.ByRepo, *r)
+	}
+	sort.Slice(st.ByRepo, func(i, j int) bool {
+		if st.ByRepo[i].Commits == st.ByRepo[j].Commits {
+			return st.ByRepo[i].Repo < st.ByRepo[j].Repo
+		}
+		return st.ByRepo[i].Commits > st.ByRepo[j].Commits
+	})
+	if len(st.ByRepo) > MaxTopRepos {
+		st.ByRepo = st.ByRepo[:MaxTopRepos]
+	}
+	return &st
+}
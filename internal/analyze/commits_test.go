@@ -0,0 +1,139 @@
+package analyze
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestCommitStats(t *testing.T) {
+	commit := func(repo, when, headline string, length, add, del int, body bool) model.CommitItem {
+		return model.CommitItem{Repo: repo, SHA: repo + when, CommittedAt: at(when), Headline: headline, MessageLength: length, Additions: add, Deletions: del, HasBody: body}
+	}
+	tests := []struct {
+		name    string
+		commits []model.CommitItem
+		check   func(t *testing.T, st *model.CommitStats)
+	}{
+		{
+			name: "no commits",
+			check: func(t *testing.T, st *model.CommitStats) {
+				if st.Total != 0 || st.Repos != 0 || st.LargestCommit != nil || st.Messages.AvgMessageLength != 0 || len(st.ByRepo) != 0 {
+					t.Errorf("stats = %+v", st)
+				}
+				if len(st.TimeOfDayHistogram) != 24 || len(st.WeekdayHistogram) != 7 || st.Messages.ConventionalTypes == nil {
+					t.Errorf("empty histograms = %v %v", st.TimeOfDayHistogram, st.WeekdayHistogram)
+				}
+			},
+		},
+		{
+			name: "messages",
+			commits: []model.CommitItem{
+				commit("acme/api", "2025-03-03 09:00", "feat(api): add endpoint", 23, 10, 2, false),
+				commit("acme/api", "2025-03-04 10:00", "fix!: drop v1", 40, 1, 1, true),
+				commit("acme/api", "2025-03-05 11:00", "Merge pull request #4 from acme/fix", 35, 0, 0, true),
+				// Headlines are measured in characters.
+				commit("acme/api", "2025-03-06 12:00", "修正: 日本語", 7, 3, 0, false),
+				// Not conventional: capitalised type, no space after the colon.
+				commit("acme/api", "2025-03-07 13:00", "Fix: typo", 9, 1, 1, false),
+				commit("acme/api", "2025-03-08 14:00", "docs:readme", 11, 1, 0, false),
+			},
+			check: func(t *testing.T, st *model.CommitStats) {
+				m := st.Messages
+				if m.Conventional != 2 || !reflect.DeepEqual(m.ConventionalTypes, map[string]int{"feat": 1, "fix": 1}) {
+					t.Errorf("conventional = %d %v", m.Conventional, m.ConventionalTypes)
+				}
+				if m.Merges != 1 || m.WithBody != 2 {
+					t.Errorf("merges %d, with body %d", m.Merges, m.WithBody)
+				}
+				if want := float64(23+13+35+7+9+11) / 6; m.AvgHeadlineLength != want {
+					t.Errorf("AvgHeadlineLength = %v, want %v", m.AvgHeadlineLength, want)
+				}
+				if want := float64(23+40+35+7+9+11) / 6; m.AvgMessageLength != want {
+					t.Errorf("AvgMessageLength = %v, want %v", m.AvgMessageLength, want)
+				}
+			},
+		},
+		{
+			name: "churn and largest commit",
+			commits: []model.CommitItem{
+				commit("acme/api", "2025-05-01 10:00", "a", 1, 100, 20, false),
+				commit("acme/web", "2025-05-02 10:00", "b", 1, 60, 60, false),
+				commit("acme/web", "2025-05-03 10:00", "c", 1, 5, 0, false),
+				commit("acme/cli", "2025-05-04 10:00", "d", 1, 0, 130, false),
+			},
+			check: func(t *testing.T, st *model.CommitStats) {
+				if st.Total != 4 || st.Repos != 3 || st.Additions != 165 || st.Deletions != 210 {
+					t.Errorf("totals = %d commits, %d repos, +%d -%d", st.Total, st.Repos, st.Additions, st.Deletions)
+				}
+				if st.LargestCommit == nil || st.LargestCommit.Repo != "acme/cli" {
+					t.Errorf("LargestCommit = %+v", st.LargestCommit)
+				}
+				want := []model.CommitRepoStats{
+					{Repo: "acme/web", Commits: 2, Additions: 65, Deletions: 60},
+					{Repo: "acme/api", Commits: 1, Additions: 100, Deletions: 20},
+					{Repo: "acme/cli", Commits: 1, Deletions: 130},
+				}
+				if !reflect.DeepEqual(st.ByRepo, want) {
+					t.Errorf("ByRepo = %+v, want %+v", st.ByRepo, want)
+				}
+			},
+		},
+		{
+			name: "largest commit tie",
+			commits: []model.CommitItem{
+				commit("acme/api", "2025-05-01 10:00", "a", 1, 100, 20, false),
+				commit("acme/web", "2025-05-02 10:00", "b", 1, 60, 60, false),
+			},
+			check: func(t *testing.T, st *model.CommitStats) {
+				if st.LargestCommit == nil || st.LargestCommit.Repo != "acme/api" {
+					t.Errorf("LargestCommit = %+v, want the first of the tie", st.LargestCommit)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, commitStats(tt.commits, time.UTC))
+		})
+	}
+}
+
+func TestCommitStatsHistograms(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := []model.CommitItem{
+		{Repo: "acme/api", CommittedAt: at("2025-03-03 09:30")}, // Monday
+		{Repo: "acme/api", CommittedAt: at("2025-03-03 09:59")},
+		{Repo: "acme/api", CommittedAt: at("2025-03-04 02:00")}, // Monday 18:00 in LA
+	}
+	st := commitStats(commits, time.UTC)
+	if st.TimeOfDayHistogram["09"] != 2 || st.TimeOfDayHistogram["02"] != 1 || st.WeekdayHistogram["Mon"] != 2 || st.WeekdayHistogram["Tue"] != 1 {
+		t.Errorf("UTC: %v %v", st.TimeOfDayHistogram, st.WeekdayHistogram)
+	}
+	st = commitStats(commits, la)
+	if st.TimeOfDayHistogram["01"] != 2 || st.TimeOfDayHistogram["18"] != 1 || st.WeekdayHistogram["Mon"] != 3 {
+		t.Errorf("LA: %v %v", st.TimeOfDayHistogram, st.WeekdayHistogram)
+	}
+}
+
+func TestCommitStatsByRepoCap(t *testing.T) {
+	var commits []model.CommitItem
+	for i := 0; i < MaxTopRepos+5; i++ {
+		for j := 0; j <= i; j++ {
+			commits = append(commits, model.CommitItem{Repo: fmt.Sprintf("acme/r%02d", i), CommittedAt: at("2025-01-01 00:00")})
+		}
+	}
+	st := commitStats(commits, time.UTC)
+	if st.Repos != MaxTopRepos+5 || len(st.ByRepo) != MaxTopRepos {
+		t.Fatalf("%d repos, %d listed; want %d listed", st.Repos, len(st.ByRepo), MaxTopRepos)
+	}
+	if st.ByRepo[0].Repo != fmt.Sprintf("acme/r%02d", MaxTopRepos+4) || st.ByRepo[MaxTopRepos-1].Commits != 6 {
+		t.Errorf("ByRepo = %+v", st.ByRepo)
+	}
+}
@@ -0,0 +1,133 @@
+package analyze
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// maxLanguageShifts caps how many language share changes Compare reports.
+const maxLanguageShifts = 15
+
+// Compare describes how cur changed relative to prev: deltas for totals, merge
+// rate, merge time and streak, language share shifts (when both attribute
+// languages the same way), and repositories that entered or left the top list.
+func Compare(prev, cur *model.Recap) *model.Comparison {
+	var c model.Comparison
+	c.Previous.Period = prev.Meta.Period
+	c.Previous.From = prev.Meta.From
+	c.Previous.To = prev.Meta.To
+
+	c.Totals = map[string]model.Delta{
+		"commits":       delta(prev.Totals.Commits, cur.Totals.Commits),
+		"pull_requests": delta(prev.Totals.PullRequests, cur.Totals.PullRequests),
+		"issues":        delta(prev.Totals.Issues, cur.Totals.Issues),
+		"reviews":       delta(prev.Totals.Reviews, cur.Totals.Reviews),
+		"overall":       delta(prev.Totals.Overall, cur.Totals.Overall),
+	}
+	c.MergeRate = delta(prev.PRStats.MergeRate, cur.PRStats.MergeRate)
+	c.AvgTimeToMergeHours = delta(prev.PRStats.AvgTimeToMergeHours, cur.PRStats.AvgTimeToMergeHours)
+	c.LongestStreak = delta(prev.Calendar.LongestStreak, cur.Calendar.LongestStreak)
+	if languageMode(prev) == languageMode(cur) {
+		c.Languages = languageShifts(prev.Languages.WeightedBytes, cur.Languages.WeightedBytes, maxLanguageShifts)
+	}
+
+	wasTop := map[string]bool{}
+	for _, r := range prev.TopRepos {
+		wasTop[r.Repo] = true
+	}
+	isTop := map[string]bool{}
+	for _, r := range cur.TopRepos {
+		isTop[r.Repo] = true
+		if wasTop[r.Repo] {
+			c.TopRepos.Stayed = append(c.TopRepos.Stayed, r.Repo)
+		} else {
+			c.TopRepos.Entered = append(c.TopRepos.Entered, r.Repo)
+		}
+	}
+	for _, r := range prev.TopRepos {
+		if !isTop[r.Repo] {
+			c.TopRepos.Left = append(c.TopRepos.Left, r.Repo)
+		}
+	}
+
+	return &c
+}
+
+func delta[T int | float64](prev, cur T) model.Delta {
+	d := model.Delta{
+		Previous: float64(prev),
+		Current:  float64(cur),
+		Change:   float64(cur) - float64(prev),
+	}
+	if prev != 0 {
+		pct := d.Change / math.Abs(d.Previous) * 100
+		d.PercentChange = &pct
+	}
+	return d
+}
+
+// languageMode is how r attributed languages, weighting included; recaps
+// written before modes existed used raw repo bytes. Shares from different
+// modes are not compared.
+func languageMode(r *model.Recap) string {
+	switch r.Languages.Mode {
+	case "", LanguagesBytes:
+		if r.Languages.Weighting == "" {
+			return LanguagesBytes + "/" + WeightRaw
+		}
+		return LanguagesBytes + "/" + r.Languages.Weighting
+	}
+	return r.Languages.Mode
+}
+
+// languageShifts compares each language's share of the total bytes and
+// returns the n largest moves, in percentage points.
+func languageShifts(prev, cur model.LanguageBytes, n int) []model.LanguageShift {
+	prevShare, curShare := shares(prev), shares(cur)
+	langs := map[string]bool{}
+	for k := range prevShare {
+		langs[k] = true
+	}
+	for k := range curShare {
+		langs[k] = true
+	}
+
+	out := make([]model.LanguageShift, 0, len(langs))
+	for lang := range langs {
+		p, c := prevShare[lang], curShare[lang]
+		out = append(out, model.LanguageShift{
+			Language:      lang,
+			PreviousShare: p,
+			CurrentShare:  c,
+			ChangePoints:  (c - p) * 100,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ai, aj := math.Abs(out[i].ChangePoints), math.Abs(out[j].ChangePoints)
+		if ai == aj {
+			return out[i].Language < out[j].Language
+		}
+		return ai > aj
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+func shares(langs model.LanguageBytes) map[string]float64 {
+	var total int64
+	for _, v := range langs {
+		total += v
+	}
+	out := make(map[string]float64, len(langs))
+	if total == 0 {
+		return out
+	}
+	for k, v := range langs {
+		out[k] = float64(v) / float64(total)
+	}
+	return out
+}
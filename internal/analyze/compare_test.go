@@ -0,0 +1,146 @@
+package analyze
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestDelta(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name      string
+		prev, cur float64
+		want      model.Delta
+	}{
+		{"growth", 40, 50, model.Delta{Previous: 40, Current: 50, Change: 10, PercentChange: pct(25)}},
+		{"drop", 50, 40, model.Delta{Previous: 50, Current: 40, Change: -10, PercentChange: pct(-20)}},
+		{"unchanged", 7, 7, model.Delta{Previous: 7, Current: 7, PercentChange: pct(0)}},
+		// Nothing to compare a percentage against.
+		{"from zero", 0, 12, model.Delta{Current: 12, Change: 12}},
+		{"zero to zero", 0, 0, model.Delta{}},
+		// A negative base still reports the direction of the change.
+		{"negative base", -4, -2, model.Delta{Previous: -4, Current: -2, Change: 2, PercentChange: pct(50)}},
+	}
+	for _, tt := range tests {
+		got := delta(tt.prev, tt.cur)
+		if got.Previous != tt.want.Previous || got.Current != tt.want.Current || got.Change != tt.want.Change {
+			t.Errorf("%s: delta = %+v, want %+v", tt.name, got, tt.want)
+		}
+		switch {
+		case tt.want.PercentChange == nil && got.PercentChange != nil:
+			t.Errorf("%s: PercentChange = %v, want nil", tt.name, *got.PercentChange)
+		case tt.want.PercentChange != nil && (got.PercentChange == nil || math.Abs(*got.PercentChange-*tt.want.PercentChange) > 1e-9):
+			t.Errorf("%s: PercentChange = %v, want %v", tt.name, got.PercentChange, *tt.want.PercentChange)
+		}
+	}
+	if d := delta(0, 5); d.PercentChange != nil {
+		t.Errorf("int delta from zero: PercentChange = %v", *d.PercentChange)
+	}
+}
+
+// compareRecap is a recap with just the fields Compare reads.
+func compareRecap(commits int, mode, weighting string, langs model.LanguageBytes, top ...string) *model.Recap {
+	r := &model.Recap{}
+	r.Totals.Commits = commits
+	r.Totals.Overall = commits
+	r.Languages.Mode = mode
+	r.Languages.Weighting = weighting
+	r.Languages.WeightedBytes = langs
+	for _, repo := range top {
+		r.TopRepos = append(r.TopRepos, model.RepoContrib{Repo: repo})
+	}
+	return r
+}
+
+func TestCompare(t *testing.T) {
+	prev := compareRecap(0, LanguagesChanges, "", model.LanguageBytes{"Go": 600, "Shell": 200, "Python": 200}, "acme/api", "acme/web", "acme/old")
+	prev.Meta.Period = "2024"
+	cur := compareRecap(30, LanguagesChanges, "", model.LanguageBytes{"Go": 500, "Rust": 500}, "acme/web", "acme/new", "acme/api")
+	c := Compare(prev, cur)
+
+	if c.Previous.Period != "2024" {
+		t.Errorf("Previous.Period = %q", c.Previous.Period)
+	}
+	if d := c.Totals["commits"]; d.Change != 30 || d.PercentChange != nil {
+		t.Errorf("commits from zero = %+v, want +30 and no percentage", d)
+	}
+
+	// Go 60% -> 50%, Python and Shell 20% -> 0, Rust 0 -> 50%; largest
+	// moves first, ties by name.
+	want := []model.LanguageShift{
+		{Language: "Rust", PreviousShare: 0, CurrentShare: 0.5, ChangePoints: 50},
+		{Language: "Python", PreviousShare: 0.2, CurrentShare: 0, ChangePoints: -20},
+		{Language: "Shell", PreviousShare: 0.2, CurrentShare: 0, ChangePoints: -20},
+		{Language: "Go", PreviousShare: 0.6, CurrentShare: 0.5, ChangePoints: -10},
+	}
+	if len(c.Languages) != len(want) {
+		t.Fatalf("Languages = %+v, want %+v", c.Languages, want)
+	}
+	for i, s := range c.Languages {
+		w := want[i]
+		if s.Language != w.Language || math.Abs(s.PreviousShare-w.PreviousShare) > 1e-9 || math.Abs(s.CurrentShare-w.CurrentShare) > 1e-9 || math.Abs(s.ChangePoints-w.ChangePoints) > 1e-9 {
+			t.Errorf("Languages[%d] = %+v, want %+v", i, s, w)
+		}
+	}
+
+	if !reflect.DeepEqual(c.TopRepos.Entered, []string{"acme/new"}) {
+		t.Errorf("Entered = %v", c.TopRepos.Entered)
+	}
+	if !reflect.DeepEqual(c.TopRepos.Left, []string{"acme/old"}) {
+		t.Errorf("Left = %v", c.TopRepos.Left)
+	}
+	// In the current ranking's order.
+	if !reflect.DeepEqual(c.TopRepos.Stayed, []string{"acme/web", "acme/api"}) {
+		t.Errorf("Stayed = %v", c.TopRepos.Stayed)
+	}
+}
+
+func TestCompareLanguageModes(t *testing.T) {
+	langs := model.LanguageBytes{"Go": 1}
+	other := model.LanguageBytes{"Rust": 1}
+	tests := []struct {
+		name      string
+		prev, cur *model.Recap
+		compared  bool
+	}{
+		{"same mode", compareRecap(1, LanguagesChanges, "", langs), compareRecap(1, LanguagesChanges, "", other), true},
+		{"changes vs bytes", compareRecap(1, LanguagesBytes, WeightRaw, langs), compareRecap(1, LanguagesChanges, "", other), false},
+		{"different weighting", compareRecap(1, LanguagesBytes, WeightRaw, langs), compareRecap(1, LanguagesBytes, WeightEqual, other), false},
+		// Recaps from before modes existed used raw bytes.
+		{"legacy vs raw bytes", compareRecap(1, "", "", langs), compareRecap(1, LanguagesBytes, WeightRaw, other), true},
+		{"legacy vs changes", compareRecap(1, "", "", langs), compareRecap(1, LanguagesChanges, "", other), false},
+	}
+	for _, tt := range tests {
+		c := Compare(tt.prev, tt.cur)
+		if compared := len(c.Languages) > 0; compared != tt.compared {
+			t.Errorf("%s: Languages = %+v, want compared=%v", tt.name, c.Languages, tt.compared)
+		}
+		// The rest of the comparison does not depend on the mode.
+		if c.Totals["commits"].Current != 1 {
+			t.Errorf("%s: Totals = %+v", tt.name, c.Totals)
+		}
+	}
+}
+
+func TestLanguageShiftsCap(t *testing.T) {
+	prev, cur := model.LanguageBytes{}, model.LanguageBytes{}
+	for i, lang := range []string{"A", "B", "C", "D", "E"} {
+		prev[lang] = int64(10 + i)
+		cur[lang] = int64(10 + 4 - i)
+	}
+	got := languageShifts(prev, cur, 3)
+	if len(got) != 3 {
+		t.Fatalf("got %d shifts, want 3", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if math.Abs(got[i].ChangePoints) > math.Abs(got[i-1].ChangePoints) {
+			t.Errorf("shifts not ranked: %+v", got)
+		}
+	}
+	if got := languageShifts(nil, nil, 3); len(got) != 0 {
+		t.Errorf("no languages: %+v", got)
+	}
+}
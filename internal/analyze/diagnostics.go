@@ -0,0 +1,120 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// diagnostics reports, per section, whether it was collected and whether it
+// is complete, together with every failed sub-request behind the answer.
+func diagnostics(in Input) model.Diagnostics {
+	d := model.Diagnostics{
+		Failures:  append([]model.Failure(nil), in.Failures...),
+		Skipped:   in.Skipped,
+		Preflight: in.Preflight,
+	}
+	sort.SliceStable(d.Failures, func(i, j int) bool {
+		a, b := d.Failures[i], d.Failures[j]
+		if a.Section != b.Section {
+			return a.Section < b.Section
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		return a.Operation < b.Operation
+	})
+
+	failed := map[string]int{}
+	for _, f := range d.Failures {
+		failed[f.Section]++
+	}
+	skipped := func(flag string) bool {
+		for _, s := range in.Skipped {
+			if s == flag {
+				return true
+			}
+		}
+		return false
+	}
+	add := func(section string, collected, complete bool, note string) {
+		n := failed[section]
+		d.Sections = append(d.Sections, model.SectionStatus{
+			Section:   section,
+			Collected: collected || n > 0,
+			Complete:  collected && complete && n == 0,
+			Failures:  n,
+			Note:      note,
+		})
+	}
+
+	contribComplete := in.Contributions != nil
+	var capped []string
+	if in.Contributions != nil {
+		for _, rc := range in.Contributions.RepoCoverage {
+			if !rc.Complete {
+				contribComplete = false
+				capped = append(capped, rc.Kind)
+			}
+		}
+	}
+	var note string
+	if len(capped) > 0 {
+		note = "per-repository " + strings.Join(capped, ", ") + " cut short by GitHub's 100-repository cap"
+	}
+	add("contributions", in.Contributions != nil, contribComplete, note)
+
+	note = ""
+	if in.PRSearch.Query != "" && !in.PRSearch.Complete {
+		note = "search results capped (raise --max-search)"
+	}
+	add("pull_requests", in.PRSearch.Query != "", in.PRSearch.Complete, note)
+
+	note = ""
+	issuesComplete := in.IssuesOpenedSearch.Complete && in.IssuesClosedSearch.Complete
+	if in.IssuesOpenedSearch.Query != "" && !issuesComplete {
+		note = "search results capped (raise --max-search)"
+	}
+	add("issues", in.IssuesOpenedSearch.Query != "", issuesComplete, note)
+
+	note = ""
+	if !in.CommitsCollected {
+		note = "enable with --commits"
+	}
+	add("commits", in.CommitsCollected, true, note)
+
+	note = ""
+	reviewsComplete := in.ReviewsGivenSearch.Complete && in.ReviewsReceivedSearch.Complete
+	truncated := truncatedPRs(in.ReviewsGiven, in.ReviewsReceived)
+	switch {
+	case !in.ReviewsCollected:
+		note = "enable with --reviews"
+	case !reviewsComplete:
+		note = "search results capped (raise --max-search)"
+	case truncated > 0:
+		note = fmt.Sprintf("reviews or review comments of %d PRs could not all be read", truncated)
+	}
+	reviewsComplete = reviewsComplete && truncated == 0
+	add("reviews", in.ReviewsCollected, reviewsComplete, note)
+
+	add("repositories", in.Repos != nil, true, "")
+
+	note = ""
+	switch {
+	case in.ChangedFiles == nil && in.Languages != nil:
+		note = "attributed by repo bytes, not lines changed"
+	case in.ChangedFiles != nil && !HasCountedChanges(in.ChangedFiles, in.LanguageOptions):
+		note = "no changed file is in a recognised, counted language"
+	}
+	add("languages", in.Languages != nil || in.ChangedFiles != nil, true, note)
+
+	note = ""
+	if skipped("--skip-growth") {
+		note = "skipped (--skip-growth)"
+	}
+	add("growth", in.Growth != nil, true, note)
+
+	return d
+}
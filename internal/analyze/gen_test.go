@@ -0,0 +1,182 @@
+package analyze
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// Synthetic inputs for the table, property and golden tests. Every generator
+// takes a seeded *rand.Rand, so a seed always yields the same data.
+
+// day is a date in UTC, for building calendars by hand.
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// calendar builds consecutive days from start with the given counts.
+func calendar(start string, counts ...int) []model.ContributionDay {
+	d := day(start)
+	out := make([]model.ContributionDay, len(counts))
+	for i, n := range counts {
+		out[i] = model.ContributionDay{Date: d.AddDate(0, 0, i).Format(time.DateOnly), Count: n}
+	}
+	return out
+}
+
+// yearWindow is the calendar year y in loc, as --year builds it.
+func yearWindow(y int, loc *time.Location) period.Window {
+	return period.FiscalYear(y, time.January, loc)
+}
+
+// genCalendar fills every day of w; a day is active with probability active
+// and then gets 1-12 contributions.
+func genCalendar(rng *rand.Rand, w period.Window, active float64) []model.ContributionDay {
+	var out []model.ContributionDay
+	for d := w.From; !d.After(w.To); d = d.AddDate(0, 0, 1) {
+		n := 0
+		if rng.Float64() < active {
+			n = 1 + rng.Intn(12)
+		}
+		out = append(out, model.ContributionDay{Date: d.Format(time.DateOnly), Count: n})
+	}
+	return out
+}
+
+// genRepos names n repositories across a few owners.
+func genRepos(rng *rand.Rand, n int) []string {
+	owners := []string{"octocat", "octo-org", "acme", "hubot"}
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s/repo-%02d", owners[rng.Intn(len(owners))], i)
+	}
+	return out
+}
+
+// genContributions builds a collection over w whose per-repo breakdowns sum
+// to the totals, spread over repos.
+func genContributions(rng *rand.Rand, w period.Window, repos []string) *model.ContributionsCollection {
+	cc := &model.ContributionsCollection{
+		Calendar:      genCalendar(rng, w, 0.6),
+		ByRepoCommits: map[string]model.RepoContribLite{},
+		ByRepoPRs:     map[string]model.RepoContribLite{},
+		ByRepoIssues:  map[string]model.RepoContribLite{},
+		ByRepoReviews: map[string]model.RepoContribLite{},
+	}
+	spread := func(dst map[string]model.RepoContribLite, max int) int {
+		total := 0
+		for _, r := range repos {
+			if rng.Intn(3) == 0 {
+				continue
+			}
+			n := 1 + rng.Intn(max)
+			dst[r] = model.RepoContribLite{Repo: r, Count: n, IsPrivate: rng.Intn(5) == 0}
+			total += n
+		}
+		return total
+	}
+	cc.TotalCommits = spread(cc.ByRepoCommits, 80)
+	cc.TotalPRs = spread(cc.ByRepoPRs, 15)
+	cc.TotalIssues = spread(cc.ByRepoIssues, 8)
+	cc.TotalReviews = spread(cc.ByRepoReviews, 20)
+	return cc
+}
+
+// randomTime is a uniformly random second inside w.
+func randomTime(rng *rand.Rand, w period.Window) time.Time {
+	return w.From.Add(time.Duration(rng.Int63n(int64(w.To.Sub(w.From)/time.Second))) * time.Second)
+}
+
+// genPRs builds n PRs created inside w. About two in three are merged, a
+// few of them with mergedAt before createdAt, as clock skew produces.
+func genPRs(rng *rand.Rand, w period.Window, repos []string, n int) []model.PRItem {
+	out := make([]model.PRItem, n)
+	for i := range out {
+		repo := repos[rng.Intn(len(repos))]
+		p := model.PRItem{
+			Repo:      repo,
+			Number:    i + 1,
+			Title:     fmt.Sprintf("Change %d", i+1),
+			URL:       fmt.Sprintf("https://github.com/%s/pull/%d", repo, i+1),
+			CreatedAt: randomTime(rng, w),
+			Additions: rng.Intn(500),
+			Deletions: rng.Intn(200),
+		}
+		if rng.Intn(3) > 0 {
+			merged := p.CreatedAt.Add(time.Duration(rng.Intn(14*24*60)) * time.Minute)
+			if rng.Intn(20) == 0 {
+				merged = p.CreatedAt.Add(-time.Duration(1+rng.Intn(120)) * time.Second)
+			}
+			p.Merged, p.MergedAt = true, &merged
+		}
+		out[i] = p
+	}
+	return out
+}
+
+// genIssues builds n issues opened inside w, about half of them closed.
+func genIssues(rng *rand.Rand, w period.Window, repos []string, n int) []model.IssueItem {
+	out := make([]model.IssueItem, n)
+	for i := range out {
+		repo := repos[rng.Intn(len(repos))]
+		it := model.IssueItem{
+			Repo:      repo,
+			Number:    1000 + i,
+			Title:     fmt.Sprintf("Problem %d", i+1),
+			URL:       fmt.Sprintf("https://github.com/%s/issues/%d", repo, 1000+i),
+			CreatedAt: randomTime(rng, w),
+		}
+		if rng.Intn(2) == 0 {
+			closed := it.CreatedAt.Add(time.Duration(1+rng.Intn(30*24)) * time.Hour)
+			it.ClosedAt = &closed
+		}
+		out[i] = it
+	}
+	return out
+}
+
+// genLanguages builds per-repo language bytes for repos.
+func genLanguages(rng *rand.Rand, repos []string) []model.RepoLanguages {
+	langs := []string{"Go", "TypeScript", "Python", "Rust", "Shell", "HTML", "CSS", "Ruby"}
+	out := make([]model.RepoLanguages, len(repos))
+	for i, r := range repos {
+		lb := model.LanguageBytes{}
+		for _, l := range langs {
+			if rng.Intn(3) == 0 {
+				lb[l] = int64(1 + rng.Intn(100000))
+			}
+		}
+		out[i] = model.RepoLanguages{Repo: r, Languages: lb, IsFork: rng.Intn(10) == 0, IsArchived: rng.Intn(10) == 0}
+	}
+	return out
+}
+
+// genInput is a complete user input for w from seed: contributions, PRs,
+// issues and bytes-mode languages.
+func genInput(seed int64, w period.Window, loc *time.Location) Input {
+	rng := rand.New(rand.NewSource(seed))
+	repos := genRepos(rng, 4+rng.Intn(16))
+	in := Input{
+		User:          "octocat",
+		Window:        w,
+		Location:      loc,
+		Contributions: genContributions(rng, w, repos),
+		Languages:     genLanguages(rng, repos),
+		Now:           time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+	in.PRs = genPRs(rng, w, repos, rng.Intn(60))
+	in.IssuesOpened = genIssues(rng, w, repos, rng.Intn(30))
+	for _, it := range in.IssuesOpened {
+		if it.ClosedAt != nil && !it.ClosedAt.After(w.To) {
+			in.IssuesClosed = append(in.IssuesClosed, it)
+		}
+	}
+	return in
+}
@@ -0,0 +1,95 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/golden from the current output")
+
+// goldenInputs are the recaps pinned in testdata/golden/<name>.json. A diff
+// there is the reviewable effect of an analytics change.
+func goldenInputs(t *testing.T) map[string]Input {
+	t.Helper()
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leap := genInput(2024, yearWindow(2024, kolkata), kolkata)
+	leap.LanguageOptions = LanguageOptions{Weighting: WeightActivity, Ignore: []string{IgnoreForks, "HTML"}}
+
+	changes := genInput(7, yearWindow(2025, la), la)
+	changes.Languages = nil
+	changes.ChangedFiles = []model.ChangedFile{}
+	paths := []string{"main.go", "web/app.tsx", "web/app.css", "vendor/x/y.go", "README.md", "scripts/ci.sh", "go.sum"}
+	for i, p := range changes.PRs {
+		path := paths[i%len(paths)]
+		changes.ChangedFiles = append(changes.ChangedFiles, model.ChangedFile{
+			Repo: p.Repo, Number: p.Number, Path: path, Additions: p.Additions, Deletions: p.Deletions, At: p.CreatedAt,
+		})
+	}
+
+	return map[string]Input{
+		"empty": {
+			User:          "octocat",
+			Window:        yearWindow(2025, time.UTC),
+			Contributions: &model.ContributionsCollection{},
+			Now:           time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC),
+		},
+		"synthetic_2025_utc": genInput(2025, yearWindow(2025, time.UTC), time.UTC),
+		"leap_2024_kolkata":  leap,
+		"changes_2025_la":    changes,
+	}
+}
+
+func TestGoldenRecaps(t *testing.T) {
+	for name, in := range goldenInputs(t) {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.MarshalIndent(BuildRecap(in), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			file := filepath.Join("testdata", "golden", name+".json")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(file, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("%v (run go test ./internal/analyze -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("recap differs from %s (run go test ./internal/analyze -update and review the diff)\n%s", file, firstDiff(got, want))
+			}
+		})
+	}
+}
+
+// firstDiff shows the first line where got and want differ.
+func firstDiff(got, want []byte) string {
+	g, w := bytes.Split(got, []byte("\n")), bytes.Split(want, []byte("\n"))
+	for i := 0; i < len(g) && i < len(w); i++ {
+		if !bytes.Equal(g[i], w[i]) {
+			return fmt.Sprintf("line %d:\n  got:  %s\n  want: %s", i+1, bytes.TrimSpace(g[i]), bytes.TrimSpace(w[i]))
+		}
+	}
+	return fmt.Sprintf("got %d lines, want %d", len(g), len(w))
+}
@@ -0,0 +1,138 @@
+package analyze
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// growth returns a copy of g with monthly and weekly series and the breakout
+// day for every repository and for the total, and the totals per owner.
+func growth(g *model.GrowthMetrics, w period.Window, loc *time.Location) *model.GrowthMetrics {
+	out := *g
+	out.Repos = append([]model.GrowthRepo(nil), g.Repos...)
+
+	var stars, forks []time.Time
+	starsAtEnd, forksAtEnd := 0, 0
+	for i := range out.Repos {
+		r := &out.Repos[i]
+		if len(r.StarTimes) > 0 || len(r.ForkTimes) > 0 {
+			r.Series = growthSeries(r.StarTimes, r.ForkTimes, r.StarsNow-r.StarsAfter, r.ForksNow-r.ForksAfter, w, loc)
+		}
+		r.Breakout = breakout(r.Repo, r.StarTimes, loc)
+		if b := r.Breakout; b != nil {
+			best := out.Breakout
+			if best == nil || b.Stars > best.Stars || (b.Stars == best.Stars && b.Date < best.Date) {
+				out.Breakout = b
+			}
+		}
+		stars = append(stars, r.StarTimes...)
+		forks = append(forks, r.ForkTimes...)
+		starsAtEnd += r.StarsNow - r.StarsAfter
+		forksAtEnd += r.ForksNow - r.ForksAfter
+	}
+	out.Series = growthSeries(stars, forks, starsAtEnd, forksAtEnd, w, loc)
+	out.ByOwner = growthByOwner(out.Repos)
+	return &out
+}
+
+// growthByOwner sums the repos per owning user or organization.
+func growthByOwner(repos []model.GrowthRepo) []model.OwnerGrowth {
+	index := map[string]int{}
+	var out []model.OwnerGrowth
+	for _, r := range repos {
+		if r.Owner == "" {
+			continue
+		}
+		i, ok := index[r.Owner]
+		if !ok {
+			i = len(out)
+			index[r.Owner] = i
+			out = append(out, model.OwnerGrowth{Owner: r.Owner, Type: r.OwnerType})
+		}
+		o := &out[i]
+		o.Repos++
+		o.StarsGained += r.StarsGainedInYear
+		o.ForksGained += r.ForksGainedInYear
+		o.StarsNow += r.StarsNow
+		o.ForksNow += r.ForksNow
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].StarsGained != out[j].StarsGained {
+			return out[i].StarsGained > out[j].StarsGained
+		}
+		return out[i].Owner < out[j].Owner
+	})
+	return out
+}
+
+// growthSeries buckets stars and forks by month and ISO week over the whole
+// window, empty periods included. The running totals are worked back from
+// the totals at the end of the window.
+func growthSeries(stars, forks []time.Time, starsAtEnd, forksAtEnd int, w period.Window, loc *time.Location) *model.GrowthSeries {
+	month := func(t time.Time) string { return t.In(loc).Format("2006-01") }
+	week := func(t time.Time) string { return fmtISOWeek(t.In(loc).ISOWeek()) }
+
+	var months, weeks []string
+	from, to := w.From.In(loc), w.To.In(loc)
+	for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, loc); !m.After(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, month(m))
+	}
+	monday := time.Date(from.Year(), from.Month(), from.Day()-(int(from.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	for d := monday; !d.After(to); d = d.AddDate(0, 0, 7) {
+		weeks = append(weeks, week(d))
+	}
+
+	return &model.GrowthSeries{
+		Monthly: growthPoints(months, month, stars, forks, starsAtEnd, forksAtEnd),
+		Weekly:  growthPoints(weeks, week, stars, forks, starsAtEnd, forksAtEnd),
+	}
+}
+
+func growthPoints(periods []string, key func(time.Time) string, stars, forks []time.Time, starsAtEnd, forksAtEnd int) []model.GrowthPoint {
+	index := make(map[string]int, len(periods))
+	points := make([]model.GrowthPoint, len(periods))
+	for i, p := range periods {
+		index[p] = i
+		points[i].Period = p
+	}
+	for _, t := range stars {
+		if i, ok := index[key(t)]; ok {
+			points[i].Stars++
+		}
+	}
+	for _, t := range forks {
+		if i, ok := index[key(t)]; ok {
+			points[i].Forks++
+		}
+	}
+	cumStars, cumForks := starsAtEnd, forksAtEnd
+	for i := len(points) - 1; i >= 0; i-- {
+		points[i].CumulativeStars = cumStars
+		points[i].CumulativeForks = cumForks
+		cumStars -= points[i].Stars
+		cumForks -= points[i].Forks
+	}
+	return points
+}
+
+// breakout finds the day with the most stars (the earliest, on a tie).
+func breakout(repo string, stars []time.Time, loc *time.Location) *model.Breakout {
+	if len(stars) == 0 {
+		return nil
+	}
+	perDay := map[string]int{}
+	for _, t := range stars {
+		perDay[t.In(loc).Format("2006-01-02")]++
+	}
+	b := &model.Breakout{Repo: repo}
+	for day, n := range perDay {
+		if n > b.Stars || (n == b.Stars && day < b.Date) {
+			b.Date, b.Stars = day, n
+		}
+	}
+	b.Share = float64(b.Stars) / float64(len(stars))
+	return b
+}
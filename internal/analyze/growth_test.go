@@ -0,0 +1,241 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// at is a UTC time, "2006-01-02 15:04".
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func periods(points []model.GrowthPoint) []string {
+	out := make([]string, len(points))
+	for i, p := range points {
+		out[i] = p.Period
+	}
+	return out
+}
+
+func TestGrowthPoints(t *testing.T) {
+	month := func(t time.Time) string { return t.Format("2006-01") }
+	stars := []time.Time{at("2025-01-05 10:00"), at("2025-01-20 10:00"), at("2025-03-01 00:00"), at("2024-12-31 23:59")}
+	forks := []time.Time{at("2025-02-14 12:00")}
+
+	// 10 stars and 3 forks today, 4 stars and 1 fork of them after the window:
+	// 6 and 2 at its end. The December star is outside every period.
+	got := growthPoints([]string{"2025-01", "2025-02", "2025-03"}, month, stars, forks, 6, 2)
+	want := []model.GrowthPoint{
+		{Period: "2025-01", Stars: 2, CumulativeStars: 5, CumulativeForks: 1},
+		{Period: "2025-02", Forks: 1, CumulativeStars: 5, CumulativeForks: 2},
+		{Period: "2025-03", Stars: 1, CumulativeStars: 6, CumulativeForks: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("growthPoints = %+v, want %+v", got, want)
+	}
+
+	// No events: every period carries the end total.
+	got = growthPoints([]string{"2025-01", "2025-02"}, month, nil, nil, 7, 0)
+	for _, p := range got {
+		if p.CumulativeStars != 7 || p.Stars != 0 {
+			t.Errorf("quiet period %+v, want cumulative 7", p)
+		}
+	}
+}
+
+func TestGrowthSeries(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		months  []string
+		weeks   []string
+		tz      string
+		stars   []time.Time
+		weekly0 int // stars in the first week
+	}{
+		{
+			name:   "window starting mid-week",
+			from:   "2025-03-05", // a Wednesday
+			to:     "2025-03-20",
+			months: []string{"2025-03"},
+			weeks:  []string{"2025-W10", "2025-W11", "2025-W12"},
+			// Monday and Tuesday of the first week are before the window,
+			// so only the Thursday star lands in it.
+			stars:   []time.Time{at("2025-03-06 09:00"), at("2025-03-18 09:00")},
+			weekly0: 1,
+		},
+		{
+			name:    "ISO week starting in the previous year",
+			from:    "2021-01-01", // a Friday in 2020-W53
+			to:      "2021-01-12",
+			months:  []string{"2021-01"},
+			weeks:   []string{"2020-W53", "2021-W01", "2021-W02"},
+			stars:   []time.Time{at("2021-01-02 12:00"), at("2021-01-03 23:00")},
+			weekly0: 2,
+		},
+		{
+			name:   "zone moves a star into the next week",
+			from:   "2025-06-01",
+			to:     "2025-06-30",
+			tz:     "Asia/Tokyo",
+			months: []string{"2025-06"},
+			weeks:  []string{"2025-W22", "2025-W23", "2025-W24", "2025-W25", "2025-W26", "2025-W27"},
+			// Sunday 20:00 UTC is Monday 05:00 in Tokyo.
+			stars:   []time.Time{at("2025-06-01 20:00")},
+			weekly0: 0,
+		},
+	}
+	for _, tt := range tests {
+		loc := time.UTC
+		if tt.tz != "" {
+			var err error
+			if loc, err = time.LoadLocation(tt.tz); err != nil {
+				t.Fatal(err)
+			}
+		}
+		w, err := period.Range(tt.from, tt.to, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := growthSeries(tt.stars, nil, len(tt.stars), 0, w, loc)
+		if got := periods(s.Monthly); !reflect.DeepEqual(got, tt.months) {
+			t.Errorf("%s: months = %v, want %v", tt.name, got, tt.months)
+		}
+		if got := periods(s.Weekly); !reflect.DeepEqual(got, tt.weeks) {
+			t.Errorf("%s: weeks = %v, want %v", tt.name, got, tt.weeks)
+			continue
+		}
+		if s.Weekly[0].Stars != tt.weekly0 {
+			t.Errorf("%s: first week has %d stars, want %d", tt.name, s.Weekly[0].Stars, tt.weekly0)
+		}
+		// Worked back from the end total.
+		last := s.Weekly[len(s.Weekly)-1]
+		if last.CumulativeStars != len(tt.stars) || s.Weekly[0].CumulativeStars != len(tt.stars)-sumStars(s.Weekly[1:]) {
+			t.Errorf("%s: cumulative stars %+v", tt.name, s.Weekly)
+		}
+	}
+}
+
+func sumStars(points []model.GrowthPoint) int {
+	n := 0
+	for _, p := range points {
+		n += p.Stars
+	}
+	return n
+}
+
+func TestBreakout(t *testing.T) {
+	if b := breakout("acme/api", nil, time.UTC); b != nil {
+		t.Errorf("no stars: %+v", b)
+	}
+
+	// Two days with two stars each: the earlier one wins.
+	stars := []time.Time{at("2025-05-09 10:00"), at("2025-05-02 08:00"), at("2025-05-09 11:00"), at("2025-05-02 09:00"), at("2025-05-03 09:00")}
+	b := breakout("acme/api", stars, time.UTC)
+	if b == nil || b.Date != "2025-05-02" || b.Stars != 2 || b.Share != 0.4 || b.Repo != "acme/api" {
+		t.Errorf("tie = %+v, want 2025-05-02 with 2 stars", b)
+	}
+
+	// Days are counted in the recap zone: in Los Angeles the 2025-05-03 09:00
+	// UTC star and both 2025-05-02 ones fall on May 2, 01:00-02:00 local.
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stars = []time.Time{at("2025-05-02 08:00"), at("2025-05-02 09:00"), at("2025-05-03 06:00"), at("2025-05-09 10:00"), at("2025-05-09 11:00")}
+	if b := breakout("acme/api", stars, la); b.Date != "2025-05-02" || b.Stars != 3 {
+		t.Errorf("LA = %+v, want 2025-05-02 with 3 stars", b)
+	}
+}
+
+func TestGrowth(t *testing.T) {
+	w := yearWindow(2025, time.UTC)
+	g := &model.GrowthMetrics{Repos: []model.GrowthRepo{
+		{
+			Repo: "acme/api", Owner: "acme", StarsNow: 12, StarsAfter: 2, ForksNow: 1,
+			StarTimes: []time.Time{at("2025-02-01 10:00"), at("2025-02-01 11:00"), at("2025-07-01 10:00")},
+		},
+		{
+			// Forks but no stars: a series, no breakout.
+			Repo: "acme/fork-magnet", Owner: "acme", ForksNow: 4, ForksAfter: 1,
+			ForkTimes: []time.Time{at("2025-03-10 10:00"), at("2025-03-11 10:00")},
+		},
+		{Repo: "acme/quiet", Owner: "acme", StarsNow: 3},
+		{
+			Repo: "acme/web", Owner: "acme", StarsNow: 5,
+			StarTimes: []time.Time{at("2025-01-15 10:00"), at("2025-01-15 12:00")},
+		},
+	}}
+	got := growth(g, w, time.UTC)
+
+	api, magnet, quiet, web := got.Repos[0], got.Repos[1], got.Repos[2], got.Repos[3]
+	if api.Series == nil || api.Series.Monthly[11].CumulativeStars != 10 || api.Series.Monthly[0].CumulativeStars != 7 {
+		t.Errorf("acme/api series = %+v", api.Series)
+	}
+	if magnet.Series == nil || magnet.Breakout != nil {
+		t.Errorf("acme/fork-magnet: series %v, breakout %+v", magnet.Series != nil, magnet.Breakout)
+	} else if m := magnet.Series.Monthly; m[1].CumulativeForks != 1 || m[2].Forks != 2 || m[2].CumulativeForks != 3 {
+		t.Errorf("acme/fork-magnet monthly = %+v", m)
+	}
+	if quiet.Series != nil || quiet.Breakout != nil {
+		t.Errorf("acme/quiet has no events: %+v", quiet)
+	}
+
+	// Both api and web peak at two stars; web's day is earlier.
+	if got.Breakout == nil || got.Breakout.Repo != "acme/web" || got.Breakout.Date != "2025-01-15" {
+		t.Errorf("overall breakout = %+v, want acme/web on 2025-01-15", got.Breakout)
+	}
+	if web.Breakout == nil || web.Breakout.Share != 1 {
+		t.Errorf("acme/web breakout = %+v", web.Breakout)
+	}
+
+	// The total series sums every repo, quiet ones included in the end totals.
+	last := got.Series.Monthly[len(got.Series.Monthly)-1]
+	if last.CumulativeStars != 10+0+3+5 || last.CumulativeForks != 1+3 {
+		t.Errorf("total at the end = %+v", last)
+	}
+	if first := got.Series.Monthly[0]; first.Stars != 2 || first.CumulativeStars != 18-3 {
+		t.Errorf("total January = %+v", first)
+	}
+	if len(got.Series.Weekly) != 53 || got.Series.Weekly[0].Period != "2025-W01" {
+		t.Errorf("weekly periods %v", periods(got.Series.Weekly))
+	}
+
+	// The input is left alone.
+	if g.Repos[0].Series != nil || g.Series != nil {
+		t.Error("growth modified its input")
+	}
+}
+
+func TestGrowthByOwner(t *testing.T) {
+	repos := []model.GrowthRepo{
+		{Repo: "octocat/hello-world", Owner: "octocat", OwnerType: "User", Source: "owner", StarsGainedInYear: 4, StarsNow: 10, ForksGainedInYear: 1, ForksNow: 2},
+		{Repo: "octo-org/platform", Owner: "octo-org", OwnerType: "Organization", Source: "org_maintained", StarsGainedInYear: 9, StarsNow: 30},
+		{Repo: "octocat/Spoon-Knife", Owner: "octocat", OwnerType: "User", Source: "allowlist", IsFork: true, StarsGainedInYear: 5, StarsNow: 5, ForksGainedInYear: 3, ForksNow: 3},
+		{Repo: "acme/widgets", Owner: "acme", OwnerType: "Organization", Source: "top_contributed", StarsGainedInYear: 9, StarsNow: 12},
+		{Repo: "ghost-repo"}, // no owner: not attributed
+	}
+	got := growthByOwner(repos)
+	want := []model.OwnerGrowth{
+		// Ties on stars gained go by owner.
+		{Owner: "acme", Type: "Organization", Repos: 1, StarsGained: 9, StarsNow: 12},
+		{Owner: "octo-org", Type: "Organization", Repos: 1, StarsGained: 9, StarsNow: 30},
+		{Owner: "octocat", Type: "User", Repos: 2, StarsGained: 9, ForksGained: 4, StarsNow: 15, ForksNow: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("growthByOwner = %+v, want %+v", got, want)
+	}
+	if got := growthByOwner(nil); len(got) != 0 {
+		t.Errorf("no repos: %+v", got)
+	}
+}
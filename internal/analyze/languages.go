@@ -0,0 +1,201 @@
+package analyze
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/linguist"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// Language attribution modes, as reported in Recap.Languages.Mode.
+const (
+	LanguagesChanges = "changes" // lines changed by the user's PRs, per file language
+	LanguagesBytes   = "bytes"   // today's language bytes of the contributed repos
+)
+
+// Weighting strategies for combining repos in "bytes" mode.
+const (
+	WeightRaw      = "raw"      // sum every repo's bytes
+	WeightActivity = "activity" // repo language shares times the user's activity in the repo
+	WeightEqual    = "equal"    // every repo's language shares count the same
+)
+
+// weightingNotes explain each strategy in the recap's language note.
+var weightingNotes = map[string]string{
+	WeightRaw:      "Repos are weighted by their size in bytes.",
+	WeightActivity: "Each repo's language shares are weighted by your activity in it.",
+	WeightEqual:    "Each repo's language shares count the same, whatever its size.",
+}
+
+// Ignore list entries that match repos rather than languages.
+const (
+	IgnoreForks    = "forks"
+	IgnoreArchived = "archived"
+)
+
+// DefaultIgnore is the default --language-ignore: forked and archived repos,
+// whose code is mostly someone else's or no longer worked on, and Jupyter
+// notebooks, whose stored outputs (images, tables) outweigh the code in them
+// by orders of magnitude in both bytes and changed lines.
+var DefaultIgnore = []string{IgnoreForks, IgnoreArchived, "Jupyter Notebook"}
+
+// LanguageOptions choose how the language breakdown is built.
+type LanguageOptions struct {
+	Weighting string // WeightRaw (default), WeightActivity or WeightEqual
+	// Ignore lists languages to leave out, matched case-insensitively, and
+	// IgnoreForks / IgnoreArchived to leave out such repos.
+	Ignore []string
+}
+
+// ignores reports whether o leaves out lang.
+func (o LanguageOptions) ignores(lang string) bool {
+	for _, s := range o.Ignore {
+		if strings.EqualFold(s, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// monthlyLanguages caps the languages listed per month.
+const monthlyLanguages = 5
+
+// changedLanguages sums the lines added and deleted per language across
+// files, overall and per month of the window (empty months included).
+// Vendored and generated files, unrecognised languages and those opts
+// ignores are left out.
+func changedLanguages(files []model.ChangedFile, w period.Window, loc *time.Location, opts LanguageOptions) (model.LanguageBytes, []model.LanguageMonth) {
+	total := model.LanguageBytes{}
+	perMonth := map[string]model.LanguageBytes{}
+	for _, f := range files {
+		lang, ok := countedFile(f, opts)
+		if !ok {
+			continue
+		}
+		lines := int64(f.Additions + f.Deletions)
+		month := f.At.In(loc).Format("2006-01")
+		if perMonth[month] == nil {
+			perMonth[month] = model.LanguageBytes{}
+		}
+		perMonth[month][lang] += lines
+		total[lang] += lines
+	}
+	if len(total) == 0 {
+		return nil, nil
+	}
+
+	var monthly []model.LanguageMonth
+	from, to := w.From.In(loc), w.To.In(loc)
+	for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, loc); !m.After(to); m = m.AddDate(0, 1, 0) {
+		key := m.Format("2006-01")
+		lm := model.LanguageMonth{Month: key, Top: topLanguages(perMonth[key], monthlyLanguages)}
+		for _, n := range perMonth[key] {
+			lm.Lines += n
+		}
+		monthly = append(monthly, lm)
+	}
+	return total, monthly
+}
+
+// countedFile returns the language of f if its lines count towards the
+// changes mode: a recognised language opts does not ignore, in a file that
+// is not vendored or generated and has lines changed.
+func countedFile(f model.ChangedFile, opts LanguageOptions) (string, bool) {
+	lang := linguist.Language(f.Path)
+	if lang == "" || linguist.Vendored(f.Path) || opts.ignores(lang) || f.Additions+f.Deletions == 0 {
+		return "", false
+	}
+	return lang, true
+}
+
+// HasCountedChanges reports whether any of files counts towards the changes
+// mode. When none does, e.g. PRs that only touched docs or data files, the
+// collector falls back to bytes mode rather than report no languages.
+func HasCountedChanges(files []model.ChangedFile, opts LanguageOptions) bool {
+	for _, f := range files {
+		if _, ok := countedFile(f, opts); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// weightedLanguages combines the repos' language bytes as opts.Weighting
+// says, with activity giving the user's activity per repo. Ignored languages
+// and repos are left out first; the repos left out are returned sorted.
+func weightedLanguages(repos []model.RepoLanguages, activity map[string]int, opts LanguageOptions) (model.LanguageBytes, []string) {
+	var excluded []string
+	type repoBytes struct {
+		langs  model.LanguageBytes
+		total  int64
+		weight float64
+	}
+	var kept []repoBytes
+	var total int64
+	for _, r := range repos {
+		if (r.IsFork && opts.ignores(IgnoreForks)) || (r.IsArchived && opts.ignores(IgnoreArchived)) {
+			excluded = append(excluded, r.Repo)
+			continue
+		}
+		rb := repoBytes{langs: model.LanguageBytes{}, weight: 1}
+		for lang, n := range r.Languages {
+			if n > 0 && !opts.ignores(lang) {
+				rb.langs[lang] += n
+				rb.total += n
+			}
+		}
+		if rb.total == 0 {
+			continue
+		}
+		if opts.Weighting == WeightActivity {
+			rb.weight = float64(activity[r.Repo])
+		}
+		kept = append(kept, rb)
+		total += rb.total
+	}
+	sort.Strings(excluded)
+
+	out := model.LanguageBytes{}
+	if opts.Weighting != WeightActivity && opts.Weighting != WeightEqual {
+		for _, rb := range kept {
+			for lang, n := range rb.langs {
+				out[lang] += n
+			}
+		}
+		return out, excluded
+	}
+
+	// Split the raw total by the weighted shares, so the numbers stay bytes
+	// and only the proportions change.
+	var weights float64
+	for _, rb := range kept {
+		weights += rb.weight
+	}
+	if len(kept) == 0 {
+		return out, excluded
+	}
+	if weights == 0 {
+		// No activity recorded in any repo: weigh them equally rather than
+		// report no languages at all.
+		for i := range kept {
+			kept[i].weight = 1
+		}
+		weights = float64(len(kept))
+	}
+	shares := map[string]float64{}
+	for _, rb := range kept {
+		for lang, n := range rb.langs {
+			shares[lang] += rb.weight / weights * float64(n) / float64(rb.total)
+		}
+	}
+	for lang, share := range shares {
+		if b := int64(math.Round(share * float64(total))); b > 0 {
+			out[lang] = b
+		}
+	}
+	return out, excluded
+}
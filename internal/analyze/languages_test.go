@@ -0,0 +1,147 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestChangedLanguages(t *testing.T) {
+	w := yearWindow(2025, time.UTC)
+	file := func(path string, add, del int, when string) model.ChangedFile {
+		return model.ChangedFile{Repo: "acme/api", Path: path, Additions: add, Deletions: del, At: day(when)}
+	}
+	files := []model.ChangedFile{
+		file("cmd/main.go", 10, 2, "2025-01-10"),
+		file("internal/build/build.go", 5, 0, "2025-01-20"),
+		file("web/app.ts", 7, 3, "2025-03-02"),
+		file("vendor/x/y.go", 100, 0, "2025-01-10"),
+		file("README.md", 40, 0, "2025-01-10"),
+		file("scripts/ci.sh", 0, 0, "2025-03-02"), // renamed only
+		file("Makefile", 1, 1, "2025-12-31"),
+	}
+
+	total, monthly := changedLanguages(files, w, time.UTC, LanguageOptions{})
+	want := model.LanguageBytes{"Go": 17, "TypeScript": 10, "Makefile": 2}
+	if !reflect.DeepEqual(total, want) {
+		t.Errorf("total = %v, want %v", total, want)
+	}
+	if len(monthly) != 12 || monthly[0].Lines != 17 || monthly[1].Lines != 0 || monthly[2].Lines != 10 || monthly[11].Lines != 2 {
+		t.Errorf("monthly = %+v", monthly)
+	}
+
+	total, _ = changedLanguages(files, w, time.UTC, LanguageOptions{Ignore: []string{"go"}})
+	if _, ok := total["Go"]; ok || total["TypeScript"] != 10 {
+		t.Errorf("ignoring go: %v", total)
+	}
+
+	// Nothing countable: no breakdown, and the collector falls back to bytes.
+	docs := []model.ChangedFile{file("README.md", 40, 0, "2025-01-10"), file("vendor/x/y.go", 9, 0, "2025-01-10"), file("main.go", 0, 0, "2025-01-10")}
+	if total, monthly := changedLanguages(docs, w, time.UTC, LanguageOptions{}); total != nil || monthly != nil {
+		t.Errorf("docs only: %v, %v", total, monthly)
+	}
+	for _, tt := range []struct {
+		name  string
+		files []model.ChangedFile
+		opts  LanguageOptions
+		want  bool
+	}{
+		{"code", files, LanguageOptions{}, true},
+		{"docs, vendored and empty changes", docs, LanguageOptions{}, false},
+		{"every language ignored", files[:2], LanguageOptions{Ignore: []string{"Go"}}, false},
+		{"none", nil, LanguageOptions{}, false},
+	} {
+		if got := HasCountedChanges(tt.files, tt.opts); got != tt.want {
+			t.Errorf("HasCountedChanges(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	in := Input{Window: w, ChangedFiles: docs}
+	for _, s := range diagnostics(in).Sections {
+		if s.Section == "languages" && s.Note != "no changed file is in a recognised, counted language" {
+			t.Errorf("languages section = %+v", s)
+		}
+	}
+}
+
+func TestWeightedLanguages(t *testing.T) {
+	repos := []model.RepoLanguages{
+		// 9,000 bytes, mostly Go.
+		{Repo: "acme/big", Languages: model.LanguageBytes{"Go": 8000, "Shell": 1000}},
+		// 1,000 bytes of Rust.
+		{Repo: "acme/small", Languages: model.LanguageBytes{"Rust": 1000}},
+		{Repo: "acme/fork", IsFork: true, Languages: model.LanguageBytes{"C": 50000}},
+		{Repo: "acme/old", IsArchived: true, Languages: model.LanguageBytes{"Perl": 50000}},
+		{Repo: "acme/empty", Languages: model.LanguageBytes{}},
+	}
+	ignore := []string{IgnoreForks, IgnoreArchived}
+	tests := []struct {
+		name     string
+		opts     LanguageOptions
+		activity map[string]int
+		want     model.LanguageBytes
+		excluded []string
+	}{
+		{
+			name:     "raw sums bytes",
+			opts:     LanguageOptions{Ignore: ignore},
+			want:     model.LanguageBytes{"Go": 8000, "Shell": 1000, "Rust": 1000},
+			excluded: []string{"acme/fork", "acme/old"},
+		},
+		{
+			name: "raw keeps forks and archives unless ignored",
+			opts: LanguageOptions{Weighting: WeightRaw},
+			want: model.LanguageBytes{"Go": 8000, "Shell": 1000, "Rust": 1000, "C": 50000, "Perl": 50000},
+		},
+		{
+			// Each repo's shares count half: Go 8/9, Shell 1/9 of one half,
+			// Rust all of the other, scaled to the 10,000 bytes kept.
+			name:     "equal",
+			opts:     LanguageOptions{Weighting: WeightEqual, Ignore: ignore},
+			want:     model.LanguageBytes{"Go": 4444, "Shell": 556, "Rust": 5000},
+			excluded: []string{"acme/fork", "acme/old"},
+		},
+		{
+			name:     "activity",
+			opts:     LanguageOptions{Weighting: WeightActivity, Ignore: ignore},
+			activity: map[string]int{"acme/big": 1, "acme/small": 3},
+			want:     model.LanguageBytes{"Go": 2222, "Shell": 278, "Rust": 7500},
+			excluded: []string{"acme/fork", "acme/old"},
+		},
+		{
+			name:     "activity: a repo without activity counts for nothing",
+			opts:     LanguageOptions{Weighting: WeightActivity, Ignore: ignore},
+			activity: map[string]int{"acme/small": 5},
+			want:     model.LanguageBytes{"Rust": 10000},
+			excluded: []string{"acme/fork", "acme/old"},
+		},
+		{
+			name:     "activity: none anywhere falls back to equal",
+			opts:     LanguageOptions{Weighting: WeightActivity, Ignore: ignore},
+			activity: map[string]int{},
+			want:     model.LanguageBytes{"Go": 4444, "Shell": 556, "Rust": 5000},
+			excluded: []string{"acme/fork", "acme/old"},
+		},
+		{
+			name:     "ignored language leaves the rest of its repo, at half weight",
+			opts:     LanguageOptions{Weighting: WeightEqual, Ignore: []string{"forks", "archived", "go"}},
+			want:     model.LanguageBytes{"Shell": 1000, "Rust": 1000},
+			excluded: []string{"acme/fork", "acme/old"},
+		},
+	}
+	for _, tt := range tests {
+		got, excluded := weightedLanguages(repos, tt.activity, tt.opts)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+		if !reflect.DeepEqual(excluded, tt.excluded) {
+			t.Errorf("%s: excluded %v, want %v", tt.name, excluded, tt.excluded)
+		}
+	}
+
+	if got, _ := weightedLanguages(nil, nil, LanguageOptions{Weighting: WeightEqual}); len(got) != 0 {
+		t.Errorf("no repos: %v", got)
+	}
+}
@@ -0,0 +1,155 @@
+package analyze
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// mergeExtremes is how many PRs MergeTimeStats.Fastest and Slowest list.
+const mergeExtremes = 5
+
+// mergeBuckets are the upper bounds, in hours, of the merge-time histogram;
+// a final bucket takes the rest.
+var mergeBuckets = []struct {
+	label string
+	below float64
+}{
+	{"<10m", 10.0 / 60},
+	{"10m-1h", 1},
+	{"1-4h", 4},
+	{"4-24h", 24},
+	{"1-3d", 3 * 24},
+	{"3-7d", 7 * 24},
+	{"1-4w", 28 * 24},
+	{">=4w", math.Inf(1)},
+}
+
+// sizeBuckets are the PR size labels by additions + deletions.
+var sizeBuckets = []struct {
+	label string
+	below int
+}{
+	{"XS", 10},
+	{"S", 30},
+	{"M", 100},
+	{"L", 500},
+	{"XL", 1000},
+	{"XXL", math.MaxInt},
+}
+
+// mergeTimeStats describes how long the merged prs took, or nil if none has
+// a usable duration.
+func mergeTimeStats(prs []model.PRItem) *model.MergeTimeStats {
+	var merged []model.MergedPR
+	for _, p := range prs {
+		if !p.Merged || p.MergedAt == nil {
+			continue
+		}
+		h := p.MergedAt.Sub(p.CreatedAt).Hours()
+		if h < 0 {
+			continue
+		}
+		merged = append(merged, model.MergedPR{
+			Repo: p.Repo, Number: p.Number, Title: p.Title, URL: p.URL,
+			Size: p.Additions + p.Deletions, Hours: h,
+		})
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+
+	// Stable: PRs that took as long keep their order.
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Hours < merged[j].Hours })
+	hours := make([]float64, len(merged))
+	for i, m := range merged {
+		hours[i] = m.Hours
+	}
+
+	st := &model.MergeTimeStats{
+		Count:       len(merged),
+		MedianHours: median(hours),
+		P75Hours:    percentile(hours, 0.75),
+		P90Hours:    percentile(hours, 0.90),
+		P99Hours:    percentile(hours, 0.99),
+		MinHours:    hours[0],
+		MaxHours:    hours[len(hours)-1],
+	}
+
+	st.Histogram = make([]model.BucketCount, len(mergeBuckets))
+	for i, b := range mergeBuckets {
+		st.Histogram[i].Bucket = b.label
+	}
+	for _, h := range hours {
+		i := sort.Search(len(mergeBuckets), func(i int) bool { return h < mergeBuckets[i].below })
+		st.Histogram[i].Count++
+	}
+
+	n := min(mergeExtremes, len(merged))
+	st.Fastest = append([]model.MergedPR(nil), merged[:n]...)
+	st.Slowest = make([]model.MergedPR, 0, n)
+	for i := len(merged) - 1; i >= len(merged)-n; i-- {
+		st.Slowest = append(st.Slowest, merged[i])
+	}
+
+	byRepo := map[string][]float64{}
+	bySize := map[string][]float64{}
+	for _, m := range merged {
+		byRepo[m.Repo] = append(byRepo[m.Repo], m.Hours)
+		bySize[sizeBucket(m.Size)] = append(bySize[sizeBucket(m.Size)], m.Hours)
+	}
+	st.ByRepo = make([]model.MergeTimeGroup, 0, len(byRepo))
+	for repo, hs := range byRepo {
+		st.ByRepo = append(st.ByRepo, mergeTimeGroup(repo, hs))
+	}
+	sort.Slice(st.ByRepo, func(i, j int) bool {
+		if st.ByRepo[i].Count == st.ByRepo[j].Count {
+			return st.ByRepo[i].Group < st.ByRepo[j].Group
+		}
+		return st.ByRepo[i].Count > st.ByRepo[j].Count
+	})
+	if len(st.ByRepo) > MaxTopRepos {
+		st.ByRepo = st.ByRepo[:MaxTopRepos]
+	}
+	st.BySize = []model.MergeTimeGroup{}
+	for _, b := range sizeBuckets {
+		if hs := bySize[b.label]; len(hs) > 0 {
+			st.BySize = append(st.BySize, mergeTimeGroup(b.label, hs))
+		}
+	}
+	return st
+}
+
+// mergeTimeGroup summarises hours, which are sorted.
+func mergeTimeGroup(group string, hours []float64) model.MergeTimeGroup {
+	return model.MergeTimeGroup{
+		Group:       group,
+		Count:       len(hours),
+		MedianHours: median(hours),
+		P90Hours:    percentile(hours, 0.90),
+	}
+}
+
+func sizeBucket(size int) string {
+	for _, b := range sizeBuckets {
+		if size < b.below {
+			return b.label
+		}
+	}
+	return sizeBuckets[len(sizeBuckets)-1].label
+}
+
+// percentile interpolates linearly between the closest ranks of sorted, so
+// percentile(sorted, 0.5) is the median.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (sorted[lo+1]-sorted[lo])*(pos-float64(lo))
+}
@@ -0,0 +1,122 @@
+package analyze
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		xs   []float64
+		p    float64
+		want float64
+	}{
+		{nil, 0.5, 0},
+		{[]float64{7}, 0.99, 7},
+		{[]float64{1, 2, 3, 4}, 0.5, 2.5},
+		{[]float64{1, 2, 3, 4}, 0, 1},
+		{[]float64{1, 2, 3, 4}, 1, 4},
+		{[]float64{0, 10}, 0.9, 9},
+		{[]float64{1, 2, 3, 4, 5}, 0.75, 4},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.xs, tt.p); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.xs, tt.p, got, tt.want)
+		}
+		if tt.p == 0.5 && percentile(tt.xs, tt.p) != median(tt.xs) {
+			t.Errorf("percentile(%v, 0.5) disagrees with median", tt.xs)
+		}
+	}
+}
+
+func TestMergeTimeStats(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	n := 0
+	pr := func(repo string, took time.Duration, size int) model.PRItem {
+		n++
+		merged := created.Add(took)
+		return model.PRItem{
+			Repo: repo, Number: n, URL: fmt.Sprintf("https://github.com/%s/pull/%d", repo, n),
+			CreatedAt: created, Merged: true, MergedAt: &merged, Additions: size,
+		}
+	}
+	prs := []model.PRItem{
+		pr("o/a", 5*time.Minute, 3),
+		pr("o/a", 30*time.Minute, 20),
+		pr("o/b", 2*time.Hour, 20),
+		pr("o/a", 2*time.Hour, 150),
+		pr("o/b", 30*time.Hour, 800),
+		pr("o/c", 180*24*time.Hour, 5000), // the stale one
+		pr("o/c", -time.Minute, 1),        // clock skew
+		{Repo: "o/c", CreatedAt: created}, // never merged
+	}
+
+	st := mergeTimeStats(prs)
+	if st == nil || st.Count != 6 {
+		t.Fatalf("MergeTime = %+v, want 6 PRs", st)
+	}
+	if st.MedianHours != 2 || st.MinHours != 5.0/60 || st.MaxHours != 180*24 {
+		t.Errorf("median %v min %v max %v", st.MedianHours, st.MinHours, st.MaxHours)
+	}
+	if !(st.MedianHours <= st.P75Hours && st.P75Hours <= st.P90Hours && st.P90Hours <= st.P99Hours && st.P99Hours <= st.MaxHours) {
+		t.Errorf("percentiles out of order: %+v", st)
+	}
+
+	want := map[string]int{"<10m": 1, "10m-1h": 1, "1-4h": 2, "1-3d": 1, ">=4w": 1}
+	if len(st.Histogram) != len(mergeBuckets) {
+		t.Errorf("Histogram = %+v, want every bucket", st.Histogram)
+	}
+	for _, b := range st.Histogram {
+		if b.Count != want[b.Bucket] {
+			t.Errorf("bucket %s = %d, want %d", b.Bucket, b.Count, want[b.Bucket])
+		}
+	}
+
+	if len(st.Fastest) != mergeExtremes || st.Fastest[0].Number != 1 || st.Fastest[0].URL == "" {
+		t.Errorf("Fastest = %+v", st.Fastest)
+	}
+	// Equally fast PRs keep their order.
+	if st.Fastest[2].Number != 3 || st.Fastest[3].Number != 4 {
+		t.Errorf("Fastest = %+v, want #3 before #4", st.Fastest)
+	}
+	if st.Slowest[0].Number != 6 || st.Slowest[0].Size != 5000 || st.Slowest[1].Number != 5 {
+		t.Errorf("Slowest = %+v", st.Slowest)
+	}
+
+	if len(st.ByRepo) != 3 || st.ByRepo[0].Group != "o/a" || st.ByRepo[0].Count != 3 || st.ByRepo[0].MedianHours != 0.5 {
+		t.Errorf("ByRepo = %+v", st.ByRepo)
+	}
+	if st.ByRepo[1].Group != "o/b" || st.ByRepo[2].Group != "o/c" {
+		t.Errorf("ByRepo = %+v, want the most merged first", st.ByRepo)
+	}
+	wantSizes := []string{"XS", "S", "L", "XL", "XXL"}
+	if len(st.BySize) != len(wantSizes) {
+		t.Fatalf("BySize = %+v", st.BySize)
+	}
+	for i, g := range st.BySize {
+		if g.Group != wantSizes[i] {
+			t.Errorf("BySize[%d] = %+v, want %s", i, g, wantSizes[i])
+		}
+	}
+	if st.BySize[1].Count != 2 || st.BySize[1].MedianHours != 1.25 {
+		t.Errorf("S = %+v, want 2 PRs with a 1.25h median", st.BySize[1])
+	}
+
+	for _, prs := range [][]model.PRItem{nil, {pr("o/a", -time.Second, 1)}, {{Merged: true}}} {
+		if st := mergeTimeStats(prs); st != nil {
+			t.Errorf("mergeTimeStats(%+v) = %+v, want nil", prs, st)
+		}
+	}
+}
+
+func TestSizeBucket(t *testing.T) {
+	for size, want := range map[int]string{0: "XS", 9: "XS", 10: "S", 99: "M", 100: "L", 999: "XL", 1000: "XXL", 1 << 30: "XXL"} {
+		if got := sizeBucket(size); got != want {
+			t.Errorf("sizeBucket(%d) = %s, want %s", size, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package analyze
+
+import (
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// punchDays are the punch card rows, Monday first as ISO weeks are.
+var punchDays = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// punchCard buckets every timestamped event inside w by local weekday and
+// hour. PRs merged and issues closed after the window end are left out.
+// commits is nil unless commit history was collected.
+func punchCard(w period.Window, loc *time.Location, prs []model.PRItem, opened, closed []model.IssueItem, commits []model.CommitItem) model.PunchCard {
+	pc := model.PunchCard{Days: punchDays, Sources: map[string]int{}}
+	add := func(kind string, t time.Time) {
+		if !w.From.IsZero() && (t.Before(w.From) || t.After(w.To)) {
+			return
+		}
+		t = t.In(loc)
+		pc.Cells[(int(t.Weekday())+6)%7][t.Hour()]++
+		pc.Sources[kind]++
+		pc.Total++
+	}
+
+	for _, p := range prs {
+		add("pr_created", p.CreatedAt)
+		if p.Merged && p.MergedAt != nil {
+			add("pr_merged", *p.MergedAt)
+		}
+	}
+	for _, it := range opened {
+		add("issue_opened", it.CreatedAt)
+	}
+	for _, it := range closed {
+		if it.ClosedAt != nil {
+			add("issue_closed", *it.ClosedAt)
+		}
+	}
+	for _, c := range commits {
+		add("commit", c.CommittedAt)
+	}
+
+	// Ties go to the earliest slot of the week.
+	for d := range pc.Cells {
+		for h, n := range pc.Cells[d] {
+			if n > 0 && (pc.Busiest == nil || n > pc.Busiest.Count) {
+				pc.Busiest = &model.PunchSlot{Day: punchDays[d], Hour: h, Count: n}
+			}
+		}
+	}
+	return pc
+}
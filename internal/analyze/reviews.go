@@ -0,0 +1,118 @@
+package analyze
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// reviewDepth summarises the reviews user submitted inside [from, to] on
+// given, and who reviewed the user's own PRs in received.
+func reviewDepth(user string, from, to time.Time, given, received []model.ReviewedPR) *model.ReviewDepth {
+	var d model.ReviewDepth
+	inWindow := func(t time.Time) bool { return !t.Before(from) && !t.After(to) }
+
+	authors := map[string]int{}
+	var firstReviewHours []float64
+	for _, pr := range given {
+		var first *time.Time
+		for _, r := range pr.Reviews {
+			if !strings.EqualFold(r.Author, user) || !inWindow(r.SubmittedAt) {
+				continue
+			}
+			d.Submitted++
+			switch r.State {
+			case "APPROVED":
+				d.Approvals++
+			case "CHANGES_REQUESTED":
+				d.ChangesRequested++
+			case "COMMENTED":
+				d.Commented++
+			case "DISMISSED":
+				d.Dismissed++
+			}
+			if first == nil || r.SubmittedAt.Before(*first) {
+				t := r.SubmittedAt
+				first = &t
+			}
+		}
+		for _, c := range pr.ThreadComments {
+			if strings.EqualFold(c.Author, user) && inWindow(c.CreatedAt) {
+				d.ReviewComments++
+			}
+		}
+		if first == nil {
+			continue
+		}
+		d.PRsReviewed++
+		authors[pr.Author]++
+		if h := first.Sub(pr.CreatedAt).Hours(); h >= 0 {
+			firstReviewHours = append(firstReviewHours, h)
+		}
+	}
+	d.MedianHoursToFirstReview = median(firstReviewHours)
+	d.TopAuthorsReviewed = topPeople(authors, 10)
+
+	reviewers := map[string]int{}
+	for _, pr := range received {
+		seen := map[string]bool{}
+		for _, r := range pr.Reviews {
+			if strings.EqualFold(r.Author, user) || seen[r.Author] {
+				continue
+			}
+			seen[r.Author] = true
+			reviewers[r.Author]++
+		}
+	}
+	d.TopReviewers = topPeople(reviewers, 10)
+
+	d.TruncatedPRs = truncatedPRs(given, received)
+	return &d
+}
+
+// truncatedPRs counts the PRs whose reviews or review comments could not all
+// be read.
+func truncatedPRs(given, received []model.ReviewedPR) int {
+	n := 0
+	for _, prs := range [][]model.ReviewedPR{given, received} {
+		for _, pr := range prs {
+			if pr.Truncated {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// topPeople ranks logins by count, ties by login.
+func topPeople(m map[string]int, n int) []model.PersonCount {
+	out := make([]model.PersonCount, 0, len(m))
+	for login, c := range m {
+		out = append(out, model.PersonCount{Login: login, Count: c})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count == out[j].Count {
+			return out[i].Login < out[j].Login
+		}
+		return out[i].Count > out[j].Count
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	mid := len(s) / 2
+	if len(s)%2 == 1 {
+		return s[mid]
+	}
+	return (s[mid-1] + s[mid]) / 2
+}
@@ -0,0 +1,39 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestTruncatedReviews(t *testing.T) {
+	w := yearWindow(2025, time.UTC)
+	full := model.SearchCoverage{Query: "is:pr", Complete: true}
+	in := Input{
+		User: "octocat", Window: w, ReviewsCollected: true,
+		ReviewsGivenSearch: full, ReviewsReceivedSearch: full,
+		ReviewsGiven:    []model.ReviewedPR{{Author: "bob"}, {Author: "carol", Truncated: true}},
+		ReviewsReceived: []model.ReviewedPR{{Author: "octocat", Truncated: true}},
+	}
+	if d := reviewDepth(in.User, w.From, w.To, in.ReviewsGiven, in.ReviewsReceived); d.TruncatedPRs != 2 {
+		t.Errorf("TruncatedPRs = %d, want 2", d.TruncatedPRs)
+	}
+
+	section := func(in Input) model.SectionStatus {
+		for _, s := range diagnostics(in).Sections {
+			if s.Section == "reviews" {
+				return s
+			}
+		}
+		t.Fatal("no reviews section")
+		return model.SectionStatus{}
+	}
+	if s := section(in); s.Complete || s.Note != "reviews or review comments of 2 PRs could not all be read" {
+		t.Errorf("truncated: %+v", s)
+	}
+	in.ReviewsGiven[1].Truncated, in.ReviewsReceived[0].Truncated = false, false
+	if s := section(in); !s.Complete || s.Note != "" {
+		t.Errorf("read to the end: %+v", s)
+	}
+}
@@ -0,0 +1,117 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// seasons totals the calendar by month and by quarter of w, up to asOf
+// (YYYY-MM-DD, empty for all days) as streakStats does. Quarters are fiscal
+// when w is a fiscal year, calendar ones otherwise.
+func seasons(days []model.ContributionDay, asOf string, w period.Window) model.Seasons {
+	s := model.Seasons{Monthly: []model.PeriodTotal{}, Quarters: []model.PeriodTotal{}}
+	start := fiscalStart(w)
+	length := map[string]int{}
+	for _, d := range days {
+		if len(d.Date) < len("2006-01-02") || (asOf != "" && d.Date > asOf) {
+			continue
+		}
+		q, n := quarterOf(d.Date, start)
+		length[q] = n
+		s.Monthly = addToPeriod(s.Monthly, d.Date[:7], d.Count)
+		s.Quarters = addToPeriod(s.Quarters, q, d.Count)
+	}
+
+	// A partial quarter at either edge would make the change look like a
+	// drop or a jump, so only full quarters are compared.
+	for i := 1; i < len(s.Quarters); i++ {
+		prev, cur := s.Quarters[i-1], s.Quarters[i]
+		if prev.Days != length[prev.Period] || cur.Days != length[cur.Period] {
+			continue
+		}
+		d := delta(prev.Contributions, cur.Contributions)
+		s.Quarters[i].VsPrevious = &d
+	}
+
+	var full []model.PeriodTotal
+	for _, m := range s.Monthly {
+		if m.Days == daysInMonth(m.Period) {
+			full = append(full, m)
+		}
+	}
+	if len(full) == 0 {
+		full = s.Monthly
+	}
+	for i := range full {
+		if s.BusiestMonth == nil || full[i].Contributions > s.BusiestMonth.Contributions {
+			s.BusiestMonth = &full[i]
+		}
+		if s.QuietestMonth == nil || full[i].Contributions < s.QuietestMonth.Contributions {
+			s.QuietestMonth = &full[i]
+		}
+	}
+	if s.BusiestMonth != nil {
+		busiest, quietest := *s.BusiestMonth, *s.QuietestMonth
+		s.BusiestMonth, s.QuietestMonth = &busiest, &quietest
+	}
+	return s
+}
+
+// addToPeriod counts one day of count contributions into the period key,
+// appending it when days has moved on to a new period.
+func addToPeriod(totals []model.PeriodTotal, key string, count int) []model.PeriodTotal {
+	if len(totals) == 0 || totals[len(totals)-1].Period != key {
+		totals = append(totals, model.PeriodTotal{Period: key})
+	}
+	p := &totals[len(totals)-1]
+	p.Days++
+	p.Contributions += count
+	if count > 0 {
+		p.ActiveDays++
+	}
+	return totals
+}
+
+// fiscalStart is the month w's quarters start from: the first month of a
+// fiscal year, January for every other window.
+func fiscalStart(w period.Window) time.Month {
+	if strings.HasPrefix(w.Label, "FY") {
+		return w.From.Month()
+	}
+	return time.January
+}
+
+// quarterOf is the quarter of a YYYY-MM-DD date in years starting on the
+// first of start, and how many days that quarter has. Calendar quarters read
+// "2025Q3"; fiscal ones are labelled by the year they start in like
+// period.FiscalYear, so with a July start 2026-01-15 is in "FY2025Q3".
+func quarterOf(date string, start time.Month) (string, int) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", 0
+	}
+	offset := (int(t.Month()) - int(start) + 12) % 12
+	from := time.Date(t.Year(), t.Month()-time.Month(offset%3), 1, 0, 0, 0, 0, time.UTC)
+	days := int(from.AddDate(0, 3, 0).Sub(from).Hours() / 24)
+	if start == time.January {
+		return fmt.Sprintf("%dQ%d", t.Year(), offset/3+1), days
+	}
+	y := t.Year()
+	if t.Month() < start {
+		y--
+	}
+	return fmt.Sprintf("FY%dQ%d", y, offset/3+1), days
+}
+
+// daysInMonth is the length of a YYYY-MM month.
+func daysInMonth(month string) int {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return 0
+	}
+	return t.AddDate(0, 1, -1).Day()
+}
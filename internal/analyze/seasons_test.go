@@ -0,0 +1,184 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+func TestSeasons(t *testing.T) {
+	// Jan 30 - Apr 2 2025: the edges of January and April are partial.
+	counts := make([]int, 63)
+	counts[0] = 4  // Jan 30
+	counts[5] = 3  // Feb 4
+	counts[30] = 2 // Mar 1
+	counts[31] = 2 // Mar 2
+	counts[62] = 9 // Apr 2
+	days := calendar("2025-01-30", counts...)
+
+	s := seasons(days, "", yearWindow(2025, time.UTC))
+	want := []model.PeriodTotal{
+		{Period: "2025-01", Contributions: 4, ActiveDays: 1, Days: 2},
+		{Period: "2025-02", Contributions: 3, ActiveDays: 1, Days: 28},
+		{Period: "2025-03", Contributions: 4, ActiveDays: 2, Days: 31},
+		{Period: "2025-04", Contributions: 9, ActiveDays: 1, Days: 2},
+	}
+	if !reflect.DeepEqual(s.Monthly, want) {
+		t.Errorf("Monthly = %+v, want %+v", s.Monthly, want)
+	}
+	// Partial January and April don't compete.
+	if s.BusiestMonth == nil || s.BusiestMonth.Period != "2025-03" {
+		t.Errorf("BusiestMonth = %+v, want 2025-03", s.BusiestMonth)
+	}
+	if s.QuietestMonth == nil || s.QuietestMonth.Period != "2025-02" {
+		t.Errorf("QuietestMonth = %+v, want 2025-02", s.QuietestMonth)
+	}
+
+	if len(s.Quarters) != 2 {
+		t.Fatalf("Quarters = %+v, want 2025Q1 and 2025Q2", s.Quarters)
+	}
+	q1, q2 := s.Quarters[0], s.Quarters[1]
+	if q1.Period != "2025Q1" || q1.Contributions != 11 || q1.Days != 61 || q1.VsPrevious != nil {
+		t.Errorf("Q1 = %+v", q1)
+	}
+	// Both quarters are partial: not compared.
+	if q2.Period != "2025Q2" || q2.Days != 2 || q2.VsPrevious != nil {
+		t.Errorf("Q2 = %+v", q2)
+	}
+
+	// Up to asOf only; a window without a full month still has a busiest
+	// and quietest.
+	s = seasons(days, "2025-02-04", yearWindow(2025, time.UTC))
+	if len(s.Monthly) != 2 || s.Monthly[1].Days != 4 {
+		t.Errorf("Monthly as of Feb 4 = %+v", s.Monthly)
+	}
+	if s.BusiestMonth.Period != "2025-01" || s.QuietestMonth.Period != "2025-02" {
+		t.Errorf("busiest %+v quietest %+v", s.BusiestMonth, s.QuietestMonth)
+	}
+
+	s = seasons(nil, "", yearWindow(2025, time.UTC))
+	if s.Monthly == nil || s.Quarters == nil || s.BusiestMonth != nil || s.QuietestMonth != nil {
+		t.Errorf("empty calendar: %+v", s)
+	}
+}
+
+func TestSeasonsTies(t *testing.T) {
+	// Every full month of 2024 has one contribution: ties go to January.
+	var days []model.ContributionDay
+	for m := time.January; m <= time.December; m++ {
+		first := time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+		counts := make([]int, first.AddDate(0, 1, -1).Day())
+		counts[0] = 1
+		days = append(days, calendar(first.Format(time.DateOnly), counts...)...)
+	}
+	s := seasons(days, "", yearWindow(2024, time.UTC))
+	if s.BusiestMonth.Period != "2024-01" || s.QuietestMonth.Period != "2024-01" {
+		t.Errorf("busiest %+v quietest %+v", s.BusiestMonth, s.QuietestMonth)
+	}
+	if s.Monthly[1].Days != 29 {
+		t.Errorf("February 2024 has %d days", s.Monthly[1].Days)
+	}
+	for _, q := range s.Quarters[1:] {
+		if q.VsPrevious == nil || q.VsPrevious.Change != 0 || q.VsPrevious.PercentChange == nil {
+			t.Errorf("%s: %+v", q.Period, q.VsPrevious)
+		}
+	}
+}
+
+func TestSeasonsFiscalQuarters(t *testing.T) {
+	// FY2025 starting in July, read up to Feb 10 2026: Jul-Sep, Oct-Dec, and
+	// the first days of Jan-Mar.
+	w := period.FiscalYear(2025, time.July, time.UTC)
+	counts := make([]int, 365)
+	counts[0] = 5   // Jul 1 2025
+	counts[92] = 8  // Oct 1
+	counts[184] = 3 // Jan 1 2026
+	days := calendar("2025-07-01", counts...)
+
+	s := seasons(days, "2026-02-10", w)
+	want := []model.PeriodTotal{
+		{Period: "FY2025Q1", Contributions: 5, ActiveDays: 1, Days: 92},
+		{Period: "FY2025Q2", Contributions: 8, ActiveDays: 1, Days: 92},
+		{Period: "FY2025Q3", Contributions: 3, ActiveDays: 1, Days: 41},
+	}
+	if len(s.Quarters) != len(want) {
+		t.Fatalf("Quarters = %+v, want %+v", s.Quarters, want)
+	}
+	for i, q := range s.Quarters {
+		vs := q.VsPrevious
+		q.VsPrevious = nil
+		if q != want[i] {
+			t.Errorf("Quarters[%d] = %+v, want %+v", i, q, want[i])
+		}
+		// Q2 follows a full quarter; Q3 is still partial.
+		if compared := vs != nil; compared != (i == 1) {
+			t.Errorf("%s: VsPrevious = %+v", q.Period, vs)
+		}
+	}
+	if vs := s.Quarters[1].VsPrevious; vs == nil || vs.Change != 3 {
+		t.Errorf("FY2025Q2 vs Q1 = %+v, want +3", vs)
+	}
+
+	// The same days in a calendar window fall into calendar quarters.
+	s = seasons(days, "2026-02-10", period.Window{Label: "2025-07-01..2026-06-30", From: w.From, To: w.To})
+	if s.Quarters[0].Period != "2025Q3" || s.Quarters[2].Period != "2026Q1" {
+		t.Errorf("calendar quarters = %+v", s.Quarters)
+	}
+}
+
+func TestPunchCard(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := yearWindow(2025, time.UTC)
+	at := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	ptr := func(ts time.Time) *time.Time { return &ts }
+
+	prs := []model.PRItem{
+		// Monday 09:xx UTC, merged the same hour.
+		{CreatedAt: at("2025-03-03T09:05:00Z"), Merged: true, MergedAt: ptr(at("2025-03-03T09:40:00Z"))},
+		// Merged after the window: only the creation counts.
+		{CreatedAt: at("2025-12-31T20:00:00Z"), Merged: true, MergedAt: ptr(at("2026-01-02T10:00:00Z"))},
+		{CreatedAt: at("2025-03-10T09:59:59Z")},
+	}
+	opened := []model.IssueItem{{CreatedAt: at("2025-03-09T23:30:00Z"), ClosedAt: ptr(at("2025-03-10T01:00:00Z"))}}
+	closed := opened
+	commits := []model.CommitItem{{CommittedAt: at("2025-03-03T09:00:00Z")}}
+
+	pc := punchCard(w, time.UTC, prs, opened, closed, commits)
+	if pc.Total != 7 {
+		t.Errorf("Total = %d, want 7", pc.Total)
+	}
+	wantSources := map[string]int{"pr_created": 3, "pr_merged": 1, "issue_opened": 1, "issue_closed": 1, "commit": 1}
+	if !reflect.DeepEqual(pc.Sources, wantSources) {
+		t.Errorf("Sources = %v, want %v", pc.Sources, wantSources)
+	}
+	if pc.Cells[0][9] != 4 || pc.Cells[6][23] != 1 || pc.Cells[0][1] != 1 || pc.Cells[2][20] != 1 {
+		t.Errorf("Cells = %v", pc.Cells)
+	}
+	if want := (&model.PunchSlot{Day: "Mon", Hour: 9, Count: 4}); !reflect.DeepEqual(pc.Busiest, want) {
+		t.Errorf("Busiest = %+v, want %+v", pc.Busiest, want)
+	}
+
+	// Local time moves events across days: Monday 01:00 UTC is Sunday 18:00
+	// in Los Angeles.
+	pc = punchCard(w, la, nil, nil, closed, nil)
+	if pc.Cells[6][18] != 1 || pc.Busiest.Day != "Sun" {
+		t.Errorf("Cells in LA = %v, busiest %+v", pc.Cells, pc.Busiest)
+	}
+
+	pc = punchCard(period.Window{}, time.UTC, nil, nil, nil, nil)
+	if pc.Total != 0 || pc.Busiest != nil || len(pc.Days) != 7 || pc.Sources == nil {
+		t.Errorf("empty punch card: %+v", pc)
+	}
+}
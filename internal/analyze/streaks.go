@@ -0,0 +1,162 @@
+package analyze
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// topStreaks is how many streaks StreakStats.Top lists.
+const topStreaks = 5
+
+// activeRuns splits days (sorted by date) into maximal streaks of active
+// days. Days skip reports are passed over: they neither break nor extend a
+// streak. A date missing from days breaks it.
+func activeRuns(days []model.ContributionDay, skip func(time.Weekday) bool) []model.Streak {
+	var (
+		runs []model.Streak
+		cur  *model.Streak
+		next time.Time // the day that would continue cur
+	)
+	for _, d := range days {
+		t, err := time.Parse(time.DateOnly, d.Date)
+		if err != nil {
+			continue
+		}
+		if skip != nil && skip(t.Weekday()) {
+			continue
+		}
+		if d.Count <= 0 {
+			cur = nil
+			continue
+		}
+		if cur == nil || !t.Equal(next) {
+			runs = append(runs, model.Streak{Start: d.Date})
+			cur = &runs[len(runs)-1]
+		}
+		cur.End = d.Date
+		cur.Days++
+		cur.Contributions += d.Count
+		next = t.AddDate(0, 0, 1)
+		for skip != nil && skip(next.Weekday()) {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return runs
+}
+
+func longestStreak(days []model.ContributionDay) int {
+	best := 0
+	for _, r := range activeRuns(days, nil) {
+		best = max(best, r.Days)
+	}
+	return best
+}
+
+// longestRun is the longest of runs; ties go to the earliest.
+func longestRun(runs []model.Streak) *model.Streak {
+	var best *model.Streak
+	for i := range runs {
+		if best == nil || runs[i].Days > best.Days {
+			best = &runs[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	cp := *best
+	return &cp
+}
+
+func isWeekend(d time.Weekday) bool {
+	return d == time.Saturday || d == time.Sunday
+}
+
+// streakStats analyses the streaks of days as of asOf (YYYY-MM-DD), the last
+// day that has happened: the window end, or today while the window is still
+// open. Later days are left out. weekday adds the weekday-only streak.
+func streakStats(days []model.ContributionDay, asOf string, open, weekday bool) model.StreakStats {
+	s := model.StreakStats{Top: []model.Streak{}}
+	var past []model.ContributionDay
+	for _, d := range days {
+		if asOf != "" && d.Date > asOf {
+			continue
+		}
+		past = append(past, d)
+		if d.Count <= 0 {
+			continue
+		}
+		s.ActiveDays++
+		if t, err := time.Parse(time.DateOnly, d.Date); err == nil && isWeekend(t.Weekday()) {
+			s.WeekendDays++
+		} else {
+			s.WeekdayDays++
+		}
+	}
+
+	runs := activeRuns(past, nil)
+	s.Longest = longestRun(runs)
+	if len(runs) > 0 && len(past) > 0 {
+		last := runs[len(runs)-1]
+		end := past[len(past)-1].Date
+		if asOf != "" {
+			end = asOf
+		}
+		// Today isn't over: a streak through yesterday is still running.
+		if last.End == end || (open && last.End == addDays(end, -1)) {
+			s.Current = &last
+		}
+	}
+
+	top := append([]model.Streak(nil), runs...)
+	// Stable: equally long streaks stay in date order.
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Days > top[j].Days })
+	s.Top = append(s.Top, top[:min(topStreaks, len(top))]...)
+
+	for i := 1; i < len(runs); i++ {
+		g := model.Streak{Start: addDays(runs[i-1].End, 1), End: addDays(runs[i].Start, -1)}
+		g.Days = daysBetween(runs[i-1].End, runs[i].Start) - 1
+		if g.Days > 0 && (s.LongestGap == nil || g.Days > s.LongestGap.Days) {
+			s.LongestGap = &g
+		}
+	}
+
+	if weekday {
+		s.Weekday = longestRun(activeRuns(past, isWeekend))
+	}
+	return s
+}
+
+// addDays shifts a YYYY-MM-DD date by n days.
+func addDays(date string, n int) string {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, n).Format(time.DateOnly)
+}
+
+// daysBetween is how many days after a b is.
+func daysBetween(a, b string) int {
+	ta, err1 := time.Parse(time.DateOnly, a)
+	tb, err2 := time.Parse(time.DateOnly, b)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(tb.Sub(ta).Hours() / 24)
+}
+
+// streakEnd is the last day of w that has happened by now, in loc, and
+// whether w is still open (that day is today).
+func streakEnd(w period.Window, now time.Time, loc *time.Location) (string, bool) {
+	if w.To.IsZero() {
+		return "", false
+	}
+	end := w.To.In(loc).Format(time.DateOnly)
+	if today := now.In(loc).Format(time.DateOnly); today <= end {
+		return today, true
+	}
+	return end, false
+}
@@ -0,0 +1,222 @@
+package analyze
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+func streak(start, end string, days, contributions int) *model.Streak {
+	return &model.Streak{Start: start, End: end, Days: days, Contributions: contributions}
+}
+
+func TestStreakStats(t *testing.T) {
+	// 2025-03-03 is a Monday.
+	tests := []struct {
+		name    string
+		days    []model.ContributionDay
+		asOf    string
+		open    bool
+		longest *model.Streak
+		current *model.Streak
+		gap     *model.Streak
+		top     int
+	}{
+		{name: "empty"},
+		{name: "no activity", days: calendar("2025-03-03", 0, 0, 0), asOf: "2025-03-05"},
+		{
+			name:    "longest and gap",
+			days:    calendar("2025-03-03", 1, 2, 0, 0, 0, 1, 1, 1, 0, 4),
+			asOf:    "2025-03-12",
+			longest: streak("2025-03-08", "2025-03-10", 3, 3),
+			current: streak("2025-03-12", "2025-03-12", 1, 4),
+			gap:     streak("2025-03-05", "2025-03-07", 3, 0),
+			top:     3,
+		},
+		{
+			name:    "tie goes to the earliest",
+			days:    calendar("2025-03-03", 1, 1, 0, 5, 5, 0),
+			asOf:    "2025-03-08",
+			longest: streak("2025-03-03", "2025-03-04", 2, 2),
+			gap:     streak("2025-03-05", "2025-03-05", 1, 0),
+			top:     2,
+		},
+		{
+			name:    "closed window ending on a quiet day",
+			days:    calendar("2025-03-03", 1, 1, 0),
+			asOf:    "2025-03-05",
+			longest: streak("2025-03-03", "2025-03-04", 2, 2),
+			top:     1,
+		},
+		{
+			name:    "open window: today not over yet",
+			days:    calendar("2025-03-03", 1, 1, 0),
+			asOf:    "2025-03-05",
+			open:    true,
+			longest: streak("2025-03-03", "2025-03-04", 2, 2),
+			current: streak("2025-03-03", "2025-03-04", 2, 2),
+			top:     1,
+		},
+		{
+			name:    "open window: future days ignored",
+			days:    calendar("2025-03-03", 1, 3, 0, 0, 0),
+			asOf:    "2025-03-04",
+			open:    true,
+			longest: streak("2025-03-03", "2025-03-04", 2, 4),
+			current: streak("2025-03-03", "2025-03-04", 2, 4),
+			top:     1,
+		},
+		{
+			name:    "across leap day",
+			days:    calendar("2024-02-27", 0, 1, 1, 1, 0),
+			asOf:    "2024-03-02",
+			longest: streak("2024-02-28", "2024-03-01", 3, 3),
+			top:     1,
+		},
+		{
+			name:    "missing date breaks a streak",
+			days:    append(calendar("2025-03-03", 1, 1), calendar("2025-03-06", 1)...),
+			asOf:    "2025-03-06",
+			longest: streak("2025-03-03", "2025-03-04", 2, 2),
+			current: streak("2025-03-06", "2025-03-06", 1, 1),
+			gap:     streak("2025-03-05", "2025-03-05", 1, 0),
+			top:     2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := streakStats(tt.days, tt.asOf, tt.open, false)
+			if !reflect.DeepEqual(s.Longest, tt.longest) {
+				t.Errorf("Longest = %+v, want %+v", s.Longest, tt.longest)
+			}
+			if !reflect.DeepEqual(s.Current, tt.current) {
+				t.Errorf("Current = %+v, want %+v", s.Current, tt.current)
+			}
+			if !reflect.DeepEqual(s.LongestGap, tt.gap) {
+				t.Errorf("LongestGap = %+v, want %+v", s.LongestGap, tt.gap)
+			}
+			if len(s.Top) != tt.top {
+				t.Errorf("Top = %+v, want %d streaks", s.Top, tt.top)
+			}
+			if s.Top == nil {
+				t.Error("Top is nil")
+			}
+			if tt.longest != nil && s.Top[0] != *tt.longest {
+				t.Errorf("Top[0] = %+v, want the longest", s.Top[0])
+			}
+			if s.Weekday != nil {
+				t.Errorf("Weekday = %+v without asking", s.Weekday)
+			}
+		})
+	}
+}
+
+func TestTopStreaks(t *testing.T) {
+	// Streaks of 1..7 days separated by one quiet day, shortest first.
+	var counts []int
+	for n := 1; n <= 7; n++ {
+		for i := 0; i < n; i++ {
+			counts = append(counts, 1)
+		}
+		counts = append(counts, 0)
+	}
+	s := streakStats(calendar("2025-01-01", counts...), "", false, false)
+	if len(s.Top) != topStreaks {
+		t.Fatalf("%d top streaks, want %d", len(s.Top), topStreaks)
+	}
+	for i, want := range []int{7, 6, 5, 4, 3} {
+		if s.Top[i].Days != want {
+			t.Errorf("Top[%d] = %+v, want %d days", i, s.Top[i], want)
+		}
+	}
+	if s.Current != nil {
+		t.Errorf("Current = %+v after a quiet last day", s.Current)
+	}
+}
+
+func TestWeekdayStreak(t *testing.T) {
+	// Mon 2025-03-03 .. Mon 03-17: active every weekday, never on weekends,
+	// except Wed 03-12.
+	s := streakStats(calendar("2025-03-03", 1, 1, 1, 1, 1, 0, 0, 1, 1, 0, 1, 1, 0, 0, 1), "2025-03-17", false, true)
+	if want := streak("2025-03-03", "2025-03-11", 7, 7); !reflect.DeepEqual(s.Weekday, want) {
+		t.Errorf("Weekday = %+v, want %+v", s.Weekday, want)
+	}
+	if s.Longest.Days != 5 {
+		t.Errorf("Longest = %+v, want the first working week", s.Longest)
+	}
+	if s.WeekdayDays != 10 || s.WeekendDays != 0 || s.ActiveDays != 10 {
+		t.Errorf("weekday %d weekend %d active %d, want 10 0 10", s.WeekdayDays, s.WeekendDays, s.ActiveDays)
+	}
+
+	// Weekend activity neither breaks nor extends the weekday streak.
+	s = streakStats(calendar("2025-03-06", 1, 1, 3, 3, 1, 0), "", false, true)
+	if want := streak("2025-03-06", "2025-03-10", 3, 3); !reflect.DeepEqual(s.Weekday, want) {
+		t.Errorf("Weekday = %+v, want %+v", s.Weekday, want)
+	}
+	if s.WeekendDays != 2 || s.WeekdayDays != 3 {
+		t.Errorf("weekday %d weekend %d, want 3 2", s.WeekdayDays, s.WeekendDays)
+	}
+}
+
+func TestStreakEnd(t *testing.T) {
+	w := yearWindow(2025, time.UTC)
+	tests := []struct {
+		now      time.Time
+		wantDay  string
+		wantOpen bool
+	}{
+		{time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), "2025-12-31", false},
+		{time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), "2025-12-31", true},
+		{time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC), "2025-06-01", true},
+		// Already Jan 1 in Auckland is still Dec 31 in UTC.
+		{time.Date(2026, 1, 1, 1, 0, 0, 0, time.FixedZone("NZDT", 13*3600)), "2025-12-31", true},
+	}
+	for _, tt := range tests {
+		day, open := streakEnd(w, tt.now, time.UTC)
+		if day != tt.wantDay || open != tt.wantOpen {
+			t.Errorf("streakEnd(%v) = %s %v, want %s %v", tt.now, day, open, tt.wantDay, tt.wantOpen)
+		}
+	}
+	if day, open := streakEnd(period.Window{}, time.Now(), time.UTC); day != "" || open {
+		t.Errorf("zero window: %s %v", day, open)
+	}
+}
+
+func TestStreakProperties(t *testing.T) {
+	for seed := int64(1); seed <= 200; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		w := yearWindow(2024+int(seed%4), time.UTC)
+		days := genCalendar(rng, w, rng.Float64())
+		s := streakStats(days, "", false, true)
+
+		if got := longestStreak(days); (s.Longest == nil && got != 0) || (s.Longest != nil && s.Longest.Days != got) {
+			t.Fatalf("seed %d: Longest %+v disagrees with longestStreak %d", seed, s.Longest, got)
+		}
+		if s.ActiveDays != s.WeekdayDays+s.WeekendDays {
+			t.Fatalf("seed %d: %d active != %d + %d", seed, s.ActiveDays, s.WeekdayDays, s.WeekendDays)
+		}
+		if s.Longest != nil && s.Longest.Days > s.ActiveDays {
+			t.Fatalf("seed %d: streak %d > %d active days", seed, s.Longest.Days, s.ActiveDays)
+		}
+		if s.Weekday != nil && s.Weekday.Days > s.WeekdayDays {
+			t.Fatalf("seed %d: weekday streak %d > %d active weekdays", seed, s.Weekday.Days, s.WeekdayDays)
+		}
+		if s.LongestGap != nil && s.LongestGap.Days+s.ActiveDays > len(days) {
+			t.Fatalf("seed %d: gap %d + %d active days > %d days", seed, s.LongestGap.Days, s.ActiveDays, len(days))
+		}
+		for i := 1; i < len(s.Top); i++ {
+			if s.Top[i].Days > s.Top[i-1].Days {
+				t.Fatalf("seed %d: top streaks out of order: %+v", seed, s.Top)
+			}
+		}
+		for _, r := range s.Top {
+			if daysBetween(r.Start, r.End)+1 != r.Days {
+				t.Fatalf("seed %d: streak %+v spans a different number of days", seed, r)
+			}
+		}
+	}
+}
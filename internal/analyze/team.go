@@ -0,0 +1,180 @@
+package analyze
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
+)
+
+// TeamMember is one member's collected data and finished recap.
+type TeamMember struct {
+	Login         string
+	Recap         *model.Recap
+	Contributions *model.ContributionsCollection
+	// ReviewedAuthors counts PRs this member reviewed, per PR author.
+	ReviewedAuthors map[string]int
+	// ReviewsError says why ReviewedAuthors is missing or partial; the member
+	// is then listed in Meta.Failed and the team recap is not complete.
+	ReviewsError string
+}
+
+// TeamInput is everything BuildTeamRecap needs.
+type TeamInput struct {
+	Team        string
+	Window      period.Window
+	Location    *time.Location
+	Members     []TeamMember
+	Failed      []model.MemberError
+	Interrupted string
+	// Now stamps Meta.GeneratedAt; zero means the current time.
+	Now time.Time
+}
+
+// BuildTeamRecap combines member recaps into team totals, a merged calendar,
+// per-metric leaderboards, repositories shared by several members and who
+// reviewed whom inside the team.
+func BuildTeamRecap(in TeamInput) *model.TeamRecap {
+	loc := in.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var t model.TeamRecap
+	t.Meta.Team = in.Team
+	t.Meta.Year = in.Window.From.Year()
+	t.Meta.From = in.Window.From
+	t.Meta.To = in.Window.To
+	t.Meta.Period = in.Window.Label
+	t.Meta.Timezone = period.ZoneName(loc, in.Window.From)
+	t.Meta.GeneratedAt = generatedAt(in.Now)
+	t.Meta.Interrupted = in.Interrupted
+
+	members := append([]TeamMember(nil), in.Members...)
+	sort.Slice(members, func(i, j int) bool { return members[i].Login < members[j].Login })
+	inTeam := map[string]bool{}
+	t.Meta.Failed = append([]model.MemberError(nil), in.Failed...)
+	for _, m := range members {
+		inTeam[m.Login] = true
+		t.Meta.Members = append(t.Meta.Members, m.Login)
+		if m.ReviewsError != "" {
+			t.Meta.Failed = append(t.Meta.Failed, model.MemberError{Login: m.Login, Error: m.ReviewsError})
+		}
+	}
+	sort.SliceStable(t.Meta.Failed, func(i, j int) bool { return t.Meta.Failed[i].Login < t.Meta.Failed[j].Login })
+	t.Meta.Complete = len(t.Meta.Failed) == 0 && in.Interrupted == ""
+
+	// Totals + merged calendar
+	daySum := map[string]int{}
+	for _, m := range members {
+		r := m.Recap
+		t.Totals.Commits += r.Totals.Commits
+		t.Totals.PullRequests += r.Totals.PullRequests
+		t.Totals.Issues += r.Totals.Issues
+		t.Totals.Reviews += r.Totals.Reviews
+		t.Totals.PRsMerged += r.PRStats.Merged
+		t.Meta.Complete = t.Meta.Complete && r.Meta.Complete
+		for _, d := range r.Calendar.Days {
+			daySum[d.Date] += d.Count
+		}
+	}
+	t.Totals.Overall = t.Totals.Commits + t.Totals.PullRequests + t.Totals.Issues + t.Totals.Reviews
+
+	t.Calendar.Days = make([]model.ContributionDay, 0, len(daySum))
+	for date, n := range daySum {
+		t.Calendar.Days = append(t.Calendar.Days, model.ContributionDay{Date: date, Count: n})
+	}
+	sort.Slice(t.Calendar.Days, func(i, j int) bool { return t.Calendar.Days[i].Date < t.Calendar.Days[j].Date })
+	t.Calendar.LongestStreak = longestStreak(t.Calendar.Days)
+	t.Calendar.MostProductiveDay = mostProductiveDay(t.Calendar.Days)
+
+	// Leaderboards
+	metrics := map[string]func(r *model.Recap) float64{
+		"commits":        func(r *model.Recap) float64 { return float64(r.Totals.Commits) },
+		"pull_requests":  func(r *model.Recap) float64 { return float64(r.Totals.PullRequests) },
+		"issues":         func(r *model.Recap) float64 { return float64(r.Totals.Issues) },
+		"reviews":        func(r *model.Recap) float64 { return float64(r.Totals.Reviews) },
+		"overall":        func(r *model.Recap) float64 { return float64(r.Totals.Overall) },
+		"prs_merged":     func(r *model.Recap) float64 { return float64(r.PRStats.Merged) },
+		"longest_streak": func(r *model.Recap) float64 { return float64(r.Calendar.LongestStreak) },
+	}
+	t.Leaderboards = make(map[string][]model.LeaderboardEntry, len(metrics))
+	for name, value := range metrics {
+		board := make([]model.LeaderboardEntry, 0, len(members))
+		for _, m := range members {
+			board = append(board, model.LeaderboardEntry{Login: m.Login, Value: value(m.Recap)})
+		}
+		sort.SliceStable(board, func(i, j int) bool { return board[i].Value > board[j].Value })
+		t.Leaderboards[name] = board
+	}
+
+	t.SharedRepos = sharedRepos(members, 20)
+
+	// Who reviewed whom, restricted to PRs authored inside the team
+	for _, m := range members {
+		for author, n := range m.ReviewedAuthors {
+			if !inTeam[author] || author == m.Login {
+				continue
+			}
+			t.Reviews.Pairs = append(t.Reviews.Pairs, model.ReviewPair{Reviewer: m.Login, Author: author, Count: n})
+			t.Reviews.CrossMember += n
+		}
+	}
+	sort.Slice(t.Reviews.Pairs, func(i, j int) bool {
+		a, b := t.Reviews.Pairs[i], t.Reviews.Pairs[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Reviewer != b.Reviewer {
+			return a.Reviewer < b.Reviewer
+		}
+		return a.Author < b.Author
+	})
+
+	return &t
+}
+
+// sharedRepos returns repositories at least two members were active in,
+// ranked by the team's combined activity there.
+func sharedRepos(members []TeamMember, n int) []model.SharedRepo {
+	byRepo := map[string]*model.SharedRepo{}
+	for _, m := range members {
+		if m.Contributions == nil {
+			continue
+		}
+		for _, r := range repoActivity(m.Contributions) {
+			s, ok := byRepo[r.Repo]
+			if !ok {
+				s = &model.SharedRepo{Repo: r.Repo, IsPrivate: r.IsPrivate}
+				byRepo[r.Repo] = s
+			}
+			s.TotalActivity += r.TotalActivity
+			s.Members = append(s.Members, model.MemberActivity{Login: m.Login, Activity: r.TotalActivity})
+		}
+	}
+
+	out := make([]model.SharedRepo, 0, len(byRepo))
+	for _, s := range byRepo {
+		if len(s.Members) < 2 {
+			continue
+		}
+		sort.Slice(s.Members, func(i, j int) bool {
+			if s.Members[i].Activity == s.Members[j].Activity {
+				return s.Members[i].Login < s.Members[j].Login
+			}
+			return s.Members[i].Activity > s.Members[j].Activity
+		})
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalActivity == out[j].TotalActivity {
+			return out[i].Repo < out[j].Repo
+		}
+		return out[i].TotalActivity > out[j].TotalActivity
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package analyze
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// genMember is a generated member: genInput's data for seed under login.
+func genMember(seed int64, login string) TeamMember {
+	in := genInput(seed, yearWindow(2025, time.UTC), time.UTC)
+	in.User = login
+	return TeamMember{Login: login, Recap: BuildRecap(in), Contributions: in.Contributions}
+}
+
+func TestBuildTeamRecap(t *testing.T) {
+	w := yearWindow(2025, time.UTC)
+	members := []TeamMember{genMember(3, "carol"), genMember(1, "alice"), genMember(2, "bob")}
+	tr := BuildTeamRecap(TeamInput{Team: "org:acme", Window: w, Location: time.UTC, Members: members, Now: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)})
+
+	if !reflect.DeepEqual(tr.Meta.Members, []string{"alice", "bob", "carol"}) {
+		t.Errorf("Members = %v, want sorted logins", tr.Meta.Members)
+	}
+	if !tr.Meta.Complete || tr.Meta.Timezone != "UTC" || tr.Meta.Period != "2025" {
+		t.Errorf("Meta = %+v", tr.Meta)
+	}
+
+	var commits, prs, issues, reviews, merged int
+	daySum := map[string]int{}
+	for _, m := range members {
+		commits += m.Recap.Totals.Commits
+		prs += m.Recap.Totals.PullRequests
+		issues += m.Recap.Totals.Issues
+		reviews += m.Recap.Totals.Reviews
+		merged += m.Recap.PRStats.Merged
+		for _, d := range m.Recap.Calendar.Days {
+			daySum[d.Date] += d.Count
+		}
+	}
+	if tr.Totals.Commits != commits || tr.Totals.PullRequests != prs || tr.Totals.Issues != issues || tr.Totals.Reviews != reviews || tr.Totals.PRsMerged != merged {
+		t.Errorf("Totals = %+v, want the members' sums", tr.Totals)
+	}
+	if tr.Totals.Overall != commits+prs+issues+reviews {
+		t.Errorf("Overall = %d, want %d", tr.Totals.Overall, commits+prs+issues+reviews)
+	}
+
+	// The merged calendar is the per-day sum, sorted, with its streak and
+	// busiest day recomputed.
+	if len(tr.Calendar.Days) != len(daySum) {
+		t.Fatalf("calendar has %d days, want %d", len(tr.Calendar.Days), len(daySum))
+	}
+	for i, d := range tr.Calendar.Days {
+		if d.Count != daySum[d.Date] {
+			t.Errorf("%s = %d, want %d", d.Date, d.Count, daySum[d.Date])
+		}
+		if i > 0 && tr.Calendar.Days[i-1].Date >= d.Date {
+			t.Errorf("calendar out of order at %s", d.Date)
+		}
+	}
+	if got := longestStreak(tr.Calendar.Days); tr.Calendar.LongestStreak != got {
+		t.Errorf("LongestStreak = %d, want %d", tr.Calendar.LongestStreak, got)
+	}
+	if tr.Calendar.MostProductiveDay != mostProductiveDay(tr.Calendar.Days) {
+		t.Errorf("MostProductiveDay = %+v", tr.Calendar.MostProductiveDay)
+	}
+
+	// Every leaderboard ranks every member, highest first.
+	for _, name := range []string{"commits", "pull_requests", "issues", "reviews", "overall", "prs_merged", "longest_streak"} {
+		board := tr.Leaderboards[name]
+		if len(board) != len(members) {
+			t.Errorf("%s: %d entries, want %d", name, len(board), len(members))
+			continue
+		}
+		if !sort.SliceIsSorted(board, func(i, j int) bool { return board[i].Value > board[j].Value }) {
+			t.Errorf("%s not ranked: %+v", name, board)
+		}
+	}
+	if top := tr.Leaderboards["commits"][0]; top.Value != float64(maxCommits(members)) {
+		t.Errorf("commits leader = %+v", top)
+	}
+}
+
+func maxCommits(members []TeamMember) int {
+	n := 0
+	for _, m := range members {
+		n = max(n, m.Recap.Totals.Commits)
+	}
+	return n
+}
+
+func TestBuildTeamRecapLeaderboardTies(t *testing.T) {
+	recap := func(commits int) *model.Recap {
+		r := &model.Recap{}
+		r.Totals.Commits = commits
+		r.Meta.Complete = true
+		return r
+	}
+	tr := BuildTeamRecap(TeamInput{Members: []TeamMember{
+		{Login: "carol", Recap: recap(5)},
+		{Login: "bob", Recap: recap(9)},
+		{Login: "alice", Recap: recap(5)},
+	}})
+	want := []model.LeaderboardEntry{{Login: "bob", Value: 9}, {Login: "alice", Value: 5}, {Login: "carol", Value: 5}}
+	if !reflect.DeepEqual(tr.Leaderboards["commits"], want) {
+		t.Errorf("commits = %+v, want ties in login order %+v", tr.Leaderboards["commits"], want)
+	}
+}
+
+func TestSharedRepos(t *testing.T) {
+	contrib := func(commits map[string]int, reviews map[string]int) *model.ContributionsCollection {
+		cc := &model.ContributionsCollection{ByRepoCommits: map[string]model.RepoContribLite{}, ByRepoReviews: map[string]model.RepoContribLite{}}
+		for r, n := range commits {
+			cc.ByRepoCommits[r] = model.RepoContribLite{Repo: r, Count: n}
+		}
+		for r, n := range reviews {
+			cc.ByRepoReviews[r] = model.RepoContribLite{Repo: r, Count: n}
+		}
+		return cc
+	}
+	members := []TeamMember{
+		{Login: "alice", Contributions: contrib(map[string]int{"acme/api": 10, "acme/web": 2, "alice/dotfiles": 50}, nil)},
+		{Login: "bob", Contributions: contrib(map[string]int{"acme/api": 3}, map[string]int{"acme/web": 2, "acme/cli": 1})},
+		{Login: "carol", Contributions: contrib(map[string]int{"acme/web": 4}, nil)},
+		{Login: "dave"}, // nothing collected
+	}
+	got := sharedRepos(members, 20)
+	want := []model.SharedRepo{
+		{Repo: "acme/api", TotalActivity: 13, Members: []model.MemberActivity{{Login: "alice", Activity: 10}, {Login: "bob", Activity: 3}}},
+		{Repo: "acme/web", TotalActivity: 8, Members: []model.MemberActivity{{Login: "carol", Activity: 4}, {Login: "alice", Activity: 2}, {Login: "bob", Activity: 2}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sharedRepos = %+v, want %+v", got, want)
+	}
+	if got := sharedRepos(members, 1); len(got) != 1 || got[0].Repo != "acme/api" {
+		t.Errorf("capped at one: %+v", got)
+	}
+}
+
+func TestTeamReviewPairs(t *testing.T) {
+	empty := &model.Recap{}
+	empty.Meta.Complete = true
+	tr := BuildTeamRecap(TeamInput{Members: []TeamMember{
+		{Login: "alice", Recap: empty, ReviewedAuthors: map[string]int{"bob": 4, "carol": 1, "outsider": 7, "alice": 2}},
+		{Login: "bob", Recap: empty, ReviewedAuthors: map[string]int{"alice": 4}},
+		{Login: "carol", Recap: empty},
+	}})
+	want := []model.ReviewPair{
+		{Reviewer: "alice", Author: "bob", Count: 4},
+		{Reviewer: "bob", Author: "alice", Count: 4},
+		{Reviewer: "alice", Author: "carol", Count: 1},
+	}
+	if !reflect.DeepEqual(tr.Reviews.Pairs, want) {
+		t.Errorf("Pairs = %+v, want %+v", tr.Reviews.Pairs, want)
+	}
+	// Outsiders and self-reviews don't count.
+	if tr.Reviews.CrossMember != 9 {
+		t.Errorf("CrossMember = %d, want 9", tr.Reviews.CrossMember)
+	}
+	if !tr.Meta.Complete || len(tr.Meta.Failed) != 0 {
+		t.Errorf("Meta = %+v, want complete", tr.Meta)
+	}
+}
+
+func TestTeamCompleteness(t *testing.T) {
+	done := &model.Recap{}
+	done.Meta.Complete = true
+	partial := &model.Recap{}
+
+	tests := []struct {
+		name     string
+		in       TeamInput
+		complete bool
+		failed   []model.MemberError
+	}{
+		{"all collected", TeamInput{Members: []TeamMember{{Login: "alice", Recap: done}}}, true, nil},
+		{"member recap incomplete", TeamInput{Members: []TeamMember{{Login: "alice", Recap: partial}}}, false, nil},
+		{"member failed", TeamInput{
+			Members: []TeamMember{{Login: "alice", Recap: done}},
+			Failed:  []model.MemberError{{Login: "bob", Error: "boom"}},
+		}, false, []model.MemberError{{Login: "bob", Error: "boom"}}},
+		{"review pairs partial", TeamInput{
+			Members: []TeamMember{
+				{Login: "carol", Recap: done, ReviewsError: "reviewed PRs search: rate limited"},
+				{Login: "alice", Recap: done},
+			},
+			Failed: []model.MemberError{{Login: "bob", Error: "boom"}},
+		}, false, []model.MemberError{{Login: "bob", Error: "boom"}, {Login: "carol", Error: "reviewed PRs search: rate limited"}}},
+		{"interrupted", TeamInput{Members: []TeamMember{{Login: "alice", Recap: done}}, Interrupted: "signal: interrupt"}, false, nil},
+	}
+	for _, tt := range tests {
+		tr := BuildTeamRecap(tt.in)
+		if tr.Meta.Complete != tt.complete {
+			t.Errorf("%s: Complete = %v, want %v", tt.name, tr.Meta.Complete, tt.complete)
+		}
+		if !reflect.DeepEqual(tr.Meta.Failed, tt.failed) && len(tr.Meta.Failed)+len(tt.failed) > 0 {
+			t.Errorf("%s: Failed = %+v, want %+v", tt.name, tr.Meta.Failed, tt.failed)
+		}
+	}
+}
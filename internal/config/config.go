@@ -2,33 +2,158 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dotcomAPI     = "https://api.github.com"
+	dotcomGraphQL = "https://api.github.com/graphql"
 )
 
 type Config struct {
-	Token      string
+	// Token is a classic or fine-grained personal access token. When it is
+	// empty the credentials come from the GitHub App settings, TokenFile or
+	// the gh CLI, in that order (see githubapi.New).
+	Token string
+	// TokenFile holds a token on its first non-blank line.
+	TokenFile string
+	// AppID and AppPrivateKey (a PEM file path, or the PEM itself)
+	// authenticate as a GitHub App installation. AppInstallationID may be
+	// left zero when the app is installed exactly once.
+	AppID             int64
+	AppPrivateKey     string
+	AppInstallationID int64
+
 	APIBase    string
 	GraphQLEnd string
+
+	// UploadBase is only used against GitHub Enterprise Server; empty means
+	// derive it from APIBase.
+	UploadBase string
+	// CABundle is a PEM file of extra CAs to trust (self-signed GHES).
+	CABundle string
+	// InsecureSkipVerify disables TLS verification, for lab instances only.
+	InsecureSkipVerify bool
+
+	// CacheDir enables the on-disk response cache when non-empty.
+	CacheDir string
+	// CacheTTL is how long cached GraphQL responses are reused for windows
+	// that are still open. REST responses are always revalidated.
+	CacheTTL time.Duration
+
+	// RecordDir, when set, saves every API exchange there. ReplayDir answers
+	// every request from such recordings instead of the network, and needs no
+	// credentials.
+	RecordDir string
+	ReplayDir string
 }
 
 func FromEnv() (Config, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return Config{}, fmt.Errorf("GITHUB_TOKEN is required (set it in env or .env)")
+	appID, err := envInt("GITHUB_APP_ID")
+	if err != nil {
+		return Config{}, err
+	}
+	installation, err := envInt("GITHUB_APP_INSTALLATION_ID")
+	if err != nil {
+		return Config{}, err
+	}
+	appKey := os.Getenv("GITHUB_APP_PRIVATE_KEY")
+	if (appID != 0) != (appKey != "") {
+		return Config{}, fmt.Errorf("GITHUB_APP_ID and GITHUB_APP_PRIVATE_KEY must be set together")
 	}
 
 	api := os.Getenv("GITHUB_API_BASE")
-	if api == "" {
-		api = "https://api.github.com"
-	}
 	gql := os.Getenv("GITHUB_GRAPHQL")
-	if gql == "" {
-		gql = "https://api.github.com/graphql"
+	// Setting just one of the two endpoints for an Enterprise host is enough;
+	// GHES serves REST under /api/v3 and GraphQL under /api/graphql.
+	switch {
+	case api == "" && gql == "":
+		api, gql = dotcomAPI, dotcomGraphQL
+	case api == "":
+		api = enterpriseSibling(gql, "/api/v3", dotcomAPI)
+	case gql == "":
+		gql = enterpriseSibling(api, "/api/graphql", dotcomGraphQL)
+	}
+
+	insecure := false
+	if v := os.Getenv("GITHUB_INSECURE_SKIP_VERIFY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("GITHUB_INSECURE_SKIP_VERIFY: %w", err)
+		}
+		insecure = b
 	}
 
 	return Config{
-		Token:      token,
-		APIBase:    api,
-		GraphQLEnd: gql,
+		Token:              os.Getenv("GITHUB_TOKEN"),
+		TokenFile:          os.Getenv("GITHUB_TOKEN_FILE"),
+		AppID:              appID,
+		AppPrivateKey:      appKey,
+		AppInstallationID:  installation,
+		APIBase:            api,
+		GraphQLEnd:         gql,
+		UploadBase:         os.Getenv("GITHUB_UPLOAD_BASE"),
+		CABundle:           os.Getenv("GITHUB_CA_BUNDLE"),
+		InsecureSkipVerify: insecure,
+		CacheDir:           os.Getenv("RECAP_CACHE_DIR"),
+		CacheTTL:           6 * time.Hour,
 	}, nil
 }
+
+func envInt(name string) (int64, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return n, nil
+}
+
+// Host is the web host of the GitHub instance ("github.com" or the GHES
+// host), as the gh CLI names it.
+func (c Config) Host() string {
+	if !c.IsEnterprise() {
+		return "github.com"
+	}
+	u, err := url.Parse(c.APIBase)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// IsEnterprise reports whether the endpoints point at a GitHub Enterprise
+// Server instead of github.com.
+func (c Config) IsEnterprise() bool {
+	return !isDotcom(c.APIBase) || !isDotcom(c.GraphQLEnd)
+}
+
+func isDotcom(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	return u.Host == "api.github.com" || u.Host == "github.com"
+}
+
+// enterpriseSibling derives one GHES endpoint from the other
+// (https://ghe.example.com/api/graphql <-> https://ghe.example.com/api/v3).
+func enterpriseSibling(endpoint, path, dotcom string) string {
+	if isDotcom(endpoint) {
+		return dotcom
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return dotcom
+	}
+	u.Path = path
+	u.RawQuery = ""
+	return strings.TrimSuffix(u.String(), "/")
+}
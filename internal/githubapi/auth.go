@@ -0,0 +1,274 @@
+package githubapi
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// Auth methods, as reported by Client.AuthMethod and in the preflight.
+const (
+	AuthToken  = "token"      // GITHUB_TOKEN
+	AuthFile   = "token_file" // GITHUB_TOKEN_FILE
+	AuthApp    = "app"        // GitHub App installation
+	AuthGH     = "gh"         // gh auth token
+	AuthReplay = "replay"     // offline, answered from recordings (--replay)
+)
+
+// tokenSource picks the credentials: GITHUB_TOKEN, then a GitHub App, then a
+// token file, then whatever the gh CLI is logged in with. App installation
+// tokens expire after an hour and are refreshed transparently.
+func tokenSource(cfg config.Config, transport http.RoundTripper) (oauth2.TokenSource, string, *appTokenSource, error) {
+	switch {
+	case cfg.Token != "":
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cfg.Token}), AuthToken, nil, nil
+	case cfg.AppID != 0:
+		app, err := newAppTokenSource(cfg, transport)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return oauth2.ReuseTokenSource(nil, app), AuthApp, app, nil
+	case cfg.TokenFile != "":
+		tok, err := readTokenFile(cfg.TokenFile)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: tok}), AuthFile, nil, nil
+	}
+	tok, err := ghToken(cfg.Host())
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("no credentials: set GITHUB_TOKEN, GITHUB_APP_ID + GITHUB_APP_PRIVATE_KEY or GITHUB_TOKEN_FILE, or log in with `gh auth login` (%v)", err)
+	}
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: tok}), AuthGH, nil, nil
+}
+
+func readTokenFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("token file: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if tok := strings.TrimSpace(sc.Text()); tok != "" {
+			return tok, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("token file: %w", err)
+	}
+	return "", fmt.Errorf("token file %s is empty", path)
+}
+
+func ghToken(host string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("gh auth token: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("gh auth token: %w", err)
+	}
+	tok := strings.TrimSpace(string(out))
+	if tok == "" {
+		return "", fmt.Errorf("gh auth token: no token for %s", host)
+	}
+	return tok, nil
+}
+
+// tokenKind names a token by its prefix; the kind decides how the preflight
+// can find out what the token is allowed to do.
+func tokenKind(tok string) string {
+	switch {
+	case strings.HasPrefix(tok, "ghp_"):
+		return "classic_pat"
+	case strings.HasPrefix(tok, "github_pat_"):
+		return "fine_grained_pat"
+	case strings.HasPrefix(tok, "gho_"):
+		return "oauth"
+	case strings.HasPrefix(tok, "ghs_"):
+		return "app_installation"
+	case strings.HasPrefix(tok, "ghu_"):
+		return "app_user"
+	}
+	return "unknown"
+}
+
+// appTokenSource exchanges a short-lived JWT signed with the app's private
+// key for an installation access token.
+type appTokenSource struct {
+	apiBase        string
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	http           *http.Client
+
+	mu          sync.Mutex
+	permissions map[string]string
+}
+
+func newAppTokenSource(cfg config.Config, transport http.RoundTripper) (*appTokenSource, error) {
+	pemBytes := []byte(cfg.AppPrivateKey)
+	if !strings.Contains(cfg.AppPrivateKey, "-----BEGIN") {
+		b, err := os.ReadFile(cfg.AppPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY: %w", err)
+		}
+		pemBytes = b
+	}
+	key, err := parseRSAKey(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY: %w", err)
+	}
+	return &appTokenSource{
+		apiBase:        strings.TrimSuffix(cfg.APIBase, "/"),
+		appID:          cfg.AppID,
+		installationID: cfg.AppInstallationID,
+		key:            key,
+		http:           &http.Client{Transport: transport, Timeout: time.Minute},
+	}, nil
+}
+
+func parseRSAKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return key, nil
+}
+
+// Token implements oauth2.TokenSource; oauth2.ReuseTokenSource calls it again
+// shortly before the previous token expires.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.installation(jwt)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Token       string            `json:"token"`
+		ExpiresAt   time.Time         `json:"expires_at"`
+		Permissions map[string]string `json:"permissions"`
+	}
+	if err := s.call("POST", fmt.Sprintf("/app/installations/%d/access_tokens", id), jwt, &out); err != nil {
+		return nil, fmt.Errorf("installation token: %w", err)
+	}
+	s.mu.Lock()
+	s.permissions = out.Permissions
+	s.mu.Unlock()
+	return &oauth2.Token{AccessToken: out.Token, Expiry: out.ExpiresAt}, nil
+}
+
+// installation returns the configured installation, or the app's only one.
+func (s *appTokenSource) installation(jwt string) (int64, error) {
+	if s.installationID != 0 {
+		return s.installationID, nil
+	}
+	var installs []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := s.call("GET", "/app/installations?per_page=100", jwt, &installs); err != nil {
+		return 0, fmt.Errorf("list app installations: %w", err)
+	}
+	switch len(installs) {
+	case 0:
+		return 0, fmt.Errorf("GitHub App %d has no installations", s.appID)
+	case 1:
+		s.installationID = installs[0].ID
+		return s.installationID, nil
+	}
+	var ids []string
+	for _, in := range installs {
+		ids = append(ids, fmt.Sprintf("%d (%s)", in.ID, in.Account.Login))
+	}
+	return 0, fmt.Errorf("GitHub App %d has %d installations, set GITHUB_APP_INSTALLATION_ID to one of: %s", s.appID, len(installs), strings.Join(ids, ", "))
+}
+
+func (s *appTokenSource) call(method, path, jwt string, out any) error {
+	req, err := http.NewRequest(method, s.apiBase+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("http %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// jwt signs the app's identity (RS256). GitHub accepts at most ten minutes of
+// validity; iat is backdated to absorb clock drift.
+func (s *appTokenSource) jwt(now time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	signing := header + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("sign app JWT: %w", err)
+	}
+	return signing + "." + enc.EncodeToString(sig), nil
+}
+
+// grantedPermissions returns the permissions of the current installation
+// token, or nil before the first token was issued.
+func (s *appTokenSource) grantedPermissions() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.permissions
+}
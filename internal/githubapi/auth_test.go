@@ -0,0 +1,308 @@
+package githubapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+)
+
+func TestTokenKind(t *testing.T) {
+	tests := map[string]string{
+		"ghp_abc123":         "classic_pat",
+		"github_pat_11AB_cd": "fine_grained_pat",
+		"gho_abc":            "oauth",
+		"ghs_abc":            "app_installation",
+		"ghu_abc":            "app_user",
+		"ghr_abc":            "unknown", // refresh tokens never reach the API
+		"0123456789abcdef":   "unknown", // pre-2021 tokens had no prefix
+		"":                   "unknown",
+	}
+	for tok, want := range tests {
+		if got := tokenKind(tok); got != want {
+			t.Errorf("tokenKind(%q) = %s, want %s", tok, got, want)
+		}
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// The first non-blank line, trimmed.
+	if tok, err := readTokenFile(write("token", "\n   \n  ghp_first  \nghp_second\n")); err != nil || tok != "ghp_first" {
+		t.Errorf("readTokenFile = %q, %v", tok, err)
+	}
+	if _, err := readTokenFile(write("blank", "\n \t\n")); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("blank file: %v", err)
+	}
+	if _, err := readTokenFile(filepath.Join(dir, "missing")); err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: %v", err)
+	}
+}
+
+// fakeGH puts a gh executable running script on PATH.
+func fakeGH(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script on PATH")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestTokenSource(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token")
+	if err := os.WriteFile(file, []byte("ghp_fromfile\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fakeGH(t, `[ "$*" = "auth token --hostname ghe.example.com" ] && echo "  gho_fromgh  " && exit 0; echo "not logged in to $4" >&2; exit 1`)
+	ghes := config.Config{APIBase: "https://ghe.example.com/api/v3", GraphQLEnd: "https://ghe.example.com/api/graphql"}
+
+	tests := []struct {
+		name   string
+		cfg    config.Config
+		method string
+		token  string
+	}{
+		{"token first", config.Config{Token: "ghp_env", TokenFile: file, AppID: 1}, AuthToken, "ghp_env"},
+		{"token file", config.Config{TokenFile: file}, AuthFile, "ghp_fromfile"},
+		{"gh for the configured host", ghes, AuthGH, "gho_fromgh"},
+	}
+	for _, tt := range tests {
+		src, method, app, err := tokenSource(tt.cfg, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		tok, err := src.Token()
+		if err != nil || method != tt.method || tok.AccessToken != tt.token || app != nil {
+			t.Errorf("%s: %s %v %v, want %s %s", tt.name, method, tok, err, tt.method, tt.token)
+		}
+	}
+
+	// gh logged in elsewhere: its message is part of the error.
+	dotcom := config.Config{APIBase: "https://api.github.com", GraphQLEnd: "https://api.github.com/graphql"}
+	if _, _, _, err := tokenSource(dotcom, nil); err == nil || !strings.Contains(err.Error(), "not logged in to github.com") {
+		t.Errorf("gh failure: %v", err)
+	}
+}
+
+// appServer is a fake GitHub App API: it checks the JWT on every call and
+// hands out installation tokens with permissions.
+type appServer struct {
+	*httptest.Server
+	t        *testing.T
+	key      *rsa.PublicKey
+	installs []int64
+
+	mu     sync.Mutex
+	listed int
+	issued []string // installation paths tokens were issued for
+}
+
+func newAppServer(t *testing.T, key *rsa.PublicKey, installs ...int64) *appServer {
+	s := &appServer{t: t, key: key, installs: installs}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *appServer) serve(w http.ResponseWriter, r *http.Request) {
+	jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		http.Error(w, `{"message":"Requires authentication"}`, http.StatusUnauthorized)
+		return
+	}
+	if err := s.verify(jwt); err != nil {
+		s.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		http.Error(w, `{"message":"A JSON web token could not be decoded"}`, http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/v3/app/installations":
+		s.listed++
+		var out []map[string]any
+		for _, id := range s.installs {
+			out = append(out, map[string]any{"id": id, "account": map[string]any{"login": fmt.Sprintf("org%d", id)}})
+		}
+		json.NewEncoder(w).Encode(out)
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/access_tokens"):
+		s.issued = append(s.issued, r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{
+			"token":       "ghs_installation",
+			"expires_at":  time.Now().Add(time.Hour),
+			"permissions": map[string]string{"metadata": "read", "pull_requests": "read", "issues": "write"},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// verify checks the JWT's RS256 signature and its claims.
+func (s *appServer) verify(jwt string) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("JWT has %d parts", len(parts))
+	}
+	enc := base64.RawURLEncoding
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(s.key, crypto.SHA256, sum[:], sig); err != nil {
+		return fmt.Errorf("signature: %w", err)
+	}
+	var header struct{ Alg, Typ string }
+	var claims struct {
+		Iat, Exp int64
+		Iss      string
+	}
+	for i, dst := range []any{&header, &claims} {
+		b, err := enc.DecodeString(parts[i])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, dst); err != nil {
+			return err
+		}
+	}
+	now := time.Now().Unix()
+	switch {
+	case header.Alg != "RS256" || header.Typ != "JWT":
+		return fmt.Errorf("header %+v", header)
+	case claims.Iss != "1234":
+		return fmt.Errorf("iss %q, want the app ID", claims.Iss)
+	case claims.Iat > now-30 || claims.Iat < now-90:
+		return fmt.Errorf("iat %d is not backdated a minute from %d", claims.Iat, now)
+	case claims.Exp-claims.Iat > 600 || claims.Exp <= now:
+		return fmt.Errorf("exp %d: more than ten minutes after iat %d, or expired", claims.Exp, claims.Iat)
+	}
+	return nil
+}
+
+func testKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, pkcs1 := testKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The key may also be a path to a PKCS#8 file.
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		installs   []int64
+		configured int64
+		issued     string // installation the token is for; empty for an error
+		err        string
+		listed     int
+	}{
+		{name: "only installation", key: pkcs1, installs: []int64{42}, issued: "/api/v3/app/installations/42/access_tokens", listed: 1},
+		{name: "key file", key: keyFile, installs: []int64{42}, issued: "/api/v3/app/installations/42/access_tokens", listed: 1},
+		{name: "configured installation", key: pkcs1, installs: []int64{42, 43}, configured: 43, issued: "/api/v3/app/installations/43/access_tokens"},
+		{name: "several installations", key: pkcs1, installs: []int64{42, 43}, err: "set GITHUB_APP_INSTALLATION_ID to one of: 42 (org42), 43 (org43)", listed: 1},
+		{name: "no installation", key: pkcs1, err: "has no installations", listed: 1},
+	}
+	for _, tt := range tests {
+		srv := newAppServer(t, &key.PublicKey, tt.installs...)
+		cfg := config.Config{AppID: 1234, AppPrivateKey: tt.key, AppInstallationID: tt.configured, APIBase: srv.URL + "/api/v3/"}
+		src, method, app, err := tokenSource(cfg, nil)
+		if err != nil || method != AuthApp || app == nil {
+			t.Fatalf("%s: tokenSource = %s, %v", tt.name, method, err)
+		}
+		if app.grantedPermissions() != nil {
+			t.Errorf("%s: permissions before the first token", tt.name)
+		}
+
+		tok, err := src.Token()
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.err)
+			}
+		} else if err != nil || tok.AccessToken != "ghs_installation" || time.Until(tok.Expiry) < 50*time.Minute {
+			t.Errorf("%s: token %+v, %v", tt.name, tok, err)
+		} else if p := app.grantedPermissions(); p["pull_requests"] != "read" {
+			t.Errorf("%s: permissions = %v", tt.name, p)
+		}
+
+		// The token is reused until it expires, and the installation is
+		// looked up once.
+		if tt.err == "" {
+			src.Token()
+		}
+		if tt.issued != "" && (len(srv.issued) != 1 || srv.issued[0] != tt.issued) {
+			t.Errorf("%s: tokens issued for %v, want %s", tt.name, srv.issued, tt.issued)
+		}
+		if srv.listed != tt.listed {
+			t.Errorf("%s: installations listed %d times, want %d", tt.name, srv.listed, tt.listed)
+		}
+	}
+}
+
+func TestAppTokenSourceBadKey(t *testing.T) {
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ name, key, err string }{
+		{"not PEM", "-----BEGIN nothing", "no PEM block found"},
+		{"EC key", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), "private key is not RSA"},
+		{"no file", filepath.Join(t.TempDir(), "missing.pem"), "no such file"},
+	}
+	for _, tt := range tests {
+		_, err := newAppTokenSource(config.Config{AppID: 1, AppPrivateKey: tt.key}, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), "GITHUB_APP_PRIVATE_KEY: ") || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// cacheTransport is a content-addressed on-disk HTTP cache in front of the
+// rate limiter.
+//
+// REST GETs are always revalidated with If-None-Match / If-Modified-Since, so
+// unchanged pages come back as 304s that GitHub does not charge for. GraphQL
+// has no validators; a stored response is reused while it is younger than ttl,
+// or indefinitely when the caller marked the query's window as closed before
+// the response was stored (see withClosedWindow).
+type cacheTransport struct {
+	dir  string
+	ttl  time.Duration
+	next http.RoundTripper
+	// identity names the credential when the token itself rotates (GitHub
+	// App installations); otherwise the Authorization header does.
+	identity string
+
+	hits, revalidated, misses atomic.Int64
+}
+
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	StoredAt     time.Time   `json:"stored_at"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// CacheStats summarises how the on-disk cache served a run.
+type CacheStats struct {
+	Hits        int64 // served from disk without a request
+	Revalidated int64 // 304 Not Modified, served from disk
+	Misses      int64 // fetched and stored
+}
+
+// closedWindowSettle is how long after a window ends we keep treating its
+// data as live; late-arriving contributions (pushes with old dates, backfilled
+// stats) usually settle within a day.
+const closedWindowSettle = 24 * time.Hour
+
+type closedWindowKey struct{}
+
+// withClosedWindow marks GraphQL requests made with ctx as describing data that
+// stops changing once end has passed, so cached responses stored after that
+// point never need to be refetched.
+func withClosedWindow(ctx context.Context, end time.Time) context.Context {
+	return context.WithValue(ctx, closedWindowKey{}, end)
+}
+
+func newCacheTransport(dir string, ttl time.Duration, next http.RoundTripper) *cacheTransport {
+	return &cacheTransport{dir: dir, ttl: ttl, next: next}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case req.Method == http.MethodGet:
+		return t.roundTripREST(req)
+	case req.Method == http.MethodPost && guessResource(req) == "graphql":
+		return t.roundTripGraphQL(req)
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (t *cacheTransport) roundTripREST(req *http.Request) (*http.Response, error) {
+	key := cacheKey("GET", req.URL.String(), req.Header.Get("Accept"), t.credential(req))
+	entry, _ := t.load(key)
+
+	r := req
+	if entry != nil {
+		r = req.Clone(req.Context())
+		if entry.ETag != "" {
+			r.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			r.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		t.revalidated.Add(1)
+		entry.StoredAt = time.Now()
+		_ = t.store(key, entry)
+		cached := entry.response(req)
+		// Scopes describe the token making this request, not the cached one.
+		if v := resp.Header.Get("X-OAuth-Scopes"); v != "" {
+			cached.Header.Set("X-OAuth-Scopes", v)
+		}
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.misses.Add(1)
+	if resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" {
+		_ = t.store(key, &cacheEntry{
+			URL:          req.URL.String(),
+			StoredAt:     time.Now(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Header:       cacheableHeader(resp.Header),
+			Body:         body,
+		})
+	}
+	return resp, nil
+}
+
+func (t *cacheTransport) roundTripGraphQL(req *http.Request) (*http.Response, error) {
+	if req.GetBody == nil {
+		return t.next.RoundTrip(req)
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	key := cacheKey("POST", req.URL.String(), string(payload), t.credential(req))
+	if entry, _ := t.load(key); entry != nil && t.fresh(req.Context(), entry) {
+		t.hits.Add(1)
+		return entry.response(req), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.misses.Add(1)
+
+	// Never pin partial or failed GraphQL results.
+	var envelope struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+	if json.Unmarshal(body, &envelope) == nil && len(envelope.Errors) == 0 {
+		_ = t.store(key, &cacheEntry{
+			URL:      req.URL.String(),
+			StoredAt: time.Now(),
+			Header:   cacheableHeader(resp.Header),
+			Body:     body,
+		})
+	}
+	return resp, nil
+}
+
+// credential keys entries by who asked, so a response one token could see
+// (private contributions, search results, scopes) is never served to another.
+// It only ever reaches the disk hashed into the key.
+func (t *cacheTransport) credential(req *http.Request) string {
+	if t.identity != "" {
+		return t.identity
+	}
+	return req.Header.Get("Authorization")
+}
+
+func (t *cacheTransport) fresh(ctx context.Context, e *cacheEntry) bool {
+	if end, ok := ctx.Value(closedWindowKey{}).(time.Time); ok && e.StoredAt.After(end.Add(closedWindowSettle)) {
+		return true
+	}
+	return time.Since(e.StoredAt) < t.ttl
+}
+
+func (t *cacheTransport) stats() CacheStats {
+	return CacheStats{
+		Hits:        t.hits.Load(),
+		Revalidated: t.revalidated.Load(),
+		Misses:      t.misses.Load(),
+	}
+}
+
+func (t *cacheTransport) path(key string) string {
+	return filepath.Join(t.dir, key[:2], key+".json")
+}
+
+func (t *cacheTransport) load(key string) (*cacheEntry, error) {
+	b, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return nil, err
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// store writes via a temp file and rename so concurrent fetchers and an
+// interrupted run never leave a truncated entry behind.
+func (t *cacheTransport) store(key string, e *cacheEntry) error {
+	p := t.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	h := e.Header.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	// go-github skips its rate-limit bookkeeping for responses carrying
+	// this header, so stale X-RateLimit-* values are not trusted.
+	h.Set("X-From-Cache", "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func cacheKey(parts ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(h[:])
+}
+
+// cacheableHeader keeps what callers read from a response (pagination links,
+// content type) and drops per-request noise such as rate-limit counters.
+func cacheableHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for _, k := range []string{"Content-Type", "Link", "ETag", "Last-Modified"} {
+		if v := h.Values(k); len(v) > 0 {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package githubapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheKeyedByCredential(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, `{"data":{"seen_by":"`+r.Header.Get("Authorization")+`"}}`)
+	}))
+	defer srv.Close()
+
+	cache := newCacheTransport(t.TempDir(), time.Hour, http.DefaultTransport)
+	do := func(method, token string) string {
+		t.Helper()
+		var body io.Reader
+		if method == http.MethodPost {
+			body = strings.NewReader(`{"query":"{ viewer { login } }"}`)
+		}
+		req, err := http.NewRequest(method, srv.URL+"/graphql", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := cache.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		hits.Store(0)
+		if got := do(method, "alice"); !strings.Contains(got, "alice") {
+			t.Fatalf("%s: first response %s", method, got)
+		}
+		if got := do(method, "bob"); !strings.Contains(got, "bob") {
+			t.Errorf("%s: bob was served %s", method, got)
+		}
+		if got := do(method, "alice"); !strings.Contains(got, "alice") {
+			t.Errorf("%s: alice was served %s", method, got)
+		}
+		want := int64(2) // the repeat is a cache hit
+		if method == http.MethodGet {
+			want = 3 // REST revalidates, with alice's ETag
+		}
+		if n := hits.Load(); n != want {
+			t.Errorf("%s: %d requests reached the server, want %d", method, n, want)
+		}
+	}
+	if s := cache.stats(); s.Hits != 1 || s.Revalidated != 1 {
+		t.Errorf("stats = %+v, want one hit and one revalidation", s)
+	}
+
+	// App installations rotate tokens: the identity keys the cache instead.
+	cache.identity = "app 1/2"
+	hits.Store(0)
+	do(http.MethodPost, "token-1")
+	if got := do(http.MethodPost, "token-2"); !strings.Contains(got, "token-1") || hits.Load() != 1 {
+		t.Errorf("rotated token missed the cache: %s after %d requests", got, hits.Load())
+	}
+}
+
+func TestCacheClosedWindow(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		io.WriteString(w, `{"data":{}}`)
+	}))
+	defer srv.Close()
+
+	// A zero ttl refetches everything not marked closed.
+	cache := newCacheTransport(t.TempDir(), 0, http.DefaultTransport)
+	do := func(ctx context.Context) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/graphql", strings.NewReader(`{"query":"{ search }"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cache.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	open := withClosedWindow(context.Background(), time.Now().Add(searchSettle))
+	do(open)
+	do(open)
+	if hits.Load() != 2 {
+		t.Errorf("still-settling window: %d requests, want 2", hits.Load())
+	}
+	closed := withClosedWindow(context.Background(), time.Now().Add(-searchSettle))
+	do(closed)
+	do(closed)
+	if hits.Load() != 2 {
+		t.Errorf("closed window: %d requests, want the stored response reused", hits.Load())
+	}
+}
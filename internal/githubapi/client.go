@@ -3,28 +3,144 @@ package githubapi
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/dennislee928/github-recap-2025/internal/config"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/google/go-github/v62/github"
+	"golang.org/x/oauth2"
 )
 
 type Client struct {
 	cfg config.Config
 	http *http.Client
+	// transport is shared by the GraphQL client and every REST client so
+	// both APIs are throttled (and cached) from the same view of the budget.
+	transport http.RoundTripper
+	limiter *rateLimitTransport
+	cache *cacheTransport
+
+	// tokens authenticates every request; see tokenSource for where the
+	// credentials come from.
+	tokens oauth2.TokenSource
+	authMethod string
+	app *appTokenSource
+
+	// typeFields memoizes GraphQL introspection on Enterprise Server, where
+	// older versions lack fields our queries use (see hasField).
+	typeMu sync.Mutex
+	typeFields map[string]map[string]bool
+
+	// restClient is built once and shared by every REST caller (see rest).
+	restOnce sync.Once
+	restClient *github.Client
+	restErr error
+
+	// repos memoizes repository metadata across collectors (see RepoMetadata).
+	repos repoStore
 }
 
-func New(cfg config.Config) *Client {
-	return &Client{
+func New(cfg config.Config) (*Client, error) {
+	base, err := baseTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	limiter := newRateLimitTransport(base, 45*time.Second)
+	c := &Client{
 		cfg: cfg,
-		http: &http.Client{Timeout: 45 * time.Second},
+		transport: limiter,
+		limiter: limiter,
+	}
+	if cfg.CacheDir != "" {
+		c.cache = newCacheTransport(cfg.CacheDir, cfg.CacheTTL, limiter)
+		c.transport = c.cache
 	}
+	if cfg.ReplayDir != "" {
+		// Offline: no credentials, no network, no rate limits.
+		c.transport = &replayTransport{dir: cfg.ReplayDir}
+		c.tokens = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "replay"})
+		c.authMethod = AuthReplay
+		c.http = &http.Client{Transport: &oauth2.Transport{Source: c.tokens, Base: c.transport}}
+		return c, nil
+	}
+	// Installation token exchanges are never cached or recorded.
+	c.tokens, c.authMethod, c.app, err = tokenSource(cfg, limiter)
+	if err != nil {
+		return nil, err
+	}
+	if c.cache != nil && c.app != nil {
+		// Installation tokens rotate hourly; key the cache by installation.
+		c.cache.identity = fmt.Sprintf("app %d/%d", c.app.appID, c.app.installationID)
+	}
+	if cfg.RecordDir != "" {
+		if c.transport, err = newRecordTransport(cfg.RecordDir, c.transport); err != nil {
+			return nil, err
+		}
+	}
+	c.http = &http.Client{Transport: &oauth2.Transport{Source: c.tokens, Base: c.transport}}
+	return c, nil
+}
+
+// baseTransport is http.DefaultTransport plus the TLS settings Enterprise
+// Server installs tend to need: a private CA bundle, or no verification at
+// all for lab instances.
+func baseTransport(cfg config.Config) (http.RoundTripper, error) {
+	if cfg.CABundle == "" && !cfg.InsecureSkipVerify {
+		return http.DefaultTransport, nil
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s has no PEM certificates", cfg.CABundle)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+	return t, nil
+}
+
+// RateLimitUsage reports the budget consumed so far, per rate-limit resource.
+func (c *Client) RateLimitUsage() []model.APIUsage {
+	return c.limiter.snapshot()
+}
+
+// CacheStats reports how the on-disk cache served this run; ok is false when
+// caching is disabled.
+func (c *Client) CacheStats() (stats CacheStats, ok bool) {
+	if c.cache == nil {
+		return CacheStats{}, false
+	}
+	return c.cache.stats(), true
 }
 
 func (c *Client) doGraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	return c.graphQL(ctx, query, variables, out, false)
+}
+
+// doGraphQLAllowMissing is doGraphQL for aliased batch queries: NOT_FOUND
+// errors are dropped and the data decoded anyway, so a deleted or renamed
+// repository comes back as a null alias instead of failing the batch.
+func (c *Client) doGraphQLAllowMissing(ctx context.Context, query string, variables map[string]any, out any) error {
+	return c.graphQL(ctx, query, variables, out, true)
+}
+
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]any, out any, allowMissing bool) error {
 	payload := map[string]any{
 		"query": query,
 		"variables": variables,
@@ -33,7 +149,6 @@ func (c *Client) doGraphQL(ctx context.Context, query string, variables map[stri
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.cfg.GraphQLEnd, bytes.NewReader(b))
 	if err != nil { return err }
-	req.Header.Set("Authorization", "bearer "+c.cfg.Token)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
@@ -43,20 +158,23 @@ func (c *Client) doGraphQL(ctx context.Context, query string, variables map[stri
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 300 {
-		return fmt.Errorf("graphql http %d: %s", resp.StatusCode, string(body))
+		return &graphQLError{Status: resp.StatusCode, Message: string(body)}
 	}
 
 	var envelope struct{
 		Data json.RawMessage `json:"data"`
 		Errors []struct{
+			Type string `json:"type"`
 			Message string `json:"message"`
 		} `json:"errors"`
 	}
 	if err := json.Unmarshal(body, &envelope); err != nil {
 		return fmt.Errorf("graphql decode envelope: %w", err)
 	}
-	if len(envelope.Errors) > 0 {
-		return fmt.Errorf("graphql errors: %v", envelope.Errors[0].Message)
+	for _, e := range envelope.Errors {
+		if !allowMissing || e.Type != "NOT_FOUND" {
+			return &graphQLError{Type: e.Type, Message: e.Message}
+		}
 	}
 	if err := json.Unmarshal(envelope.Data, out); err != nil {
 		return fmt.Errorf("graphql decode data: %w", err)
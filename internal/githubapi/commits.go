@@ -0,0 +1,150 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// FetchCommits walks the default-branch history of each repository and keeps
+// the commits authored by userID (a GraphQL node ID, see FetchUser) that were
+// committed inside the window. Repositories are walked a few at a time; a repo
+// that fails keeps the commits read before the failure, and every failure is
+// returned as a *RequestError, joined.
+func (c *Client) FetchCommits(ctx context.Context, userID string, repos []string, from, to time.Time) ([]model.CommitItem, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		all  []model.CommitItem
+		errs []error
+	)
+	sem := make(chan struct{}, 4)
+	for _, full := range repos {
+		owner, name, ok := strings.Cut(full, "/")
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(full, owner, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			commits, err := c.repoHistory(ctx, userID, owner, name, from, to)
+			mu.Lock()
+			defer mu.Unlock()
+			all = append(all, commits...)
+			if err != nil {
+				errs = append(errs, &RequestError{Op: "commit_history", Repo: full, Err: err})
+			}
+		}(full, owner, name)
+	}
+	wg.Wait()
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].CommittedAt.Equal(all[j].CommittedAt) {
+			return all[i].SHA < all[j].SHA
+		}
+		return all[i].CommittedAt.Before(all[j].CommittedAt)
+	})
+	return all, errors.Join(errs...)
+}
+
+func (c *Client) repoHistory(ctx context.Context, userID, owner, name string, from, to time.Time) ([]model.CommitItem, error) {
+	const q = `
+query($owner:String!, $name:String!, $author:ID!, $since:GitTimestamp!, $until:GitTimestamp!, $after:String) {
+  repository(owner:$owner, name:$name) {
+    defaultBranchRef {
+      target {
+        ... on Commit {
+          history(first:100, after:$after, author:{id:$author}, since:$since, until:$until) {
+            pageInfo { hasNextPage endCursor }
+            nodes {
+              oid
+              url
+              committedDate
+              authoredDate
+              additions
+              deletions
+              messageHeadline
+              message
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	// History inside a closed window only changes on force-push.
+	ctx = withClosedWindow(ctx, to)
+	full := owner + "/" + name
+
+	var all []model.CommitItem
+	var after *string
+	for {
+		var out struct {
+			Repository *struct {
+				DefaultBranchRef *struct {
+					Target struct {
+						History struct {
+							PageInfo struct {
+								HasNextPage bool    `json:"hasNextPage"`
+								EndCursor   *string `json:"endCursor"`
+							} `json:"pageInfo"`
+							Nodes []struct {
+								OID             string    `json:"oid"`
+								URL             string    `json:"url"`
+								CommittedDate   time.Time `json:"committedDate"`
+								AuthoredDate    time.Time `json:"authoredDate"`
+								Additions       int       `json:"additions"`
+								Deletions       int       `json:"deletions"`
+								MessageHeadline string    `json:"messageHeadline"`
+								Message         string    `json:"message"`
+							} `json:"nodes"`
+						} `json:"history"`
+					} `json:"target"`
+				} `json:"defaultBranchRef"`
+			} `json:"repository"`
+		}
+		vars := map[string]any{
+			"owner":  owner,
+			"name":   name,
+			"author": userID,
+			"since":  from.Format(time.RFC3339),
+			"until":  to.Format(time.RFC3339),
+			"after":  after,
+		}
+		if err := c.doGraphQL(ctx, q, vars, &out); err != nil {
+			return all, err
+		}
+		if out.Repository == nil || out.Repository.DefaultBranchRef == nil {
+			return all, nil // empty repository
+		}
+		h := out.Repository.DefaultBranchRef.Target.History
+		for _, n := range h.Nodes {
+			all = append(all, model.CommitItem{
+				Repo:          full,
+				SHA:           n.OID,
+				URL:           n.URL,
+				CommittedAt:   n.CommittedDate,
+				AuthoredAt:    n.AuthoredDate,
+				Additions:     n.Additions,
+				Deletions:     n.Deletions,
+				Headline:      n.MessageHeadline,
+				MessageLength: utf8.RuneCountInString(strings.TrimSpace(n.Message)),
+				HasBody:       strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(n.Message), n.MessageHeadline)) != "",
+			})
+		}
+		if !h.PageInfo.HasNextPage || h.PageInfo.EndCursor == nil {
+			break
+		}
+		after = h.PageInfo.EndCursor
+	}
+	return all, nil
+}
@@ -0,0 +1,81 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+)
+
+func TestFetchCommits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	// 230 commits in the window, three pages' worth, plus some that must be
+	// left out: before and after it, and by someone else.
+	var commits []githubtest.Commit
+	for i := 0; i < 230; i++ {
+		commits = append(commits, githubtest.Commit{
+			Repo: "octocat/hello-world", OID: fmt.Sprintf("a%03d", i), Message: "Update README",
+			CommittedAt: from.Add(time.Duration(i) * 24 * time.Hour),
+			Additions:   1,
+		})
+	}
+	commits = append(commits,
+		githubtest.Commit{Repo: "octocat/hello-world", OID: "edge", CommittedAt: to, Message: "fix: 修正する\n\n本文"},
+		githubtest.Commit{Repo: "octocat/hello-world", OID: "before", CommittedAt: from.Add(-time.Second), Message: "too early"},
+		githubtest.Commit{Repo: "octocat/hello-world", OID: "after", CommittedAt: to.Add(time.Second), Message: "too late"},
+		githubtest.Commit{Repo: "octocat/hello-world", OID: "theirs", Author: "hubot", CommittedAt: from.Add(time.Hour), Message: "not mine"},
+		githubtest.Commit{Repo: "octo-org/platform", OID: "p1", CommittedAt: from.Add(36 * time.Hour), Message: "feat(api): add endpoint\n"},
+	)
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{
+		User: githubtest.User{Login: "octocat", ID: "U_octocat"},
+		Repos: []githubtest.Repo{
+			{Owner: "octocat", Name: "hello-world"},
+			{Owner: "octo-org", Name: "platform"},
+			{Owner: "octocat", Name: "empty"},
+		},
+		Commits: commits,
+	})
+	defer srv.Close()
+	c, err := New(srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := srv.Requests()
+	got, err := c.FetchCommits(context.Background(), "U_octocat", []string{"octocat/hello-world", "octo-org/platform", "octocat/empty", "octocat/gone", "not-a-repo"}, from, to)
+
+	var re *RequestError
+	if !errors.As(err, &re) || re.Repo != "octocat/gone" || re.Op != "commit_history" {
+		t.Errorf("err = %v, want a commit_history failure for octocat/gone", err)
+	}
+	// hello-world takes three pages, the others one request each.
+	if n := srv.Requests() - before; n != 3+1+1+1 {
+		t.Errorf("%d requests, want 6", n)
+	}
+	if len(got) != 232 {
+		t.Fatalf("%d commits, want 232", len(got))
+	}
+	// Oldest first, the window's edges included.
+	if got[0].SHA != "a000" || got[1].SHA != "a001" || got[2].SHA != "p1" || got[len(got)-1].SHA != "edge" {
+		t.Errorf("order: %s %s %s ... %s", got[0].SHA, got[1].SHA, got[2].SHA, got[len(got)-1].SHA)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].CommittedAt.Before(got[i-1].CommittedAt) {
+			t.Fatalf("commit %d (%s) out of order", i, got[i].SHA)
+		}
+	}
+
+	edge := got[len(got)-1]
+	// "fix: 修正する\n\n本文" is 13 characters, not its 25 bytes.
+	if edge.Repo != "octocat/hello-world" || edge.Headline != "fix: 修正する" || edge.MessageLength != 13 || !edge.HasBody {
+		t.Errorf("edge = %+v", edge)
+	}
+	if p := got[2]; p.Repo != "octo-org/platform" || p.HasBody || p.MessageLength != len("feat(api): add endpoint") || p.URL != "https://github.com/octo-org/platform/commit/p1" {
+		t.Errorf("p1 = %+v", p)
+	}
+}
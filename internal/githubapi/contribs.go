@@ -1,4 +1,3 @@
-\
 package githubapi
 
 import (
@@ -6,10 +5,14 @@ import (
 	"time"
 
 	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/dennislee928/github-recap-2025/internal/period"
 )
 
-func (c *Client) FetchContributionsCollection(login string, from, to time.Time) (*model.ContributionsCollection, error) {
-	const q = `
+// maxRepositories is the largest page GitHub allows for the
+// *ContributionsByRepository fields; a full page means there may be more.
+const maxRepositories = 100
+
+const contributionsQuery = `
 query($login:String!, $from:DateTime!, $to:DateTime!) {
   user(login:$login) {
     contributionsCollection(from:$from, to:$to) {
@@ -45,87 +48,146 @@ query($login:String!, $from:DateTime!, $to:DateTime!) {
   }
 }`
 
-	type resp struct{
-		User struct{
-			ContributionsCollection struct{
-				TotalCommitContributions int `json:"totalCommitContributions"`
-				TotalPullRequestContributions int `json:"totalPullRequestContributions"`
-				TotalIssueContributions int `json:"totalIssueContributions"`
-				TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
-				ContributionCalendar struct{
-					Weeks []struct{
-						ContributionDays []struct{
-							Date string `json:"date"`
-							ContributionCount int `json:"contributionCount"`
-						} `json:"contributionDays"`
-					} `json:"weeks"`
-				} `json:"contributionCalendar"`
-
-				CommitByRepo []struct{
-					Repository struct{
-						NameWithOwner string `json:"nameWithOwner"`
-						IsPrivate bool `json:"isPrivate"`
-					} `json:"repository"`
-					Contributions struct{
-						TotalCount int `json:"totalCount"`
-					} `json:"contributions"`
-				} `json:"commitContributionsByRepository"`
-
-				PRByRepo []struct{
-					Repository struct{
-						NameWithOwner string `json:"nameWithOwner"`
-						IsPrivate bool `json:"isPrivate"`
-					} `json:"repository"`
-					Contributions struct{
-						TotalCount int `json:"totalCount"`
-					} `json:"contributions"`
-				} `json:"pullRequestContributionsByRepository"`
-
-				IssueByRepo []struct{
-					Repository struct{
-						NameWithOwner string `json:"nameWithOwner"`
-						IsPrivate bool `json:"isPrivate"`
-					} `json:"repository"`
-					Contributions struct{
-						TotalCount int `json:"totalCount"`
-					} `json:"contributions"`
-				} `json:"issueContributionsByRepository"`
-
-				ReviewByRepo []struct{
-					Repository struct{
-						NameWithOwner string `json:"nameWithOwner"`
-						IsPrivate bool `json:"isPrivate"`
-					} `json:"repository"`
-					Contributions struct{
-						TotalCount int `json:"totalCount"`
-					} `json:"contributions"`
-				} `json:"pullRequestReviewContributionsByRepository"`
-			} `json:"contributionsCollection"`
-		} `json:"user"`
-	}
-
-	var out resp
-	if err := c.doGraphQL(context.Background(), q, map[string]any{
+type repoContributions []struct{
+	Repository struct{
+		NameWithOwner string `json:"nameWithOwner"`
+		IsPrivate bool `json:"isPrivate"`
+	} `json:"repository"`
+	Contributions struct{
+		TotalCount int `json:"totalCount"`
+	} `json:"contributions"`
+}
+
+type contributionsResp struct{
+	User struct{
+		ContributionsCollection struct{
+			TotalCommitContributions int `json:"totalCommitContributions"`
+			TotalPullRequestContributions int `json:"totalPullRequestContributions"`
+			TotalIssueContributions int `json:"totalIssueContributions"`
+			TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
+			ContributionCalendar struct{
+				Weeks []struct{
+					ContributionDays []struct{
+						Date string `json:"date"`
+						ContributionCount int `json:"contributionCount"`
+					} `json:"contributionDays"`
+				} `json:"weeks"`
+			} `json:"contributionCalendar"`
+
+			CommitByRepo repoContributions `json:"commitContributionsByRepository"`
+			PRByRepo repoContributions `json:"pullRequestContributionsByRepository"`
+			IssueByRepo repoContributions `json:"issueContributionsByRepository"`
+			ReviewByRepo repoContributions `json:"pullRequestReviewContributionsByRepository"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+// FetchContributionsCollection returns totals, the calendar and per-repo counts
+// for the window. contributionsCollection only accepts spans of up to a year,
+// so longer windows are fetched year by year and merged.
+func (c *Client) FetchContributionsCollection(ctx context.Context, login string, from, to time.Time) (*model.ContributionsCollection, error) {
+	chunks := period.Window{From: from, To: to}.Split()
+	if len(chunks) == 1 {
+		return c.fetchContributionsYear(ctx, login, from, to)
+	}
+
+	merged := &model.ContributionsCollection{}
+	tally := newRepoTally()
+	complete := true
+	dayIndex := map[string]int{}
+	for _, w := range chunks {
+		cc, err := c.fetchContributionsYear(ctx, login, w.From, w.To)
+		if err != nil {
+			return nil, err
+		}
+		merged.TotalCommits += cc.TotalCommits
+		merged.TotalPRs += cc.TotalPRs
+		merged.TotalIssues += cc.TotalIssues
+		merged.TotalReviews += cc.TotalReviews
+		for _, d := range cc.Calendar {
+			// A chunk boundary that falls mid-day shows that day in both.
+			if i, ok := dayIndex[d.Date]; ok {
+				merged.Calendar[i].Count += d.Count
+				continue
+			}
+			dayIndex[d.Date] = len(merged.Calendar)
+			merged.Calendar = append(merged.Calendar, d)
+		}
+		mergeByRepo(tally.commits, cc.ByRepoCommits)
+		mergeByRepo(tally.prs, cc.ByRepoPRs)
+		mergeByRepo(tally.issues, cc.ByRepoIssues)
+		mergeByRepo(tally.reviews, cc.ByRepoReviews)
+		for _, rc := range cc.RepoCoverage {
+			complete = complete && rc.Complete
+		}
+	}
+	merged.ByRepoCommits = tally.commits
+	merged.ByRepoPRs = tally.prs
+	merged.ByRepoIssues = tally.issues
+	merged.ByRepoReviews = tally.reviews
+	merged.RepoCoverage = repoCoverage(merged, complete)
+	return merged, nil
+}
+
+// fetchContributionsYear fetches a window of at most a year. GitHub caps each
+// per-repo list at 100 repositories; when a list comes back full, the per-repo
+// counts are rebuilt from monthly (and, if needed, smaller) windows, which are
+// exact and can be summed.
+func (c *Client) fetchContributionsYear(ctx context.Context, login string, from, to time.Time) (*model.ContributionsCollection, error) {
+	cc, err := c.fetchContributionsWindow(ctx, login, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if truncated(cc) {
+		byRepo := newRepoTally()
+		complete := true
+		for _, w := range monthWindows(from, to) {
+			ok, err := c.backfillByRepo(ctx, login, w[0], w[1], byRepo)
+			if err != nil {
+				return nil, err
+			}
+			complete = complete && ok
+		}
+		cc.ByRepoCommits = byRepo.commits
+		cc.ByRepoPRs = byRepo.prs
+		cc.ByRepoIssues = byRepo.issues
+		cc.ByRepoReviews = byRepo.reviews
+		cc.RepoCoverage = repoCoverage(cc, complete)
+	}
+	return cc, nil
+}
+
+func (c *Client) fetchContributionsWindow(ctx context.Context, login string, from, to time.Time) (*model.ContributionsCollection, error) {
+	// Contribution counts for a window stop changing shortly after it ends,
+	// so a cached response stored after that can be reused on every re-run.
+	ctx = withClosedWindow(ctx, to)
+
+	q := contributionsQuery
+	// Older Enterprise Server releases predate review contributions.
+	if !c.hasField(ctx, "ContributionsCollection", "pullRequestReviewContributionsByRepository") {
+		q = dropField(dropField(q, "totalPullRequestReviewContributions"), "pullRequestReviewContributionsByRepository")
+	}
+
+	var out contributionsResp
+	if err := c.doGraphQL(ctx, q, map[string]any{
 		"login": login,
 		"from": from.Format(time.RFC3339),
 		"to": to.Format(time.RFC3339),
 	}, &out); err != nil {
 		return nil, err
 	}
+	col := out.User.ContributionsCollection
 
 	cc := &model.ContributionsCollection{
-		TotalCommits: out.User.ContributionsCollection.TotalCommitContributions,
-		TotalPRs: out.User.ContributionsCollection.TotalPullRequestContributions,
-		TotalIssues: out.User.ContributionsCollection.TotalIssueContributions,
-		TotalReviews: out.User.ContributionsCollection.TotalPullRequestReviewContributions,
+		TotalCommits: col.TotalCommitContributions,
+		TotalPRs: col.TotalPullRequestContributions,
+		TotalIssues: col.TotalIssueContributions,
+		TotalReviews: col.TotalPullRequestReviewContributions,
 		Calendar: make([]model.ContributionDay, 0, 400),
-		ByRepoCommits: map[string]model.RepoContribLite{},
-		ByRepoPRs: map[string]model.RepoContribLite{},
-		ByRepoIssues: map[string]model.RepoContribLite{},
-		ByRepoReviews: map[string]model.RepoContribLite{},
 	}
 
-	for _, w := range out.User.ContributionsCollection.ContributionCalendar.Weeks {
+	for _, w := range col.ContributionCalendar.Weeks {
 		for _, d := range w.ContributionDays {
 			cc.Calendar = append(cc.Calendar, model.ContributionDay{
 				Date: d.Date,
@@ -134,34 +196,122 @@ query($login:String!, $from:DateTime!, $to:DateTime!) {
 		}
 	}
 
-	for _, x := range out.User.ContributionsCollection.CommitByRepo {
-		cc.ByRepoCommits[x.Repository.NameWithOwner] = model.RepoContribLite{
-			Repo: x.Repository.NameWithOwner,
-			Count: x.Contributions.TotalCount,
-			IsPrivate: x.Repository.IsPrivate,
+	byRepo := newRepoTally()
+	addRepoContributions(byRepo.commits, col.CommitByRepo)
+	addRepoContributions(byRepo.prs, col.PRByRepo)
+	addRepoContributions(byRepo.issues, col.IssueByRepo)
+	addRepoContributions(byRepo.reviews, col.ReviewByRepo)
+	cc.ByRepoCommits = byRepo.commits
+	cc.ByRepoPRs = byRepo.prs
+	cc.ByRepoIssues = byRepo.issues
+	cc.ByRepoReviews = byRepo.reviews
+	cc.RepoCoverage = repoCoverage(cc, !truncated(cc))
+
+	return cc, nil
+}
+
+// backfillByRepo adds the per-repo counts for [from, to] into byRepo, halving
+// the window while any list is still full. It reports false if a list was
+// still full at one-day resolution.
+func (c *Client) backfillByRepo(ctx context.Context, login string, from, to time.Time, byRepo *repoTally) (bool, error) {
+	cc, err := c.fetchContributionsWindow(ctx, login, from, to)
+	if err != nil {
+		return false, err
+	}
+	if truncated(cc) && to.Sub(from) > 24*time.Hour {
+		mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+		okLeft, err := c.backfillByRepo(ctx, login, from, mid, byRepo)
+		if err != nil {
+			return false, err
 		}
+		okRight, err := c.backfillByRepo(ctx, login, mid.Add(time.Second), to, byRepo)
+		return okLeft && okRight, err
 	}
-	for _, x := range out.User.ContributionsCollection.PRByRepo {
-		cc.ByRepoPRs[x.Repository.NameWithOwner] = model.RepoContribLite{
+	mergeByRepo(byRepo.commits, cc.ByRepoCommits)
+	mergeByRepo(byRepo.prs, cc.ByRepoPRs)
+	mergeByRepo(byRepo.issues, cc.ByRepoIssues)
+	mergeByRepo(byRepo.reviews, cc.ByRepoReviews)
+	return !truncated(cc), nil
+}
+
+type repoTally struct {
+	commits, prs, issues, reviews map[string]model.RepoContribLite
+}
+
+func newRepoTally() *repoTally {
+	return &repoTally{
+		commits: map[string]model.RepoContribLite{},
+		prs: map[string]model.RepoContribLite{},
+		issues: map[string]model.RepoContribLite{},
+		reviews: map[string]model.RepoContribLite{},
+	}
+}
+
+func addRepoContributions(dst map[string]model.RepoContribLite, src repoContributions) {
+	for _, x := range src {
+		dst[x.Repository.NameWithOwner] = model.RepoContribLite{
 			Repo: x.Repository.NameWithOwner,
 			Count: x.Contributions.TotalCount,
 			IsPrivate: x.Repository.IsPrivate,
 		}
 	}
-	for _, x := range out.User.ContributionsCollection.IssueByRepo {
-		cc.ByRepoIssues[x.Repository.NameWithOwner] = model.RepoContribLite{
-			Repo: x.Repository.NameWithOwner,
-			Count: x.Contributions.TotalCount,
-			IsPrivate: x.Repository.IsPrivate,
+}
+
+func mergeByRepo(dst, src map[string]model.RepoContribLite) {
+	for name, v := range src {
+		cur, ok := dst[name]
+		if !ok {
+			dst[name] = v
+			continue
 		}
+		cur.Count += v.Count
+		dst[name] = cur
 	}
-	for _, x := range out.User.ContributionsCollection.ReviewByRepo {
-		cc.ByRepoReviews[x.Repository.NameWithOwner] = model.RepoContribLite{
-			Repo: x.Repository.NameWithOwner,
-			Count: x.Contributions.TotalCount,
-			IsPrivate: x.Repository.IsPrivate,
+}
+
+func truncated(cc *model.ContributionsCollection) bool {
+	return len(cc.ByRepoCommits) >= maxRepositories ||
+		len(cc.ByRepoPRs) >= maxRepositories ||
+		len(cc.ByRepoIssues) >= maxRepositories ||
+		len(cc.ByRepoReviews) >= maxRepositories
+}
+
+func repoCoverage(cc *model.ContributionsCollection, complete bool) []model.RepoCoverage {
+	cov := func(kind string, total int, m map[string]model.RepoContribLite) model.RepoCoverage {
+		sum := 0
+		for _, v := range m {
+			sum += v.Count
+		}
+		return model.RepoCoverage{
+			Kind: kind,
+			Total: total,
+			SumByRepo: sum,
+			Repos: len(m),
+			Complete: complete,
+			Consistent: sum == total,
 		}
 	}
+	return []model.RepoCoverage{
+		cov("commits", cc.TotalCommits, cc.ByRepoCommits),
+		cov("pull_requests", cc.TotalPRs, cc.ByRepoPRs),
+		cov("issues", cc.TotalIssues, cc.ByRepoIssues),
+		cov("reviews", cc.TotalReviews, cc.ByRepoReviews),
+	}
+}
 
-	return cc, nil
+// monthWindows splits [from, to] at calendar month boundaries in from's
+// location.
+func monthWindows(from, to time.Time) [][2]time.Time {
+	var out [][2]time.Time
+	start := from
+	for !start.After(to) {
+		next := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location())
+		end := next.Add(-time.Second)
+		if end.After(to) {
+			end = to
+		}
+		out = append(out, [2]time.Time{start, end})
+		start = next
+	}
+	return out
 }
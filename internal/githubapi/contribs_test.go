@@ -0,0 +1,185 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// manyRepos is one commit to each of n repositories, named prefix000 on,
+// on date.
+func manyRepos(prefix, date string, n int) []githubtest.Contribution {
+	out := make([]githubtest.Contribution, n)
+	for i := range out {
+		out[i] = githubtest.Contribution{Date: date, Repo: fmt.Sprintf("%s%03d", prefix, i), Kind: "commit", Count: 1}
+	}
+	return out
+}
+
+func contributionsClient(t *testing.T, contribs []githubtest.Contribution) *Client {
+	t.Helper()
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{
+		User:          githubtest.User{Login: "octocat"},
+		Contributions: contribs,
+	})
+	t.Cleanup(srv.Close)
+	c, err := New(srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func coverage(cc *model.ContributionsCollection, kind string) model.RepoCoverage {
+	for _, rc := range cc.RepoCoverage {
+		if rc.Kind == kind {
+			return rc
+		}
+	}
+	return model.RepoCoverage{}
+}
+
+func TestFetchContributionsBackfill(t *testing.T) {
+	// 150 repositories over the year: each month alone stays under the cap.
+	contribs := append(manyRepos("acme/jan", "2025-01-20", 75), manyRepos("acme/mar", "2025-03-05", 75)...)
+	contribs = append(contribs,
+		githubtest.Contribution{Date: "2025-01-02", Repo: "acme/both", Kind: "commit", Count: 2},
+		githubtest.Contribution{Date: "2025-03-31", Repo: "acme/both", Kind: "commit", Count: 3, Private: true},
+		githubtest.Contribution{Date: "2025-03-31", Repo: "acme/both", Kind: "pr", Count: 1},
+	)
+	c := contributionsClient(t, contribs)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+	cc, err := c.FetchContributionsCollection(context.Background(), "octocat", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cc.TotalCommits != 155 || len(cc.ByRepoCommits) != 151 {
+		t.Errorf("%d commits in %d repos, want 155 in 151", cc.TotalCommits, len(cc.ByRepoCommits))
+	}
+	// Counts from different months add up.
+	if got := cc.ByRepoCommits["acme/both"]; got.Count != 5 {
+		t.Errorf("acme/both = %+v, want 5 commits", got)
+	}
+	if got := cc.ByRepoCommits["acme/mar074"]; got.Count != 1 {
+		t.Errorf("acme/mar074 = %+v", got)
+	}
+	// Lists that were never full are rebuilt too.
+	if got := cc.ByRepoPRs["acme/both"]; got.Count != 1 || len(cc.ByRepoPRs) != 1 {
+		t.Errorf("ByRepoPRs = %+v", cc.ByRepoPRs)
+	}
+	want := model.RepoCoverage{Kind: "commits", Total: 155, SumByRepo: 155, Repos: 151, Complete: true, Consistent: true}
+	if got := coverage(cc, "commits"); got != want {
+		t.Errorf("commits coverage = %+v, want %+v", got, want)
+	}
+	// The calendar comes from the year query.
+	if len(cc.Calendar) != 365 {
+		t.Errorf("calendar has %d days", len(cc.Calendar))
+	}
+}
+
+func TestFetchContributionsStillTruncated(t *testing.T) {
+	// 120 repositories on one day: still over the cap at one-day resolution.
+	contribs := append(manyRepos("acme/busy", "2025-06-10", 120),
+		githubtest.Contribution{Date: "2025-02-01", Repo: "acme/quiet", Kind: "commit", Count: 4},
+	)
+	c := contributionsClient(t, contribs)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+	cc, err := c.FetchContributionsCollection(context.Background(), "octocat", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rc := range cc.RepoCoverage {
+		if rc.Complete {
+			t.Errorf("%s coverage = %+v, want incomplete", rc.Kind, rc)
+		}
+	}
+	if got := coverage(cc, "commits"); got.Total != 124 || got.Consistent {
+		t.Errorf("commits coverage = %+v, want 124 in total and not consistent", got)
+	}
+	if got := cc.ByRepoCommits["acme/quiet"]; got.Count != 4 {
+		t.Errorf("acme/quiet = %+v, want 4 commits", got)
+	}
+
+	// Under the cap the first answer is kept and complete.
+	c = contributionsClient(t, manyRepos("acme/r", "2025-06-10", 99))
+	cc, err = c.FetchContributionsCollection(context.Background(), "octocat", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := coverage(cc, "commits"); !got.Complete || got.Repos != 99 {
+		t.Errorf("99 repos: coverage = %+v", got)
+	}
+}
+
+func TestMonthWindows(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := func(loc *time.Location, s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04:05", s, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     [][2]string
+	}{
+		{
+			name: "partial first and last months",
+			from: ts(time.UTC, "2025-01-15 10:00:00"),
+			to:   ts(time.UTC, "2025-03-10 23:59:59"),
+			want: [][2]string{
+				{"2025-01-15 10:00:00", "2025-01-31 23:59:59"},
+				{"2025-02-01 00:00:00", "2025-02-28 23:59:59"},
+				{"2025-03-01 00:00:00", "2025-03-10 23:59:59"},
+			},
+		},
+		{
+			name: "inside one month",
+			from: ts(time.UTC, "2024-02-03 00:00:00"),
+			to:   ts(time.UTC, "2024-02-29 12:00:00"),
+			want: [][2]string{{"2024-02-03 00:00:00", "2024-02-29 12:00:00"}},
+		},
+		{
+			name: "to on a month's last second",
+			from: ts(time.UTC, "2024-11-20 00:00:00"),
+			to:   ts(time.UTC, "2024-12-31 23:59:59"),
+			want: [][2]string{
+				{"2024-11-20 00:00:00", "2024-11-30 23:59:59"},
+				{"2024-12-01 00:00:00", "2024-12-31 23:59:59"},
+			},
+		},
+		{
+			// Boundaries are local midnights, across the DST change.
+			name: "in from's location",
+			from: ts(la, "2025-02-20 00:00:00"),
+			to:   ts(la, "2025-03-15 23:59:59"),
+			want: [][2]string{
+				{"2025-02-20 00:00:00", "2025-02-28 23:59:59"},
+				{"2025-03-01 00:00:00", "2025-03-15 23:59:59"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var got [][2]string
+		for _, w := range monthWindows(tt.from, tt.to) {
+			got = append(got, [2]string{w[0].Format("2006-01-02 15:04:05"), w[1].Format("2006-01-02 15:04:05")})
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: monthWindows = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
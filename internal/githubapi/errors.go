@@ -0,0 +1,129 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/google/go-github/v62/github"
+)
+
+// RequestError is one failed sub-request of a collector that keeps going past
+// failures (one repository's languages, stargazers, history...). Such
+// collectors return all of them joined with errors.Join.
+type RequestError struct {
+	Op   string // e.g. "list_languages"
+	Repo string // owner/name
+	Err  error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Repo, e.Err)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// graphQLError is a GraphQL call that failed either at the HTTP level
+// (Status set) or with errors in the response envelope (Type set).
+type graphQLError struct {
+	Status  int
+	Type    string // e.g. NOT_FOUND, FORBIDDEN, RATE_LIMITED
+	Message string
+}
+
+func (e *graphQLError) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("graphql http %d: %s", e.Status, e.Message)
+	}
+	return "graphql errors: " + e.Message
+}
+
+// Failures flattens err into one model.Failure per failed sub-request. err may
+// be a single error or several joined ones; op names the operation for errors
+// that are not a RequestError.
+func Failures(section, op string, err error) []model.Failure {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var out []model.Failure
+		for _, e := range joined.Unwrap() {
+			out = append(out, Failures(section, op, e)...)
+		}
+		return out
+	}
+
+	f := model.Failure{Section: section, Operation: op, Error: err.Error()}
+	var re *RequestError
+	if errors.As(err, &re) {
+		f.Operation, f.Repo, f.Error = re.Op, re.Repo, re.Err.Error()
+	}
+	f.Status, f.Class = classify(err)
+	return []model.Failure{f}
+}
+
+func classify(err error) (status int, class string) {
+	var (
+		rateErr  *github.RateLimitError
+		abuseErr *github.AbuseRateLimitError
+		respErr  *github.ErrorResponse
+		gqlErr   *graphQLError
+		netErr   net.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return 0, "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return 0, "timeout"
+	case errors.As(err, &rateErr):
+		return responseStatus(rateErr.Response), "rate_limited"
+	case errors.As(err, &abuseErr):
+		return responseStatus(abuseErr.Response), "rate_limited"
+	case errors.As(err, &respErr):
+		status = responseStatus(respErr.Response)
+	case errors.As(err, &gqlErr):
+		if gqlErr.Status == 0 {
+			switch gqlErr.Type {
+			case "NOT_FOUND":
+				return 0, "not_found"
+			case "FORBIDDEN":
+				return 0, "forbidden"
+			case "RATE_LIMITED":
+				return 0, "rate_limited"
+			}
+			return 0, "graphql"
+		}
+		status = gqlErr.Status
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return 0, "timeout"
+		}
+		return 0, "network"
+	default:
+		return 0, "other"
+	}
+
+	switch {
+	case status == http.StatusUnauthorized:
+		return status, "unauthorized"
+	case status == http.StatusForbidden:
+		return status, "forbidden"
+	case status == http.StatusNotFound:
+		return status, "not_found"
+	case status == http.StatusTooManyRequests:
+		return status, "rate_limited"
+	case status >= 500:
+		return status, "server_error"
+	}
+	return status, "http_error"
+}
+
+func responseStatus(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
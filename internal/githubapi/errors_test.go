@@ -0,0 +1,127 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/google/go-github/v62/github"
+)
+
+func TestFailures(t *testing.T) {
+	resp := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{Method: "GET", URL: &url.URL{Path: "/repos/acme/api"}}}
+	}
+	restErr := func(status int) error {
+		return &github.ErrorResponse{Response: resp(status), Message: http.StatusText(status)}
+	}
+	timeout := &url.Error{Op: "Get", URL: "https://api.github.com/user", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name string
+		err  error
+		want []model.Failure
+	}{
+		{"nil", nil, nil},
+		{
+			"plain error keeps the op",
+			errors.New("boom"),
+			[]model.Failure{{Section: "s", Operation: "op", Class: "other", Error: "boom"}},
+		},
+		{
+			"REST 404 in a RequestError",
+			&RequestError{Op: "list_languages", Repo: "acme/api", Err: restErr(404)},
+			[]model.Failure{{Section: "s", Operation: "list_languages", Repo: "acme/api", Status: 404, Class: "not_found", Error: restErr(404).Error()}},
+		},
+		{
+			"REST statuses",
+			errors.Join(restErr(401), restErr(403), restErr(429), restErr(502), restErr(422)),
+			[]model.Failure{
+				{Section: "s", Operation: "op", Status: 401, Class: "unauthorized", Error: restErr(401).Error()},
+				{Section: "s", Operation: "op", Status: 403, Class: "forbidden", Error: restErr(403).Error()},
+				{Section: "s", Operation: "op", Status: 429, Class: "rate_limited", Error: restErr(429).Error()},
+				{Section: "s", Operation: "op", Status: 502, Class: "server_error", Error: restErr(502).Error()},
+				{Section: "s", Operation: "op", Status: 422, Class: "http_error", Error: restErr(422).Error()},
+			},
+		},
+		{
+			"primary and secondary rate limits",
+			errors.Join(
+				&github.RateLimitError{Response: resp(403), Message: "API rate limit exceeded"},
+				&github.AbuseRateLimitError{Response: resp(403), Message: "secondary rate limit"},
+			),
+			[]model.Failure{
+				{Section: "s", Operation: "op", Status: 403, Class: "rate_limited", Error: (&github.RateLimitError{Response: resp(403), Message: "API rate limit exceeded"}).Error()},
+				{Section: "s", Operation: "op", Status: 403, Class: "rate_limited", Error: (&github.AbuseRateLimitError{Response: resp(403), Message: "secondary rate limit"}).Error()},
+			},
+		},
+		{
+			"GraphQL envelope errors",
+			errors.Join(
+				&RequestError{Op: "list_stargazers", Repo: "acme/gone", Err: &graphQLError{Type: "NOT_FOUND", Message: "Could not resolve"}},
+				&RequestError{Op: "list_stargazers", Repo: "acme/secret", Err: &graphQLError{Type: "FORBIDDEN", Message: "Resource not accessible"}},
+				&graphQLError{Type: "RATE_LIMITED", Message: "API rate limit exceeded"},
+				&graphQLError{Message: "Something went wrong"},
+			),
+			[]model.Failure{
+				{Section: "s", Operation: "list_stargazers", Repo: "acme/gone", Class: "not_found", Error: "graphql errors: Could not resolve"},
+				{Section: "s", Operation: "list_stargazers", Repo: "acme/secret", Class: "forbidden", Error: "graphql errors: Resource not accessible"},
+				{Section: "s", Operation: "op", Class: "rate_limited", Error: "graphql errors: API rate limit exceeded"},
+				{Section: "s", Operation: "op", Class: "graphql", Error: "graphql errors: Something went wrong"},
+			},
+		},
+		{
+			"GraphQL HTTP errors",
+			errors.Join(&graphQLError{Status: 502, Message: "Bad Gateway"}, &graphQLError{Status: 401, Message: "Bad credentials"}),
+			[]model.Failure{
+				{Section: "s", Operation: "op", Status: 502, Class: "server_error", Error: "graphql http 502: Bad Gateway"},
+				{Section: "s", Operation: "op", Status: 401, Class: "unauthorized", Error: "graphql http 401: Bad credentials"},
+			},
+		},
+		{
+			"cancelled and timed out, however wrapped",
+			errors.Join(
+				&RequestError{Op: "commit_history", Repo: "acme/api", Err: fmt.Errorf("page 3: %w", context.Canceled)},
+				fmt.Errorf("search: %w", context.DeadlineExceeded),
+				timeout,
+				refused,
+			),
+			[]model.Failure{
+				{Section: "s", Operation: "commit_history", Repo: "acme/api", Class: "canceled", Error: "page 3: context canceled"},
+				{Section: "s", Operation: "op", Class: "timeout", Error: "search: context deadline exceeded"},
+				{Section: "s", Operation: "op", Class: "timeout", Error: timeout.Error()},
+				{Section: "s", Operation: "op", Class: "network", Error: refused.Error()},
+			},
+		},
+		{
+			// Collectors join per-repo failures, and callers join those again.
+			"nested joins are flattened",
+			errors.Join(
+				errors.Join(
+					&RequestError{Op: "list_languages", Repo: "acme/a", Err: restErr(404)},
+					&RequestError{Op: "list_languages", Repo: "acme/b", Err: restErr(500)},
+				),
+				fmt.Errorf("growth: %w", &RequestError{Op: "list_forks", Repo: "acme/c", Err: restErr(403)}),
+			),
+			[]model.Failure{
+				{Section: "s", Operation: "list_languages", Repo: "acme/a", Status: 404, Class: "not_found", Error: restErr(404).Error()},
+				{Section: "s", Operation: "list_languages", Repo: "acme/b", Status: 500, Class: "server_error", Error: restErr(500).Error()},
+				// A wrapped RequestError is still found, and its own error kept.
+				{Section: "s", Operation: "list_forks", Repo: "acme/c", Status: 403, Class: "forbidden", Error: restErr(403).Error()},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := Failures("s", "op", tt.err)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package githubapi
+
+import (
+	"context"
+	"strings"
+)
+
+// ServerVersion returns "github.com", or the installed version reported by a
+// GitHub Enterprise Server's /meta endpoint.
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	if !c.cfg.IsEnterprise() {
+		return "github.com", nil
+	}
+	client, err := c.rest()
+	if err != nil {
+		return "", err
+	}
+	// go-github's APIMeta has no installed_version, so decode it directly.
+	req, err := client.NewRequest("GET", "meta", nil)
+	if err != nil {
+		return "", err
+	}
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if _, err := client.Do(restCtx(ctx), req, &meta); err != nil {
+		return "", err
+	}
+	if meta.InstalledVersion != "" {
+		return "GHES " + meta.InstalledVersion, nil
+	}
+	return "GHES (unknown version)", nil
+}
+
+// hasField reports whether the server's GraphQL schema has typeName.field.
+// github.com always has everything we query; Enterprise Server versions are
+// introspected once per type for the life of the client. If introspection
+// itself fails, or does not know the type, every field of it is assumed
+// present, so the real query surfaces the actual error; that answer is
+// remembered too rather than re-asked on every query.
+func (c *Client) hasField(ctx context.Context, typeName, field string) bool {
+	if !c.cfg.IsEnterprise() {
+		return true
+	}
+
+	c.typeMu.Lock()
+	defer c.typeMu.Unlock()
+	if c.typeFields == nil {
+		c.typeFields = map[string]map[string]bool{}
+	}
+	fields, ok := c.typeFields[typeName]
+	if !ok {
+		const q = `
+query($name:String!) {
+  __type(name:$name) {
+    fields { name }
+  }
+}`
+		var out struct {
+			Type *struct {
+				Fields []struct {
+					Name string `json:"name"`
+				} `json:"fields"`
+			} `json:"__type"`
+		}
+		err := c.doGraphQL(ctx, q, map[string]any{"name": typeName}, &out)
+		if err != nil && ctx.Err() != nil {
+			return true // cancelled: ask again next time
+		}
+		if err == nil && out.Type != nil {
+			fields = make(map[string]bool, len(out.Type.Fields))
+			for _, f := range out.Type.Fields {
+				fields[f.Name] = true
+			}
+		}
+		c.typeFields[typeName] = fields // nil: assume every field
+	}
+	return fields == nil || fields[field]
+}
+
+// dropField removes a top-level selection block such as
+// "reviewThreads(first:50) { ... }" (or a bare "field" line) from a query
+// when the server does not support it.
+func dropField(query, field string) string {
+	i := strings.Index(query, field)
+	if i < 0 {
+		return query
+	}
+	start := strings.LastIndex(query[:i], "\n") + 1
+	end := i + len(field)
+	rest := query[end:]
+	lineEnd := strings.IndexByte(rest, '\n')
+	if lineEnd < 0 {
+		lineEnd = len(rest)
+	}
+	if open := strings.IndexByte(rest[:lineEnd], '{'); open >= 0 {
+		depth := 0
+		for j := open; j < len(rest); j++ {
+			switch rest[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			if depth == 0 {
+				lineEnd = j + 1
+				break
+			}
+		}
+	}
+	return query[:start] + strings.TrimLeft(rest[lineEnd:], " ")
+}
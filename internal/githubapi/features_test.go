@@ -0,0 +1,70 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+)
+
+func TestHasFieldCachesIntrospection(t *testing.T) {
+	var asked atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked.Add(1)
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.Variables["name"] {
+		case "PullRequest":
+			io.WriteString(w, `{"data":{"__type":{"fields":[{"name":"number"},{"name":"reviews"}]}}}`)
+		case "Unknown":
+			io.WriteString(w, `{"data":{"__type":null}}`)
+		default:
+			io.WriteString(w, `{"errors":[{"message":"introspection is disabled"}]}`)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := New(config.Config{Token: "ghp_fake", APIBase: srv.URL + "/api/v3", GraphQLEnd: srv.URL + "/api/graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	tests := []struct {
+		typeName, field string
+		want            bool
+	}{
+		{"PullRequest", "reviews", true},
+		{"PullRequest", "reviewThreads", false},
+		{"Unknown", "anything", true},
+		{"Broken", "anything", true},
+	}
+	for round := 0; round < 3; round++ {
+		for _, tt := range tests {
+			if got := client.hasField(ctx, tt.typeName, tt.field); got != tt.want {
+				t.Errorf("hasField(%s, %s) = %v, want %v", tt.typeName, tt.field, got, tt.want)
+			}
+		}
+	}
+	// One introspection per type, failures included.
+	if n := asked.Load(); n != 3 {
+		t.Errorf("%d introspection requests, want 3", n)
+	}
+
+	// A cancelled introspection is not remembered.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	client.hasField(cancelled, "Issue", "number")
+	client.typeMu.Lock()
+	_, cached := client.typeFields["Issue"]
+	client.typeMu.Unlock()
+	if cached {
+		t.Error("cancelled introspection was cached")
+	}
+}
@@ -0,0 +1,167 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// fileBatch is how many pull requests share one aliased files query; each
+// asks for a 100-file page.
+const fileBatch = 10
+
+// filePage is one page of a pull request's changed files.
+type filePage struct {
+	PageInfo struct {
+		HasNextPage bool    `json:"hasNextPage"`
+		EndCursor   *string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		Path      string `json:"path"`
+		Additions int    `json:"additions"`
+		Deletions int    `json:"deletions"`
+	} `json:"nodes"`
+}
+
+// FetchPRFiles lists the files changed by each pull request. First pages are
+// fetched in aliased batches; pull requests with more than 100 files are
+// continued one by one. A pull request that fails keeps the files read
+// before the failure; every failure is returned as a *RequestError, joined.
+func (c *Client) FetchPRFiles(ctx context.Context, prs []model.PRItem) ([]model.ChangedFile, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		all  []model.ChangedFile
+		errs []error
+	)
+	record := func(pr model.PRItem, p *filePage, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if p != nil {
+			for _, n := range p.Nodes {
+				all = append(all, model.ChangedFile{
+					Repo:      pr.Repo,
+					Number:    pr.Number,
+					Path:      n.Path,
+					Additions: n.Additions,
+					Deletions: n.Deletions,
+					At:        pr.CreatedAt,
+				})
+			}
+		}
+		if err != nil {
+			errs = append(errs, &RequestError{Op: "pull_request_files", Repo: fmt.Sprintf("%s#%d", pr.Repo, pr.Number), Err: err})
+		}
+	}
+
+	sem := make(chan struct{}, 4)
+	for start := 0; start < len(prs); start += fileBatch {
+		batch := prs[start:min(start+fileBatch, len(prs))]
+		wg.Add(1)
+		go func(batch []model.PRItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pages, err := c.firstFilePages(ctx, batch)
+			if err != nil {
+				for _, pr := range batch {
+					record(pr, nil, err)
+				}
+				return
+			}
+			for i, pr := range batch {
+				p := pages[i]
+				if p == nil {
+					record(pr, nil, fmt.Errorf("pull request not found"))
+					continue
+				}
+				record(pr, p, nil)
+				for p.PageInfo.HasNextPage && p.PageInfo.EndCursor != nil {
+					p, err = c.moreFiles(ctx, pr, p.PageInfo.EndCursor)
+					record(pr, p, err)
+					if err != nil {
+						break
+					}
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+	return all, errors.Join(errs...)
+}
+
+// firstFilePages fetches the first page of changed files of every pull
+// request in batch with one aliased query. A nil page means the pull request
+// was not returned.
+func (c *Client) firstFilePages(ctx context.Context, batch []model.PRItem) ([]*filePage, error) {
+	var q strings.Builder
+	q.WriteString("query {\n")
+	for i, pr := range batch {
+		owner, name, _ := strings.Cut(pr.Repo, "/")
+		fmt.Fprintf(&q, `  p%d: repository(owner:%s, name:%s) {
+    pullRequest(number:%d) {
+      files(first:100) {
+        pageInfo { hasNextPage endCursor }
+        nodes { path additions deletions }
+      }
+    }
+  }
+`, i, strconv.Quote(owner), strconv.Quote(name), pr.Number)
+	}
+	q.WriteString("}")
+
+	var out map[string]*struct {
+		PullRequest *struct {
+			Files filePage `json:"files"`
+		} `json:"pullRequest"`
+	}
+	if err := c.doGraphQLAllowMissing(ctx, q.String(), nil, &out); err != nil {
+		return nil, err
+	}
+	pages := make([]*filePage, len(batch))
+	for i := range batch {
+		r := out["p"+strconv.Itoa(i)]
+		if r == nil || r.PullRequest == nil {
+			continue
+		}
+		pages[i] = &r.PullRequest.Files
+	}
+	return pages, nil
+}
+
+// moreFiles fetches the page of pr's changed files after cursor.
+func (c *Client) moreFiles(ctx context.Context, pr model.PRItem, cursor *string) (*filePage, error) {
+	const q = `
+query($owner:String!, $name:String!, $number:Int!, $after:String) {
+  repository(owner:$owner, name:$name) {
+    pullRequest(number:$number) {
+      files(first:100, after:$after) {
+        pageInfo { hasNextPage endCursor }
+        nodes { path additions deletions }
+      }
+    }
+  }
+}`
+	owner, name, _ := strings.Cut(pr.Repo, "/")
+	var out struct {
+		Repository *struct {
+			PullRequest *struct {
+				Files filePage `json:"files"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]any{"owner": owner, "name": name, "number": pr.Number, "after": cursor}
+	if err := c.doGraphQL(ctx, q, vars, &out); err != nil {
+		return nil, err
+	}
+	if out.Repository == nil || out.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request not found")
+	}
+	return &out.Repository.PullRequest.Files, nil
+}
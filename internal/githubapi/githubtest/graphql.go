@@ -0,0 +1,487 @@
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type gqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type gqlError struct {
+	Type    string `json:"type,omitempty"`
+	Path    []any  `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// aliasRe matches the aliased repository blocks of the batch queries.
+var aliasRe = regexp.MustCompile(`(\w+): repository\(owner:("(?:[^"\\]|\\.)*"), name:("(?:[^"\\]|\\.)*")\) \{`)
+
+var (
+	firstRe       = regexp.MustCompile(`search\(query:\$q, type:ISSUE, first:(\d+)`)
+	pullRequestRe = regexp.MustCompile(`pullRequest\(number:(\d+)\)`)
+)
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req gqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON"})
+		return
+	}
+	q := req.Query
+	var (
+		data any
+		errs []gqlError
+	)
+	switch {
+	case strings.Contains(q, "__type("):
+		// Schema introspection: unknown types make the client assume every
+		// field exists.
+		data = map[string]any{"__type": nil}
+	case strings.Contains(q, "contributionsCollection("):
+		data, errs = s.contributions(req.Variables)
+	case strings.Contains(q, "search(query:$q"):
+		data, errs = s.search(q, req.Variables)
+	case strings.Contains(q, "user(login:$login)"):
+		data, errs = s.user(req.Variables)
+	case strings.Contains(q, "repository(owner:$owner"):
+		data, errs = s.repositoryPage(q, req.Variables)
+	case aliasRe.MatchString(q):
+		data, errs = s.aliased(q)
+	default:
+		errs = []gqlError{{Message: "githubtest: query not implemented"}}
+	}
+	out := map[string]any{"data": data}
+	if len(errs) > 0 {
+		out["errors"] = errs
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func stringVar(vars map[string]any, name string) string {
+	s, _ := vars[name].(string)
+	return s
+}
+
+// cursor decodes a page cursor, which is just the offset of the next item.
+func cursor(vars map[string]any) int {
+	n, _ := strconv.Atoi(stringVar(vars, "after"))
+	return n
+}
+
+// page cuts items[offset:offset+n] and describes the page as GraphQL does.
+func page[T any](items []T, offset, n int) ([]T, map[string]any) {
+	start := min(offset, len(items))
+	end := min(start+n, len(items))
+	info := map[string]any{"hasNextPage": end < len(items), "endCursor": nil}
+	if end > start {
+		info["endCursor"] = strconv.Itoa(end)
+	}
+	out := items[start:end]
+	if out == nil {
+		out = []T{}
+	}
+	return out, info
+}
+
+func (s *Server) user(vars map[string]any) (any, []gqlError) {
+	u := s.data.User
+	if !strings.EqualFold(stringVar(vars, "login"), u.Login) {
+		return map[string]any{"user": nil}, []gqlError{{Type: "NOT_FOUND", Path: []any{"user"}, Message: "Could not resolve to a User"}}
+	}
+	return map[string]any{"user": map[string]any{
+		"id":       u.ID,
+		"login":    u.Login,
+		"name":     u.Name,
+		"location": u.Location,
+	}}, nil
+}
+
+func (s *Server) contributions(vars map[string]any) (any, []gqlError) {
+	if !strings.EqualFold(stringVar(vars, "login"), s.data.User.Login) {
+		return map[string]any{"user": nil}, []gqlError{{Type: "NOT_FOUND", Path: []any{"user"}, Message: "Could not resolve to a User"}}
+	}
+	from, err1 := time.Parse(time.RFC3339, stringVar(vars, "from"))
+	to, err2 := time.Parse(time.RFC3339, stringVar(vars, "to"))
+	if err1 != nil || err2 != nil {
+		return nil, []gqlError{{Message: "from and to must be DateTimes"}}
+	}
+	// Like GitHub, contributions are bucketed by UTC day.
+	first, last := from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly)
+
+	totals := map[string]int{}
+	perDay := map[string]int{}
+	type repoCount struct {
+		repo    string
+		count   int
+		private bool
+	}
+	byRepo := map[string]map[string]*repoCount{}
+	for _, c := range s.data.Contributions {
+		if c.Date < first || c.Date > last {
+			continue
+		}
+		totals[c.Kind] += c.Count
+		perDay[c.Date] += c.Count
+		if byRepo[c.Kind] == nil {
+			byRepo[c.Kind] = map[string]*repoCount{}
+		}
+		rc := byRepo[c.Kind][c.Repo]
+		if rc == nil {
+			rc = &repoCount{repo: c.Repo, private: c.Private}
+			byRepo[c.Kind][c.Repo] = rc
+		}
+		rc.count += c.Count
+	}
+	list := func(kind string) []any {
+		var rcs []*repoCount
+		for _, rc := range byRepo[kind] {
+			rcs = append(rcs, rc)
+		}
+		sort.Slice(rcs, func(i, j int) bool {
+			if rcs[i].count != rcs[j].count {
+				return rcs[i].count > rcs[j].count
+			}
+			return rcs[i].repo < rcs[j].repo
+		})
+		out := []any{}
+		for _, rc := range rcs[:min(len(rcs), 100)] {
+			out = append(out, map[string]any{
+				"repository":    map[string]any{"nameWithOwner": rc.repo, "isPrivate": rc.private},
+				"contributions": map[string]any{"totalCount": rc.count},
+			})
+		}
+		return out
+	}
+
+	// The calendar lists every day of the range in Sunday-first weeks.
+	var weeks []any
+	var days []any
+	start, _ := time.Parse(time.DateOnly, first)
+	end, _ := time.Parse(time.DateOnly, last)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Sunday && len(days) > 0 {
+			weeks = append(weeks, map[string]any{"contributionDays": days})
+			days = nil
+		}
+		date := d.Format(time.DateOnly)
+		days = append(days, map[string]any{"date": date, "contributionCount": perDay[date]})
+	}
+	if len(days) > 0 {
+		weeks = append(weeks, map[string]any{"contributionDays": days})
+	}
+
+	return map[string]any{"user": map[string]any{"contributionsCollection": map[string]any{
+		"totalCommitContributions":                   totals["commit"],
+		"totalPullRequestContributions":              totals["pr"],
+		"totalIssueContributions":                    totals["issue"],
+		"totalPullRequestReviewContributions":        totals["review"],
+		"contributionCalendar":                       map[string]any{"weeks": weeks},
+		"commitContributionsByRepository":            list("commit"),
+		"pullRequestContributionsByRepository":       list("pr"),
+		"issueContributionsByRepository":             list("issue"),
+		"pullRequestReviewContributionsByRepository": list("review"),
+	}}}, nil
+}
+
+// searchQuery is a parsed issue search: author:, is: and one created: or
+// closed: range. Other qualifiers are not implemented.
+type searchQuery struct {
+	author, is, field string
+	from, to          time.Time
+}
+
+func parseSearch(q string) (searchQuery, error) {
+	var sq searchQuery
+	for _, term := range strings.Fields(q) {
+		key, val, _ := strings.Cut(term, ":")
+		switch key {
+		case "author":
+			sq.author = val
+		case "is":
+			sq.is = val
+		case "created", "closed":
+			a, b, ok := strings.Cut(val, "..")
+			from, err1 := time.Parse(time.RFC3339, a)
+			to, err2 := time.Parse(time.RFC3339, b)
+			if !ok || err1 != nil || err2 != nil {
+				return sq, fmt.Errorf("githubtest: bad range %q", term)
+			}
+			sq.field, sq.from, sq.to = key, from, to
+		default:
+			return sq, fmt.Errorf("githubtest: search qualifier %q not implemented", term)
+		}
+	}
+	if sq.author == "" || (sq.is != "pr" && sq.is != "issue") || sq.field == "" {
+		return sq, fmt.Errorf("githubtest: search %q not implemented", q)
+	}
+	return sq, nil
+}
+
+func (sq searchQuery) in(t *time.Time) bool {
+	return t != nil && !t.Before(sq.from) && !t.After(sq.to)
+}
+
+func (s *Server) search(q string, vars map[string]any) (any, []gqlError) {
+	sq, err := parseSearch(stringVar(vars, "q"))
+	if err != nil {
+		return nil, []gqlError{{Message: err.Error()}}
+	}
+	var nodes []any
+	if strings.EqualFold(sq.author, s.data.User.Login) {
+		if sq.is == "pr" {
+			for _, pr := range s.data.PullRequests {
+				t := &pr.CreatedAt
+				if sq.field == "closed" {
+					t = pr.ClosedAt
+				}
+				if sq.in(t) {
+					nodes = append(nodes, prNode(pr))
+				}
+			}
+		} else {
+			for _, is := range s.data.Issues {
+				t := &is.CreatedAt
+				if sq.field == "closed" {
+					t = is.ClosedAt
+				}
+				if sq.in(t) {
+					nodes = append(nodes, issueNode(is))
+				}
+			}
+		}
+	}
+
+	first := 100
+	if m := firstRe.FindStringSubmatch(q); m != nil {
+		first, _ = strconv.Atoi(m[1])
+	}
+	result := map[string]any{"issueCount": len(nodes)}
+	if first > 1 {
+		result["nodes"], result["pageInfo"] = page(nodes, cursor(vars), first)
+	}
+	return map[string]any{"search": result}, nil
+}
+
+func prNode(pr PullRequest) map[string]any {
+	return map[string]any{
+		"number":     pr.Number,
+		"title":      pr.Title,
+		"url":        fmt.Sprintf("https://github.com/%s/pull/%d", pr.Repo, pr.Number),
+		"createdAt":  pr.CreatedAt,
+		"merged":     pr.MergedAt != nil,
+		"mergedAt":   pr.MergedAt,
+		"additions":  pr.Additions,
+		"deletions":  pr.Deletions,
+		"repository": map[string]any{"nameWithOwner": pr.Repo, "isPrivate": pr.Private},
+	}
+}
+
+func issueNode(is Issue) map[string]any {
+	return map[string]any{
+		"number":     is.Number,
+		"title":      is.Title,
+		"url":        fmt.Sprintf("https://github.com/%s/issues/%d", is.Repo, is.Number),
+		"createdAt":  is.CreatedAt,
+		"closedAt":   is.ClosedAt,
+		"repository": map[string]any{"nameWithOwner": is.Repo, "isPrivate": is.Private},
+	}
+}
+
+func (s *Server) pullRequest(repo string, number int) *PullRequest {
+	for i := range s.data.PullRequests {
+		if pr := &s.data.PullRequests[i]; strings.EqualFold(pr.Repo, repo) && pr.Number == number {
+			return pr
+		}
+	}
+	return nil
+}
+
+// repositoryPage answers the single-repository queries: the next page of
+// stargazers or of a pull request's files, and default-branch history.
+func (s *Server) repositoryPage(q string, vars map[string]any) (any, []gqlError) {
+	full := stringVar(vars, "owner") + "/" + stringVar(vars, "name")
+	rp := s.repo(full)
+	if rp == nil {
+		return map[string]any{"repository": nil}, []gqlError{{Type: "NOT_FOUND", Path: []any{"repository"}, Message: "Could not resolve to a Repository with the name '" + full + "'."}}
+	}
+	switch {
+	case strings.Contains(q, "stargazers("):
+		return map[string]any{"repository": map[string]any{"stargazers": stargazers(rp, cursor(vars), 100)}}, nil
+	case strings.Contains(q, "history("):
+		return map[string]any{"repository": s.history(full, vars)}, nil
+	case strings.Contains(q, "pullRequest(number:$number)"):
+		n, _ := vars["number"].(float64)
+		pr := s.pullRequest(full, int(n))
+		if pr == nil {
+			return map[string]any{"repository": map[string]any{"pullRequest": nil}}, []gqlError{{Type: "NOT_FOUND", Path: []any{"repository", "pullRequest"}, Message: "Could not resolve to a PullRequest"}}
+		}
+		return map[string]any{"repository": map[string]any{"pullRequest": map[string]any{"files": files(pr, cursor(vars))}}}, nil
+	}
+	return nil, []gqlError{{Message: "githubtest: repository query not implemented"}}
+}
+
+// aliased answers the batch queries, one aliased repository block each:
+// stargazers, pull request files or metadata. Repositories that don't exist
+// come back null with a NOT_FOUND error, as on GitHub.
+func (s *Server) aliased(q string) (any, []gqlError) {
+	data := map[string]any{}
+	var errs []gqlError
+	locs := aliasRe.FindAllStringSubmatchIndex(q, -1)
+	for i, loc := range locs {
+		end := len(q)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		block := q[loc[0]:end]
+		alias := q[loc[2]:loc[3]]
+		owner, _ := strconv.Unquote(q[loc[4]:loc[5]])
+		name, _ := strconv.Unquote(q[loc[6]:loc[7]])
+		full := owner + "/" + name
+
+		rp := s.repo(full)
+		if rp == nil {
+			data[alias] = nil
+			errs = append(errs, gqlError{Type: "NOT_FOUND", Path: []any{alias}, Message: "Could not resolve to a Repository with the name '" + full + "'."})
+			continue
+		}
+		switch {
+		case strings.Contains(block, "newest: stargazers("):
+			oldest := []any{}
+			if stars := newestFirst(rp.Stargazers); len(stars) > 0 {
+				oldest = append(oldest, map[string]any{"starredAt": stars[len(stars)-1]})
+			}
+			data[alias] = map[string]any{
+				"newest": stargazers(rp, 0, 100),
+				"oldest": map[string]any{"edges": oldest},
+			}
+		case pullRequestRe.MatchString(block):
+			n, _ := strconv.Atoi(pullRequestRe.FindStringSubmatch(block)[1])
+			pr := s.pullRequest(full, n)
+			if pr == nil {
+				data[alias] = map[string]any{"pullRequest": nil}
+				errs = append(errs, gqlError{Type: "NOT_FOUND", Path: []any{alias, "pullRequest"}, Message: "Could not resolve to a PullRequest"})
+				continue
+			}
+			data[alias] = map[string]any{"pullRequest": map[string]any{"files": files(pr, 0)}}
+		case strings.Contains(block, "nameWithOwner"):
+			data[alias] = repoMeta(rp)
+		default:
+			return nil, []gqlError{{Message: "githubtest: aliased query not implemented"}}
+		}
+	}
+	return data, errs
+}
+
+// history is a page of full's default-branch commits by the $author ID
+// committed between $since and $until, newest first. A repository without
+// commits has no default branch.
+func (s *Server) history(full string, vars map[string]any) map[string]any {
+	since, _ := time.Parse(time.RFC3339, stringVar(vars, "since"))
+	until, _ := time.Parse(time.RFC3339, stringVar(vars, "until"))
+	mine := stringVar(vars, "author") == s.data.User.ID
+
+	var commits []Commit
+	empty := true
+	for _, c := range s.data.Commits {
+		if !strings.EqualFold(c.Repo, full) {
+			continue
+		}
+		empty = false
+		author := c.Author == "" || strings.EqualFold(c.Author, s.data.User.Login)
+		if author == mine && !c.CommittedAt.Before(since) && !c.CommittedAt.After(until) {
+			commits = append(commits, c)
+		}
+	}
+	if empty {
+		return map[string]any{"defaultBranchRef": nil}
+	}
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].CommittedAt.After(commits[j].CommittedAt) })
+
+	var nodes []any
+	for _, c := range commits {
+		headline, _, _ := strings.Cut(c.Message, "\n")
+		authored := c.AuthoredAt
+		if authored.IsZero() {
+			authored = c.CommittedAt
+		}
+		nodes = append(nodes, map[string]any{
+			"oid":             c.OID,
+			"url":             "https://github.com/" + full + "/commit/" + c.OID,
+			"committedDate":   c.CommittedAt,
+			"authoredDate":    authored,
+			"additions":       c.Additions,
+			"deletions":       c.Deletions,
+			"messageHeadline": headline,
+			"message":         c.Message,
+		})
+	}
+	nodes, info := page(nodes, cursor(vars), 100)
+	return map[string]any{"defaultBranchRef": map[string]any{"target": map[string]any{
+		"history": map[string]any{"pageInfo": info, "nodes": nodes},
+	}}}
+}
+
+func stargazers(rp *Repo, offset, n int) map[string]any {
+	var edges []any
+	for _, t := range newestFirst(rp.Stargazers) {
+		edges = append(edges, map[string]any{"starredAt": t})
+	}
+	edges, info := page(edges, offset, n)
+	return map[string]any{"pageInfo": info, "edges": edges}
+}
+
+func files(pr *PullRequest, offset int) map[string]any {
+	var nodes []any
+	for _, f := range pr.Files {
+		nodes = append(nodes, map[string]any{"path": f.Path, "additions": f.Additions, "deletions": f.Deletions})
+	}
+	nodes, info := page(nodes, offset, 100)
+	return map[string]any{"pageInfo": info, "nodes": nodes}
+}
+
+func repoMeta(rp *Repo) map[string]any {
+	var primary any
+	if rp.PrimaryLanguage != "" {
+		primary = map[string]any{"name": rp.PrimaryLanguage}
+	}
+	topics := []any{}
+	for _, t := range rp.Topics {
+		topics = append(topics, map[string]any{"topic": map[string]any{"name": t}})
+	}
+	langs := make([]string, 0, len(rp.Languages))
+	for l := range rp.Languages {
+		langs = append(langs, l)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if rp.Languages[langs[i]] != rp.Languages[langs[j]] {
+			return rp.Languages[langs[i]] > rp.Languages[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	edges := []any{}
+	for _, l := range langs {
+		edges = append(edges, map[string]any{"size": rp.Languages[l], "node": map[string]any{"name": l}})
+	}
+	return map[string]any{
+		"nameWithOwner":    rp.fullName(),
+		"description":      rp.Description,
+		"primaryLanguage":  primary,
+		"repositoryTopics": map[string]any{"nodes": topics},
+		"stargazerCount":   len(rp.Stargazers),
+		"forkCount":        len(rp.Forks),
+		"owner":            map[string]any{"__typename": rp.OwnerType, "login": rp.Owner},
+		"isFork":           rp.Fork,
+		"isArchived":       rp.Archived,
+		"isPrivate":        rp.Private,
+		"languages":        map[string]any{"edges": edges},
+	}
+}
@@ -0,0 +1,113 @@
+package githubtest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request, path string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case path == "/user":
+		w.Header().Set("X-OAuth-Scopes", s.data.User.Scopes)
+		writeJSON(w, http.StatusOK, map[string]any{"login": s.data.User.Login, "type": "User"})
+	case path == "/meta":
+		writeJSON(w, http.StatusOK, map[string]any{"installed_version": "fake"})
+	case path == "/user/repos":
+		var out []any
+		for i := range s.data.Repos {
+			if rp := &s.data.Repos[i]; !strings.EqualFold(rp.Owner, s.data.User.Login) && rp.Permissions != nil {
+				out = append(out, restRepo(rp))
+			}
+		}
+		writePage(w, r, out)
+	case len(parts) == 3 && parts[0] == "users" && parts[2] == "repos":
+		var out []any
+		for i := range s.data.Repos {
+			if rp := &s.data.Repos[i]; strings.EqualFold(rp.Owner, parts[1]) {
+				out = append(out, restRepo(rp))
+			}
+		}
+		writePage(w, r, out)
+	case len(parts) >= 3 && parts[0] == "repos":
+		rp := s.repo(parts[1] + "/" + parts[2])
+		if rp == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		switch strings.Join(parts[3:], "/") {
+		case "":
+			writeJSON(w, http.StatusOK, restRepo(rp))
+		case "languages":
+			writeJSON(w, http.StatusOK, rp.Languages)
+		case "forks":
+			var out []any
+			for i, t := range newestFirst(rp.Forks) {
+				out = append(out, map[string]any{
+					"full_name":  fmt.Sprintf("fork%d/%s", i, rp.Name),
+					"created_at": t.Format(time.RFC3339),
+				})
+			}
+			writePage(w, r, out)
+		case "stargazers":
+			// Oldest first, as the REST API lists them.
+			stars := newestFirst(rp.Stargazers)
+			var out []any
+			for i := len(stars) - 1; i >= 0; i-- {
+				out = append(out, map[string]any{"starred_at": stars[i].Format(time.RFC3339), "user": map[string]any{"login": fmt.Sprintf("stargazer%d", i)}})
+			}
+			writePage(w, r, out)
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		}
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+func restRepo(rp *Repo) map[string]any {
+	return map[string]any{
+		"name":             rp.Name,
+		"full_name":        rp.fullName(),
+		"owner":            map[string]any{"login": rp.Owner, "type": rp.OwnerType},
+		"description":      rp.Description,
+		"language":         rp.PrimaryLanguage,
+		"topics":           rp.Topics,
+		"private":          rp.Private,
+		"fork":             rp.Fork,
+		"archived":         rp.Archived,
+		"stargazers_count": len(rp.Stargazers),
+		"forks_count":      len(rp.Forks),
+		"permissions":      rp.Permissions,
+	}
+}
+
+// writePage serves one page of items as the REST API paginates them, with a
+// Link header pointing at the next page.
+func writePage(w http.ResponseWriter, r *http.Request, items []any) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 30
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	if end < len(items) {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		next := *r.URL
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+	out := items[start:end]
+	if out == nil {
+		out = []any{}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
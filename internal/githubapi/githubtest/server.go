@@ -0,0 +1,234 @@
+// Package githubtest runs an in-process fake GitHub for tests and offline
+// runs. It serves the GraphQL and REST calls the recap makes for
+// contributions, PR and issue search, PR files, repository metadata and
+// languages, stargazers and forks, and commit history, all answered from
+// fixture files:
+//
+//	user.json           the recapped user (login, id, name, location, scopes)
+//	contributions.json  dated contributions: date, repo, kind, count, private
+//	pull_requests.json  the user's PRs, with their changed files
+//	issues.json         the user's issues
+//	repos.json          repositories: metadata, languages, stargazers, forks
+//	commits.json        default-branch commits of those repositories
+//
+// Endpoints are served both at github.com paths and at Enterprise Server
+// paths (/api/v3, /api/graphql); Config uses the latter.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+)
+
+// User is user.json.
+type User struct {
+	Login    string `json:"login"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	Scopes   string `json:"scopes"` // X-OAuth-Scopes, e.g. "repo, read:org"
+}
+
+// Contribution is one entry of contributions.json: count contributions of
+// one kind ("commit", "pr", "issue" or "review") to repo on date.
+type Contribution struct {
+	Date    string `json:"date"` // YYYY-MM-DD
+	Repo    string `json:"repo"`
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Private bool   `json:"private"`
+}
+
+// PullRequest is one entry of pull_requests.json.
+type PullRequest struct {
+	Repo      string     `json:"repo"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+	Private   bool       `json:"private"`
+	Files     []File     `json:"files"`
+}
+
+type File struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Issue is one entry of issues.json.
+type Issue struct {
+	Repo      string     `json:"repo"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	Private   bool       `json:"private"`
+}
+
+// Commit is one entry of commits.json, a commit on repo's default branch.
+type Commit struct {
+	Repo        string    `json:"repo"`
+	OID         string    `json:"oid"`
+	Author      string    `json:"author"` // login; empty is the recapped user
+	CommittedAt time.Time `json:"committed_at"`
+	AuthoredAt  time.Time `json:"authored_at"`
+	Additions   int       `json:"additions"`
+	Deletions   int       `json:"deletions"`
+	Message     string    `json:"message"`
+}
+
+// Repo is one entry of repos.json. Star and fork counts are the lengths of
+// Stargazers and Forks.
+type Repo struct {
+	Owner           string           `json:"owner"`
+	Name            string           `json:"name"`
+	OwnerType       string           `json:"owner_type"` // "User" (default) or "Organization"
+	Description     string           `json:"description"`
+	PrimaryLanguage string           `json:"primary_language"`
+	Topics          []string         `json:"topics"`
+	Fork            bool             `json:"fork"`
+	Archived        bool             `json:"archived"`
+	Private         bool             `json:"private"`
+	Permissions     map[string]bool  `json:"permissions"` // the user's, for org repos
+	Languages       map[string]int64 `json:"languages"`
+	Stargazers      []time.Time      `json:"stargazers"`
+	Forks           []time.Time      `json:"forks"`
+}
+
+func (r *Repo) fullName() string { return r.Owner + "/" + r.Name }
+
+// Fixtures is a complete fixture set.
+type Fixtures struct {
+	User          User
+	Contributions []Contribution
+	PullRequests  []PullRequest
+	Issues        []Issue
+	Repos         []Repo
+	Commits       []Commit
+}
+
+// LoadFixtures reads a fixture set from dir. Every file but user.json is
+// optional.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	f := &Fixtures{}
+	for name, dst := range map[string]any{
+		"user.json":          &f.User,
+		"contributions.json": &f.Contributions,
+		"pull_requests.json": &f.PullRequests,
+		"issues.json":        &f.Issues,
+		"repos.json":         &f.Repos,
+		"commits.json":       &f.Commits,
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) && name != "user.json" {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, dst); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	for i := range f.Repos {
+		if f.Repos[i].OwnerType == "" {
+			f.Repos[i].OwnerType = "User"
+		}
+	}
+	return f, nil
+}
+
+// FixtureDir returns the path of a fixture set shipped in this package's
+// testdata directory, e.g. FixtureDir("octocat").
+func FixtureDir(name string) string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", name)
+}
+
+// Server is a running fake GitHub.
+type Server struct {
+	*httptest.Server
+	data     *Fixtures
+	requests atomic.Int64
+}
+
+// NewServer starts a fake GitHub serving the fixtures in dir. Close it when
+// done.
+func NewServer(dir string) (*Server, error) {
+	data, err := LoadFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	return NewServerFixtures(data), nil
+}
+
+// NewServerFixtures starts a fake GitHub serving data.
+func NewServerFixtures(data *Fixtures) *Server {
+	s := &Server{data: data}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Config points a client at s, with a dummy token.
+func (s *Server) Config() config.Config {
+	return config.Config{
+		Token:      "ghp_fake",
+		APIBase:    s.URL + "/api/v3",
+		GraphQLEnd: s.URL + "/api/graphql",
+	}
+}
+
+// Requests is how many requests s has served.
+func (s *Server) Requests() int {
+	return int(s.requests.Load())
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	switch {
+	case r.Method == http.MethodPost && (r.URL.Path == "/graphql" || r.URL.Path == "/api/graphql"):
+		s.serveGraphQL(w, r)
+	case r.Method == http.MethodGet:
+		s.serveREST(w, r, path)
+	default:
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	}
+}
+
+func (s *Server) repo(full string) *Repo {
+	for i := range s.data.Repos {
+		if strings.EqualFold(s.data.Repos[i].fullName(), full) {
+			return &s.data.Repos[i]
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// newestFirst returns ts sorted newest first.
+func newestFirst(ts []time.Time) []time.Time {
+	out := append([]time.Time(nil), ts...)
+	sort.Slice(out, func(i, j int) bool { return out[i].After(out[j]) })
+	return out
+}
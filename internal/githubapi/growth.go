@@ -0,0 +1,196 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+	"github.com/google/go-github/v62/github"
+)
+
+// Why a repository is in the growth section, as reported in GrowthRepo.Source.
+const (
+	SourceOwner          = "owner"           // owned by the user
+	SourceAllowlist      = "allowlist"       // listed with --growth-repos
+	SourceOrgMaintained  = "org_maintained"  // org repo the user administers or maintains
+	SourceTopContributed = "top_contributed" // one of the user's most contributed-to repos
+)
+
+// GrowthSelection picks the repositories whose stars and forks are counted
+// besides the user's own.
+type GrowthSelection struct {
+	// OrgMaintained adds organization repositories the user has admin or
+	// maintain permission on. Permissions are those of the token, so this
+	// needs a token that belongs to the user.
+	OrgMaintained bool
+	// Top adds these repositories ("owner/name"), typically the user's most
+	// contributed-to ones.
+	Top []string
+	// Repos is an explicit allowlist ("owner/name"). Listed forks are kept.
+	Repos []string
+	// IncludeForks keeps forks of upstream projects, which are dropped by
+	// default.
+	IncludeForks bool
+}
+
+// growthRepo is a candidate repository and why it was picked.
+type growthRepo struct {
+	owner, name string
+	ownerType   string
+	stars       int
+	forks       int
+	private     bool
+	fork        bool
+	source      string
+}
+
+func restGrowthRepo(r *github.Repository, source string) growthRepo {
+	return growthRepo{
+		owner:     r.GetOwner().GetLogin(),
+		name:      r.GetName(),
+		ownerType: r.GetOwner().GetType(),
+		stars:     r.GetStargazersCount(),
+		forks:     r.GetForksCount(),
+		private:   r.GetPrivate(),
+		fork:      r.GetFork(),
+		source:    source,
+	}
+}
+
+func metaGrowthRepo(m *model.RepoMeta, source string) growthRepo {
+	owner, name, _ := strings.Cut(m.Repo, "/")
+	return growthRepo{
+		owner:     owner,
+		name:      name,
+		ownerType: m.OwnerType,
+		stars:     m.Stars,
+		forks:     m.Forks,
+		private:   m.IsPrivate,
+		fork:      m.IsFork,
+		source:    source,
+	}
+}
+
+func (g growthRepo) String() string { return g.owner + "/" + g.name }
+
+// growthRepos lists the repositories the growth section covers: login's own,
+// then those sel adds, each once. Forks are dropped unless sel keeps them and
+// returned by name. Sources that cannot be listed are skipped and their
+// failures returned as *RequestError, joined.
+func (c *Client) growthRepos(ctx context.Context, client *github.Client, login string, sel GrowthSelection) ([]growthRepo, []string, error) {
+	var (
+		out   []growthRepo
+		forks []string
+		errs  []error
+		seen  = map[string]bool{}
+		// Allowlisted forks are kept whichever source lists them first.
+		allowed = map[string]bool{}
+	)
+	for _, name := range sel.Repos {
+		allowed[strings.ToLower(name)] = true
+	}
+	add := func(g growthRepo) {
+		if g.owner == "" || g.name == "" {
+			return
+		}
+		full := strings.ToLower(g.String())
+		if seen[full] {
+			return
+		}
+		seen[full] = true
+		if g.fork && !sel.IncludeForks && !allowed[full] {
+			forks = append(forks, g.String())
+			return
+		}
+		out = append(out, g)
+	}
+
+	opt := &github.RepositoryListByUserOptions{
+		Type:        "owner",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		repos, resp, err := client.Repositories.ListByUser(restCtx(ctx), login, opt)
+		if err != nil {
+			errs = append(errs, &RequestError{Op: "list_owned_repos", Err: err})
+			break
+		}
+		for _, r := range repos {
+			add(restGrowthRepo(r, SourceOwner))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	// Allowlisted and top repos come from the shared metadata store, which
+	// the language collector has usually filled already.
+	get := func(names []string, source string) {
+		var want []string
+		for _, name := range names {
+			if seen[strings.ToLower(name)] {
+				continue
+			}
+			if owner, repo, ok := strings.Cut(name, "/"); !ok || owner == "" || repo == "" {
+				errs = append(errs, &RequestError{Op: "repo_metadata", Repo: name, Err: fmt.Errorf("want owner/name")})
+				continue
+			}
+			want = append(want, name)
+		}
+		metas, err := c.RepoMetadata(ctx, want)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, name := range want {
+			if m := metas[name]; m != nil {
+				add(metaGrowthRepo(m, source))
+			}
+		}
+	}
+	get(sel.Repos, SourceAllowlist)
+
+	if sel.OrgMaintained {
+		if err := c.orgMaintained(ctx, client, login, func(r *github.Repository) { add(restGrowthRepo(r, SourceOrgMaintained)) }); err != nil {
+			errs = append(errs, &RequestError{Op: "list_org_repos", Err: err})
+		}
+	}
+
+	get(sel.Top, SourceTopContributed)
+	return out, forks, errors.Join(errs...)
+}
+
+// orgMaintained passes every organization repository the token's user can
+// administer or maintain to add. The permissions GitHub reports are the
+// viewer's, so the token has to belong to login.
+func (c *Client) orgMaintained(ctx context.Context, client *github.Client, login string, add func(*github.Repository)) error {
+	viewer, _, err := client.Users.Get(restCtx(ctx), "")
+	if err != nil {
+		return fmt.Errorf("get authenticated user: %w", err)
+	}
+	if !strings.EqualFold(viewer.GetLogin(), login) {
+		return fmt.Errorf("token belongs to %s, not %s: org repository permissions are the viewer's", viewer.GetLogin(), login)
+	}
+	opt := &github.RepositoryListByAuthenticatedUserOptions{
+		Affiliation: "organization_member",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		repos, resp, err := client.Repositories.ListByAuthenticatedUser(restCtx(ctx), opt)
+		if err != nil {
+			return err
+		}
+		for _, r := range repos {
+			if p := r.GetPermissions(); p["admin"] || p["maintain"] {
+				add(r)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return nil
+}
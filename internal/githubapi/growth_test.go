@@ -0,0 +1,95 @@
+package githubapi
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+)
+
+func TestGrowthRepos(t *testing.T) {
+	maintain := map[string]bool{"maintain": true, "pull": true}
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{
+		User: githubtest.User{Login: "octocat"},
+		Repos: []githubtest.Repo{
+			{Owner: "octocat", Name: "hello-world", OwnerType: "User"},
+			{Owner: "octocat", Name: "linguist", OwnerType: "User", Fork: true},
+			{Owner: "octocat", Name: "Spoon-Knife", OwnerType: "User", Fork: true},
+			{Owner: "octo-org", Name: "platform", OwnerType: "Organization", Permissions: maintain},
+			{Owner: "octo-org", Name: "vendored-lib", OwnerType: "Organization", Fork: true, Permissions: maintain},
+			{Owner: "upstream", Name: "patched", OwnerType: "Organization", Fork: true},
+			{Owner: "acme", Name: "widgets", OwnerType: "Organization"},
+		},
+	})
+	defer srv.Close()
+	c, err := New(srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := c.rest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sel := GrowthSelection{
+		OrgMaintained: true,
+		// An owned fork and someone else's fork, listed in other cases.
+		Repos: []string{"OctoCat/spoon-knife", "Upstream/Patched"},
+		// Already counted as org-maintained, and one new repo.
+		Top: []string{"OCTO-ORG/platform", "acme/widgets"},
+	}
+	tests := []struct {
+		name     string
+		forks    bool
+		repos    map[string]string // repo -> source
+		excluded []string
+	}{
+		{
+			name: "forks dropped unless allowlisted",
+			repos: map[string]string{
+				"octocat/hello-world": SourceOwner,
+				"octocat/Spoon-Knife": SourceOwner,
+				"upstream/patched":    SourceAllowlist,
+				"octo-org/platform":   SourceOrgMaintained,
+				"acme/widgets":        SourceTopContributed,
+			},
+			excluded: []string{"octo-org/vendored-lib", "octocat/linguist"},
+		},
+		{
+			name:  "--growth-forks keeps every fork",
+			forks: true,
+			repos: map[string]string{
+				"octocat/hello-world":   SourceOwner,
+				"octocat/linguist":      SourceOwner,
+				"octocat/Spoon-Knife":   SourceOwner,
+				"upstream/patched":      SourceAllowlist,
+				"octo-org/platform":     SourceOrgMaintained,
+				"octo-org/vendored-lib": SourceOrgMaintained,
+				"acme/widgets":          SourceTopContributed,
+			},
+		},
+	}
+	for _, tt := range tests {
+		sel.IncludeForks = tt.forks
+		repos, excluded, err := c.growthRepos(context.Background(), rc, "octocat", sel)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := map[string]string{}
+		for _, r := range repos {
+			if _, dup := got[r.String()]; dup {
+				t.Errorf("%s: %s listed twice", tt.name, r)
+			}
+			got[r.String()] = r.source
+		}
+		if !reflect.DeepEqual(got, tt.repos) {
+			t.Errorf("%s: repos = %v, want %v", tt.name, got, tt.repos)
+		}
+		sort.Strings(excluded)
+		if !reflect.DeepEqual(excluded, tt.excluded) {
+			t.Errorf("%s: excluded = %v, want %v", tt.name, excluded, tt.excluded)
+		}
+	}
+}
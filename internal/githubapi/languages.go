@@ -0,0 +1,37 @@
+package githubapi
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// FetchLanguagesForContributedRepos returns the language breakdown of every
+// repository in cc, with whether it is a fork or archived; weighting them is
+// up to the caller. The breakdowns come from the shared repository metadata
+// (see RepoMetadata). A repository that fails is left out; every failure is
+// returned as a *RequestError, joined.
+func (c *Client) FetchLanguagesForContributedRepos(ctx context.Context, cc *model.ContributionsCollection) ([]model.RepoLanguages, error) {
+	seen := map[string]bool{}
+	var repos []string
+	for _, m := range []map[string]model.RepoContribLite{cc.ByRepoCommits, cc.ByRepoPRs, cc.ByRepoIssues, cc.ByRepoReviews} {
+		for full := range m {
+			if !seen[full] && strings.Count(full, "/") == 1 {
+				seen[full] = true
+				repos = append(repos, full)
+			}
+		}
+	}
+	sort.Strings(repos)
+
+	metas, err := c.RepoMetadata(ctx, repos)
+	all := make([]model.RepoLanguages, 0, len(metas))
+	for _, full := range repos {
+		if m := metas[full]; m != nil {
+			all = append(all, model.RepoLanguages{Repo: full, Languages: m.Languages, IsFork: m.IsFork, IsArchived: m.IsArchived})
+		}
+	}
+	return all, err
+}
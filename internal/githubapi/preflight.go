@@ -0,0 +1,135 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// sectionNeeds is what each recap section needs beyond public data: a
+// classic PAT / OAuth scope, or a read permission on a fine-grained PAT or
+// GitHub App installation.
+var sectionNeeds = []struct {
+	section    string
+	scope      string
+	permission string
+	effect     string
+}{
+	{"contributions", "repo", "metadata", "private contributions are counted but not attributed to repositories"},
+	{"pull_requests", "repo", "pull_requests", "PRs in private repositories are not found by search"},
+	{"issues", "repo", "issues", "issues in private repositories are not found by search"},
+	{"commits", "repo", "contents", "history of private repositories cannot be walked"},
+	{"reviews", "repo", "pull_requests", "reviews on private PRs are missing"},
+	{"languages", "repo", "metadata", "languages of private repositories are missing"},
+	{"growth", "repo", "metadata", "stars and forks of private repositories are missing"},
+	{"team", "read:org", "members", "private org and team members are not listed"},
+}
+
+// impliedScopes lists the broader scopes that also grant a scope.
+var impliedScopes = map[string][]string{
+	"read:org": {"write:org", "admin:org"},
+}
+
+// AuthMethod reports where the credentials came from (AuthToken, AuthApp...).
+func (c *Client) AuthMethod() string {
+	return c.authMethod
+}
+
+// Preflight checks the credentials before collecting and reports, for each
+// of sections, the scopes or permissions it would need but the token lacks.
+// login is the user being recapped (empty in team mode); a token that belongs
+// to someone else cannot see their private contributions.
+func (c *Client) Preflight(ctx context.Context, login string, sections []string) (*model.Preflight, error) {
+	tok, err := c.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+	p := &model.Preflight{
+		Method:    c.authMethod,
+		TokenKind: tokenKind(tok.AccessToken),
+	}
+
+	var granted func(scope, permission string) bool
+	verified := true
+	switch p.TokenKind {
+	case "app_installation":
+		if c.app != nil {
+			p.Permissions = c.app.grantedPermissions()
+		}
+		if p.Permissions == nil {
+			verified = false
+		}
+		granted = func(_, permission string) bool { return p.Permissions[permission] != "" }
+		p.Warnings = append(p.Warnings, "GitHub App installation tokens only see repositories the app is installed on; contributions elsewhere are counted without repository details")
+	default:
+		client, err := c.rest()
+		if err != nil {
+			return nil, err
+		}
+		viewer, resp, err := client.Users.Get(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("get authenticated user: %w", err)
+		}
+		p.Viewer = viewer.GetLogin()
+		if header := resp.Header.Get("X-OAuth-Scopes"); header != "" {
+			for _, s := range strings.Split(header, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					p.Scopes = append(p.Scopes, s)
+				}
+			}
+			sort.Strings(p.Scopes)
+		}
+		switch p.TokenKind {
+		case "classic_pat", "oauth":
+			granted = func(scope, _ string) bool { return hasScope(p.Scopes, scope) }
+		default:
+			// Fine-grained PATs and app user tokens don't expose their
+			// permissions; the needs are still listed.
+			verified = false
+		}
+		if login != "" && !strings.EqualFold(p.Viewer, login) {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("token belongs to %s, not %s: %s's private contributions and repositories are not visible", p.Viewer, login, login))
+		}
+	}
+
+	want := map[string]bool{}
+	for _, s := range sections {
+		want[s] = true
+	}
+	for _, n := range sectionNeeds {
+		if !want[n.section] {
+			continue
+		}
+		a := model.SectionAccess{Section: n.section, Verified: verified}
+		if p.TokenKind == "classic_pat" || p.TokenKind == "oauth" {
+			a.Needs = []string{n.scope}
+		} else {
+			a.Needs = []string{n.permission + ":read"}
+		}
+		if verified && !granted(n.scope, n.permission) {
+			a.Missing = a.Needs
+		}
+		if !verified || len(a.Missing) > 0 {
+			a.Effect = n.effect
+		}
+		p.Sections = append(p.Sections, a)
+	}
+	return p, nil
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+		for _, broader := range impliedScopes[scope] {
+			if s == broader {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package githubapi
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{[]string{"repo", "read:org"}, "repo", true},
+		{[]string{"public_repo"}, "repo", false},
+		{[]string{"read:org"}, "read:org", true},
+		// Broader org scopes include read:org.
+		{[]string{"write:org"}, "read:org", true},
+		{[]string{"admin:org"}, "read:org", true},
+		// ...but not the other way round.
+		{[]string{"read:org"}, "admin:org", false},
+		{[]string{"repo"}, "read:org", false},
+		{nil, "repo", false},
+	}
+	for _, tt := range tests {
+		if got := hasScope(tt.scopes, tt.scope); got != tt.want {
+			t.Errorf("hasScope(%v, %q) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func section(p *model.Preflight, name string) model.SectionAccess {
+	for _, s := range p.Sections {
+		if s.Section == name {
+			return s
+		}
+	}
+	return model.SectionAccess{}
+}
+
+func TestPreflightScopes(t *testing.T) {
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{
+		User: githubtest.User{Login: "octocat", Scopes: " write:org,public_repo , "},
+	})
+	defer srv.Close()
+	c, err := New(srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := c.Preflight(context.Background(), "octocat", []string{"team", "contributions", "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Method != AuthToken || p.TokenKind != "classic_pat" || p.Viewer != "octocat" {
+		t.Errorf("preflight = %+v", p)
+	}
+	if !reflect.DeepEqual(p.Scopes, []string{"public_repo", "write:org"}) {
+		t.Errorf("Scopes = %q", p.Scopes)
+	}
+	// Only the sections asked for, in sectionNeeds order.
+	want := []model.SectionAccess{
+		{Section: "contributions", Needs: []string{"repo"}, Missing: []string{"repo"}, Verified: true, Effect: "private contributions are counted but not attributed to repositories"},
+		{Section: "team", Needs: []string{"read:org"}, Verified: true},
+	}
+	if !reflect.DeepEqual(p.Sections, want) {
+		t.Errorf("Sections = %+v, want %+v", p.Sections, want)
+	}
+	if len(p.Warnings) != 0 {
+		t.Errorf("Warnings = %q", p.Warnings)
+	}
+
+	// Someone else's token.
+	p, err = c.Preflight(context.Background(), "hubot", []string{"contributions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Warnings) != 1 || !strings.HasPrefix(p.Warnings[0], "token belongs to octocat, not hubot") {
+		t.Errorf("Warnings = %q", p.Warnings)
+	}
+	// Team mode has no single user to compare with.
+	if p, err = c.Preflight(context.Background(), "", []string{"team"}); err != nil || len(p.Warnings) != 0 {
+		t.Errorf("team mode: %v %+v", err, p)
+	}
+}
+
+func TestPreflightFineGrained(t *testing.T) {
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{User: githubtest.User{Login: "octocat"}})
+	defer srv.Close()
+	cfg := srv.Config()
+	cfg.Token = "github_pat_11AAAA"
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Preflight(context.Background(), "OctoCat", []string{"commits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The permissions can't be read back: needs are listed, nothing is
+	// reported missing, and the effect is spelled out.
+	want := []model.SectionAccess{{Section: "commits", Needs: []string{"contents:read"}, Effect: "history of private repositories cannot be walked"}}
+	if p.TokenKind != "fine_grained_pat" || p.Scopes != nil || !reflect.DeepEqual(p.Sections, want) {
+		t.Errorf("preflight = %+v", p)
+	}
+	// Logins compare case-insensitively.
+	if len(p.Warnings) != 0 {
+		t.Errorf("Warnings = %q", p.Warnings)
+	}
+}
+
+func TestPreflightApp(t *testing.T) {
+	key, keyPEM := testKey(t)
+	srv := newAppServer(t, &key.PublicKey, 42)
+	c, err := New(config.Config{AppID: 1234, AppPrivateKey: keyPEM, APIBase: srv.URL + "/api/v3", GraphQLEnd: srv.URL + "/api/graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Preflight(context.Background(), "octocat", []string{"pull_requests", "commits", "issues"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Method != AuthApp || p.TokenKind != "app_installation" || p.Viewer != "" || p.Permissions["pull_requests"] != "read" {
+		t.Errorf("preflight = %+v", p)
+	}
+	want := []model.SectionAccess{
+		{Section: "pull_requests", Needs: []string{"pull_requests:read"}, Verified: true},
+		// Write access includes read.
+		{Section: "issues", Needs: []string{"issues:read"}, Verified: true},
+		{Section: "commits", Needs: []string{"contents:read"}, Missing: []string{"contents:read"}, Verified: true, Effect: "history of private repositories cannot be walked"},
+	}
+	got := []model.SectionAccess{section(p, "pull_requests"), section(p, "issues"), section(p, "commits")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sections = %+v, want %+v", got, want)
+	}
+	if len(p.Warnings) != 1 || !strings.Contains(p.Warnings[0], "only see repositories the app is installed on") {
+		t.Errorf("Warnings = %q", p.Warnings)
+	}
+}
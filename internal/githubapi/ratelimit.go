@@ -0,0 +1,306 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// rateLimitTransport is the RoundTripper shared by the GraphQL and REST clients.
+// It reads GitHub's rate-limit headers, waits for the reset when a resource's
+// budget is exhausted, and retries secondary-rate-limit and 5xx responses with
+// backoff. It also keeps per-resource usage so a run can report what it spent.
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	timeout    time.Duration // per attempt, so waiting for a reset does not count against it
+	maxRetries int
+
+	mu    sync.Mutex
+	usage map[string]*rateUsage
+}
+
+type rateUsage struct {
+	model.APIUsage
+	window   time.Time // reset time of the window lastUsed belongs to
+	lastUsed int
+}
+
+func newRateLimitTransport(base http.RoundTripper, timeout time.Duration) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{
+		base:       base,
+		timeout:    timeout,
+		maxRetries: 6,
+		usage:      map[string]*rateUsage{},
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resource := guessResource(req)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForBudget(ctx, resource); err != nil {
+			return nil, err
+		}
+
+		r, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, t.timeout)
+		resp, err := t.base.RoundTrip(r.WithContext(attemptCtx))
+		if err != nil {
+			cancel()
+			if ctx.Err() != nil || attempt >= t.maxRetries || !r.canRetry() {
+				return nil, err
+			}
+			t.noteRetry(resource, 0)
+			if err := sleepCtx(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+		if h := resp.Header.Get("X-RateLimit-Resource"); h != "" {
+			resource = h
+		}
+		t.observe(resource, resp.Header)
+
+		wait, retry := retryDelay(resource, resp, attempt)
+		if !retry || attempt >= t.maxRetries || !r.canRetry() {
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if wait > time.Minute {
+			log.Printf("rate limit: %s budget exhausted, waiting %s for reset", resource, wait.Round(time.Second))
+		}
+		t.noteRetry(resource, wait)
+		if err := sleepCtx(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForBudget blocks until the reset time when an earlier response told us
+// the resource has no requests left in the current window.
+func (t *rateLimitTransport) waitForBudget(ctx context.Context, resource string) error {
+	t.mu.Lock()
+	u, ok := t.usage[resource]
+	var wait time.Duration
+	if ok && u.Remaining == 0 && !u.Reset.IsZero() {
+		wait = time.Until(u.Reset) + time.Second
+	}
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	log.Printf("rate limit: %s budget exhausted, waiting %s for reset", resource, wait.Round(time.Second))
+	t.noteRetry(resource, wait)
+	return sleepCtx(ctx, wait)
+}
+
+func (t *rateLimitTransport) observe(resource string, h http.Header) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u, ok := t.usage[resource]
+	if !ok {
+		u = &rateUsage{APIUsage: model.APIUsage{Resource: resource, Remaining: -1}}
+		t.usage[resource] = u
+	}
+	u.Requests++
+
+	limit, okLimit := headerInt(h, "X-RateLimit-Limit")
+	remaining, okRemaining := headerInt(h, "X-RateLimit-Remaining")
+	used, okUsed := headerInt(h, "X-RateLimit-Used")
+	reset, okReset := headerInt(h, "X-RateLimit-Reset")
+	if !okLimit || !okRemaining {
+		return
+	}
+	u.Limit = limit
+	u.Remaining = remaining
+	if okReset {
+		u.Reset = time.Unix(int64(reset), 0)
+	}
+	if !okUsed {
+		used = limit - remaining
+	}
+
+	// X-RateLimit-Used counts the whole window, including requests made by
+	// other tools with the same token. Only the growth we observe is ours; the
+	// first request in each window is counted as costing one point.
+	switch {
+	case u.window.IsZero() || !u.window.Equal(u.Reset):
+		u.Used++
+	case used > u.lastUsed:
+		u.Used += used - u.lastUsed
+	}
+	u.window = u.Reset
+	u.lastUsed = used
+}
+
+func (t *rateLimitTransport) noteRetry(resource string, waited time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, ok := t.usage[resource]
+	if !ok {
+		u = &rateUsage{APIUsage: model.APIUsage{Resource: resource, Remaining: -1}}
+		t.usage[resource] = u
+	}
+	u.Retries++
+	u.WaitedSeconds += waited.Seconds()
+}
+
+func (t *rateLimitTransport) snapshot() []model.APIUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]model.APIUsage, 0, len(t.usage))
+	for _, u := range t.usage {
+		out = append(out, u.APIUsage)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Resource < out[j].Resource })
+	return out
+}
+
+// retryDelay decides whether a response is worth retrying and for how long to
+// wait first. It covers primary limits (REST 403/429 and GraphQL RATE_LIMITED
+// errors with no remaining budget), secondary limits and transient 5xx errors.
+func retryDelay(resource string, resp *http.Response, attempt int) (time.Duration, bool) {
+	remaining, okRemaining := headerInt(resp.Header, "X-RateLimit-Remaining")
+	exhausted := okRemaining && remaining == 0
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if s, ok := headerInt(resp.Header, "Retry-After"); ok {
+			return time.Duration(s) * time.Second, true
+		}
+		if exhausted {
+			return untilReset(resp.Header), true
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || bodyContains(resp, "secondary rate limit") {
+			// GitHub asks for at least a minute when it gives no Retry-After.
+			return time.Minute + backoff(attempt), true
+		}
+		return 0, false
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return backoff(attempt), true
+	case resource == "graphql" && exhausted && bodyContains(resp, "RATE_LIMITED"):
+		return untilReset(resp.Header), true
+	}
+	return 0, false
+}
+
+func untilReset(h http.Header) time.Duration {
+	reset, ok := headerInt(h, "X-RateLimit-Reset")
+	if !ok {
+		return time.Minute
+	}
+	d := time.Until(time.Unix(int64(reset), 0)) + time.Second
+	if d < time.Second {
+		d = time.Second
+	}
+	return d
+}
+
+// bodyContains peeks at the response body and puts it back for the caller.
+func bodyContains(resp *http.Response, needle string) bool {
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return err == nil && strings.Contains(strings.ToLower(string(b)), strings.ToLower(needle))
+}
+
+func backoff(attempt int) time.Duration {
+	d := time.Second << uint(attempt)
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func guessResource(req *http.Request) string {
+	p := req.URL.Path
+	switch {
+	case strings.HasSuffix(p, "/graphql"):
+		return "graphql"
+	case strings.Contains(p, "/search/"):
+		return "search"
+	}
+	return "core"
+}
+
+func headerInt(h http.Header, key string) (int, bool) {
+	v := h.Get(key)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type retryableRequest struct {
+	*http.Request
+}
+
+func (r retryableRequest) canRetry() bool {
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
+
+// rewind returns the request to send for the given attempt, restoring the
+// body from GetBody on retries. RoundTrippers must not modify the original.
+func rewind(req *http.Request, attempt int) (retryableRequest, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return retryableRequest{req}, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return retryableRequest{}, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return retryableRequest{r}, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,247 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+func response(status int, body string, headers ...string) *http.Response {
+	h := http.Header{}
+	for i := 0; i+1 < len(headers); i += 2 {
+		h.Set(headers[i], headers[i+1])
+	}
+	return &http.Response{StatusCode: status, Header: h, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestRetryDelay(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+	tests := []struct {
+		name     string
+		resource string
+		resp     *http.Response
+		retry    bool
+		min, max time.Duration
+	}{
+		{"403 with Retry-After", "core", response(403, "", "Retry-After", "7"), true, 7 * time.Second, 7 * time.Second},
+		{"429 with Retry-After", "search", response(429, "", "Retry-After", "3"), true, 3 * time.Second, 3 * time.Second},
+		{"primary limit exhausted", "core", response(403, "", "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset), true, 9 * time.Minute, 11 * time.Minute},
+		{"429 without Retry-After", "core", response(429, ""), true, time.Minute, time.Minute + 2*time.Second},
+		{"secondary limit", "core", response(403, `{"message":"You have exceeded a secondary rate limit."}`), true, time.Minute, time.Minute + 2*time.Second},
+		{"plain 403", "core", response(403, `{"message":"Resource not accessible by integration"}`), false, 0, 0},
+		{"502", "graphql", response(502, ""), true, time.Second, 1500 * time.Millisecond},
+		{"501 is not transient", "core", response(501, ""), false, 0, 0},
+		{"GraphQL RATE_LIMITED", "graphql", response(200, `{"errors":[{"type":"RATE_LIMITED"}]}`, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset), true, 9 * time.Minute, 11 * time.Minute},
+		{"GraphQL error with budget left", "graphql", response(200, `{"errors":[{"type":"RATE_LIMITED"}]}`, "X-RateLimit-Remaining", "12"), false, 0, 0},
+		{"200", "core", response(200, "{}"), false, 0, 0},
+	}
+	for _, tt := range tests {
+		wait, retry := retryDelay(tt.resource, tt.resp, 0)
+		if retry != tt.retry || wait < tt.min || wait > tt.max {
+			t.Errorf("%s: retryDelay = %s, %v; want %v in [%s, %s]", tt.name, wait, retry, tt.retry, tt.min, tt.max)
+		}
+	}
+
+	// The body is still readable after being peeked at.
+	resp := response(403, "secondary rate limit")
+	retryDelay("core", resp, 0)
+	if b, _ := io.ReadAll(resp.Body); string(b) != "secondary rate limit" {
+		t.Errorf("body after retryDelay = %q", b)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 30 * time.Second} {
+		if attempt == 3 {
+			attempt = 10
+		}
+		if d := backoff(attempt); d < want || d > want+want/2 {
+			t.Errorf("backoff(%d) = %s, want %s plus up to half", attempt, d, want)
+		}
+	}
+}
+
+func TestObserveUsage(t *testing.T) {
+	tr := newRateLimitTransport(nil, time.Second)
+	window := time.Now().Add(time.Hour).Unix()
+	headers := func(remaining, used int, reset int64) http.Header {
+		h := http.Header{}
+		h.Set("X-RateLimit-Limit", "5000")
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		h.Set("X-RateLimit-Used", strconv.Itoa(used))
+		h.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		return h
+	}
+	// 40 points were already spent by something else: only ours count.
+	tr.observe("core", headers(4960, 40, window))
+	tr.observe("core", headers(4959, 41, window))
+	// Another tool spent 5 more in between; they show up as ours.
+	tr.observe("core", headers(4953, 47, window))
+	// A new window costs one point, whatever Used says.
+	tr.observe("core", headers(4999, 1, window+3600))
+	// No rate-limit headers: counted as a request only.
+	tr.observe("search", http.Header{})
+	tr.noteRetry("search", 2*time.Second)
+
+	want := []model.APIUsage{
+		{Resource: "core", Requests: 4, Used: 1 + 1 + 6 + 1, Remaining: 4999, Limit: 5000, Reset: time.Unix(window+3600, 0)},
+		{Resource: "search", Requests: 1, Remaining: -1, Retries: 1, WaitedSeconds: 2},
+	}
+	got := tr.snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("snapshot = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("snapshot[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// flakyServer answers with the given responses in turn, then 200s, and
+// records the request bodies it saw.
+func flakyServer(t *testing.T, responses ...func(http.ResponseWriter)) (*httptest.Server, *atomic.Int32, *[]string) {
+	var n atomic.Int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		i := int(n.Add(1)) - 1
+		if i < len(responses) {
+			responses[i](w)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		io.WriteString(w, "{}")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &n, &bodies
+}
+
+func status(code int, headers ...string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func TestRoundTripRetries(t *testing.T) {
+	// The 5xx comes first, while its backoff is still a second.
+	srv, n, bodies := flakyServer(t,
+		status(http.StatusBadGateway),
+		status(http.StatusForbidden, "Retry-After", "0"),
+		status(http.StatusTooManyRequests, "Retry-After", "0"),
+	)
+	tr := newRateLimitTransport(nil, 5*time.Second)
+	req, _ := http.NewRequest("POST", srv.URL+"/api/graphql", strings.NewReader(`{"query":"{}"}`))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || n.Load() != 4 {
+		t.Errorf("status %d after %d requests, want 200 after 4", resp.StatusCode, n.Load())
+	}
+	// Every retry resent the whole body.
+	for i, b := range *bodies {
+		if b != `{"query":"{}"}` {
+			t.Errorf("attempt %d sent %q", i, b)
+		}
+	}
+	u := tr.snapshot()
+	if len(u) != 1 || u[0].Resource != "graphql" || u[0].Requests != 4 || u[0].Retries != 3 || u[0].WaitedSeconds < 1 || u[0].Remaining != 4000 {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+func TestRoundTripGivesUp(t *testing.T) {
+	// A body that can't be rewound is sent once.
+	srv, n, _ := flakyServer(t, status(http.StatusForbidden, "Retry-After", "0"))
+	tr := newRateLimitTransport(nil, 5*time.Second)
+	req, _ := http.NewRequest("POST", srv.URL+"/api/graphql", io.NopCloser(strings.NewReader("{}")))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || n.Load() != 1 {
+		t.Errorf("status %d after %d requests, want 403 after 1", resp.StatusCode, n.Load())
+	}
+
+	// Out of retries: the last response is returned.
+	srv, n, _ = flakyServer(t, status(http.StatusForbidden, "Retry-After", "0"), status(http.StatusForbidden, "Retry-After", "0"), status(http.StatusForbidden, "Retry-After", "0"))
+	tr = newRateLimitTransport(nil, 5*time.Second)
+	tr.maxRetries = 2
+	req, _ = http.NewRequest("GET", srv.URL+"/api/v3/user", nil)
+	resp, err = tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || n.Load() != 3 {
+		t.Errorf("status %d after %d requests, want 403 after 3", resp.StatusCode, n.Load())
+	}
+}
+
+func TestWaitForBudgetCancelled(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	srv, n, _ := flakyServer(t, status(http.StatusOK, "X-RateLimit-Limit", "30", "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset))
+	tr := newRateLimitTransport(nil, 5*time.Second)
+
+	req, _ := http.NewRequest("GET", srv.URL+"/api/v3/search/issues", nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// The budget is spent until the reset an hour away: the next request
+	// waits, and cancelling the context ends the wait.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = tr.RoundTrip(req.WithContext(ctx))
+	if !errors.Is(err, context.Canceled) || time.Since(start) > 5*time.Second {
+		t.Errorf("err = %v after %s, want context.Canceled", err, time.Since(start))
+	}
+	if n.Load() != 1 {
+		t.Errorf("%d requests, want 1", n.Load())
+	}
+	if u := tr.snapshot(); len(u) != 1 || u[0].Resource != "search" || u[0].Retries != 1 || u[0].Remaining != 0 {
+		t.Errorf("usage = %+v", u)
+	}
+
+	// Other resources are not held up.
+	req, _ = http.NewRequest("GET", srv.URL+"/api/v3/user", nil)
+	if resp, err := tr.RoundTrip(req); err != nil {
+		t.Errorf("core request: %v", err)
+	} else {
+		resp.Body.Close()
+	}
+}
+
+func TestSleepCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sleepCtx(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled sleep = %v", err)
+	}
+	if err := sleepCtx(ctx, 0); err != nil {
+		t.Errorf("zero sleep = %v", err)
+	}
+	if err := sleepCtx(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("short sleep = %v", err)
+	}
+}
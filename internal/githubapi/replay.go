@@ -0,0 +1,168 @@
+package githubapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recording is one HTTP exchange saved by recordTransport. Requests are
+// identified by method, path and body only, so a recording made against
+// github.com replays against any host, and vice versa for Enterprise Server
+// paths (see replayPath). Request headers, credentials included, are never
+// saved.
+type recording struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Request json.RawMessage `json:"request,omitempty"` // GraphQL payload
+	Status  int             `json:"status"`
+	Header  http.Header     `json:"header"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// replayPath drops the host and the Enterprise Server API prefixes, leaving
+// the path and query as github.com would see them.
+func replayPath(req *http.Request) string {
+	p := req.URL.Path
+	switch {
+	case p == "/api/graphql":
+		p = "/graphql"
+	case strings.HasPrefix(p, "/api/v3/"):
+		p = strings.TrimPrefix(p, "/api/v3")
+	}
+	if req.URL.RawQuery != "" {
+		p += "?" + req.URL.RawQuery
+	}
+	return p
+}
+
+// requestBody reads req's body without consuming it.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return nil, nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func replayFile(dir string, req *http.Request, body []byte) (string, string) {
+	path := replayPath(req)
+	return filepath.Join(dir, cacheKey(req.Method, path, string(body))+".json"), path
+}
+
+// recordTransport saves every exchange that passes through it to dir.
+type recordTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+func newRecordTransport(dir string, next http.RoundTripper) (*recordTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("record dir: %w", err)
+	}
+	return &recordTransport{dir: dir, next: next}, nil
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	payload, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	file, path := replayFile(t.dir, req, payload)
+	rec := recording{
+		Method: req.Method,
+		Path:   path,
+		Status: resp.StatusCode,
+		Header: cacheableHeader(resp.Header),
+		Body:   jsonOrString(body),
+	}
+	if len(payload) > 0 {
+		rec.Request = jsonOrString(payload)
+	}
+	if v := resp.Header.Values("X-OAuth-Scopes"); len(v) > 0 {
+		rec.Header["X-OAuth-Scopes"] = v
+	}
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, append(b, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("record %s %s: %w", req.Method, path, err)
+	}
+	return resp, nil
+}
+
+// replayTransport answers every request from recordings in dir and never
+// touches the network. A request that was not recorded fails.
+type replayTransport struct {
+	dir string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	payload, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	file, path := replayFile(t.dir, req, payload)
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("replay: no recording of %s %s (%s)", req.Method, path, filepath.Base(file))
+	}
+	var rec recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("replay %s: %w", filepath.Base(file), err)
+	}
+	body := []byte(rec.Body)
+	var s string
+	if json.Unmarshal(rec.Body, &s) == nil {
+		body = []byte(s)
+	}
+	h := rec.Header.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// jsonOrString keeps JSON bodies readable in recordings and stores anything
+// else as a JSON string.
+func jsonOrString(b []byte) json.RawMessage {
+	if json.Valid(b) {
+		var buf bytes.Buffer
+		if json.Compact(&buf, b) == nil {
+			return buf.Bytes()
+		}
+	}
+	s, _ := json.Marshal(string(b))
+	return s
+}
@@ -0,0 +1,117 @@
+package githubapi_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+type collected struct {
+	Contributions *model.ContributionsCollection
+	PRs           []model.PRItem
+	PRSearch      model.SearchCoverage
+	Files         []model.ChangedFile
+	Repos         map[string]*model.RepoMeta
+	Growth        *model.GrowthMetrics
+	Failures      []model.Failure
+}
+
+func collect(t *testing.T, cfg config.Config) collected {
+	t.Helper()
+	ctx := context.Background()
+	client, err := githubapi.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	var c collected
+	if c.Contributions, err = client.FetchContributionsCollection(ctx, "octocat", from, to); err != nil {
+		t.Fatal(err)
+	}
+	if c.PRs, c.PRSearch, err = client.SearchPullRequests(ctx, "octocat", from, to, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if c.Files, err = client.FetchPRFiles(ctx, c.PRs); err != nil {
+		t.Fatal(err)
+	}
+	c.Repos, err = client.RepoMetadata(ctx, []string{"octocat/hello-world", "octo-org/platform", "octocat/gone"})
+	c.Failures = append(c.Failures, githubapi.Failures("repositories", "repo_metadata", err)...)
+	if c.Growth, err = client.CalcStarsForksGained(ctx, "octocat", from, to, githubapi.GrowthSelection{OrgMaintained: true}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRecordReplay(t *testing.T) {
+	srv, err := githubtest.NewServer(githubtest.FixtureDir("octocat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfg := srv.Config()
+	cfg.RecordDir = dir
+	live := collect(t, cfg)
+	if len(live.PRs) == 0 || len(live.Files) == 0 || live.Growth.TotalStarsGained == 0 {
+		t.Fatalf("fake GitHub returned too little to compare: %+v", live)
+	}
+	if len(live.Failures) != 1 || live.Failures[0].Repo != "octocat/gone" {
+		t.Fatalf("failures = %+v, want octocat/gone not found", live.Failures)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no recordings in %s (%v)", dir, err)
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(b, []byte(cfg.Token)) {
+			t.Fatalf("%s contains the token", filepath.Base(f))
+		}
+	}
+
+	// Replay at github.com endpoints: recordings are host independent.
+	served := srv.Requests()
+	replayed := collect(t, config.Config{
+		APIBase:    "https://api.github.com",
+		GraphQLEnd: "https://api.github.com/graphql",
+		ReplayDir:  dir,
+	})
+	if srv.Requests() != served {
+		t.Errorf("replay made %d requests to the server", srv.Requests()-served)
+	}
+	if !reflect.DeepEqual(live, replayed) {
+		t.Errorf("replayed results differ from the recorded run\nlive:     %+v\nreplayed: %+v", live, replayed)
+	}
+}
+
+func TestReplayMissing(t *testing.T) {
+	client, err := githubapi.New(config.Config{
+		APIBase:    "https://api.github.com",
+		GraphQLEnd: "https://api.github.com/graphql",
+		ReplayDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.FetchUser(context.Background(), "octocat")
+	if err == nil || !strings.Contains(err.Error(), "no recording of POST /graphql") {
+		t.Fatalf("err = %v, want a missing recording", err)
+	}
+}
@@ -0,0 +1,203 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// metaBatch is how many repositories share one aliased metadata query.
+const metaBatch = 50
+
+// repoStore memoizes repository metadata by lower-cased "owner/name".
+// Concurrent callers asking for the same repository share one fetch; a fetch
+// that fails is forgotten so the next caller retries.
+type repoStore struct {
+	mu      sync.Mutex
+	entries map[string]*repoEntry
+}
+
+type repoEntry struct {
+	done chan struct{}
+	meta *model.RepoMeta
+	err  error
+}
+
+// RepoMetadata returns the metadata of every repository in repos
+// ("owner/name"), keyed as given. Repositories not fetched before are fetched
+// in aliased batches. A repository that fails is left out; every failure is
+// returned as a *RequestError, joined.
+func (c *Client) RepoMetadata(ctx context.Context, repos []string) (map[string]*model.RepoMeta, error) {
+	s := &c.repos
+	wait := map[string]*repoEntry{}
+	var fetch []string
+	s.mu.Lock()
+	if s.entries == nil {
+		s.entries = map[string]*repoEntry{}
+	}
+	for _, full := range repos {
+		key := strings.ToLower(full)
+		if _, ok := wait[full]; ok {
+			continue
+		}
+		e, ok := s.entries[key]
+		if !ok {
+			e = &repoEntry{done: make(chan struct{})}
+			s.entries[key] = e
+			fetch = append(fetch, full)
+		}
+		wait[full] = e
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
+	for start := 0; start < len(fetch); start += metaBatch {
+		batch := fetch[start:min(start+metaBatch, len(fetch))]
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			metas, err := c.fetchRepoMeta(ctx, batch)
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for i, full := range batch {
+				e := s.entries[strings.ToLower(full)]
+				switch {
+				case err != nil:
+					e.err = err
+				case metas[i] == nil:
+					e.err = fmt.Errorf("repository not found")
+				default:
+					e.meta = metas[i]
+				}
+				if err != nil {
+					// Transient (rate limit, cancellation...): let a later
+					// caller try again. Not found is kept.
+					delete(s.entries, strings.ToLower(full))
+				}
+				close(e.done)
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	out := make(map[string]*model.RepoMeta, len(wait))
+	var errs []error
+	for full, e := range wait {
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			errs = append(errs, &RequestError{Op: "repo_metadata", Repo: full, Err: context.Cause(ctx)})
+			continue
+		}
+		if e.err != nil {
+			errs = append(errs, &RequestError{Op: "repo_metadata", Repo: full, Err: e.err})
+			continue
+		}
+		out[full] = e.meta
+	}
+	return out, errors.Join(errs...)
+}
+
+// fetchRepoMeta fetches the metadata of every repository in batch with one
+// aliased query. A nil entry means the repository was not returned.
+func (c *Client) fetchRepoMeta(ctx context.Context, batch []string) ([]*model.RepoMeta, error) {
+	var q strings.Builder
+	q.WriteString("query {\n")
+	for i, full := range batch {
+		owner, name, ok := strings.Cut(full, "/")
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&q, `  r%d: repository(owner:%s, name:%s) {
+    nameWithOwner
+    description
+    primaryLanguage { name }
+    repositoryTopics(first:20) { nodes { topic { name } } }
+    stargazerCount
+    forkCount
+    owner { __typename login }
+    isFork
+    isArchived
+    isPrivate
+    languages(first:100, orderBy:{field:SIZE, direction:DESC}) {
+      edges { size node { name } }
+    }
+  }
+`, i, strconv.Quote(owner), strconv.Quote(name))
+	}
+	q.WriteString("}")
+
+	var out map[string]*struct {
+		NameWithOwner   string `json:"nameWithOwner"`
+		Description     string `json:"description"`
+		PrimaryLanguage *struct {
+			Name string `json:"name"`
+		} `json:"primaryLanguage"`
+		RepositoryTopics struct {
+			Nodes []struct {
+				Topic struct {
+					Name string `json:"name"`
+				} `json:"topic"`
+			} `json:"nodes"`
+		} `json:"repositoryTopics"`
+		StargazerCount int `json:"stargazerCount"`
+		ForkCount      int `json:"forkCount"`
+		Owner          struct {
+			Typename string `json:"__typename"`
+			Login    string `json:"login"`
+		} `json:"owner"`
+		IsFork     bool `json:"isFork"`
+		IsArchived bool `json:"isArchived"`
+		IsPrivate  bool `json:"isPrivate"`
+		Languages  struct {
+			Edges []struct {
+				Size int64 `json:"size"`
+				Node struct {
+					Name string `json:"name"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"languages"`
+	}
+	if err := c.doGraphQLAllowMissing(ctx, q.String(), nil, &out); err != nil {
+		return nil, err
+	}
+	metas := make([]*model.RepoMeta, len(batch))
+	for i := range batch {
+		r := out["r"+strconv.Itoa(i)]
+		if r == nil {
+			continue
+		}
+		m := &model.RepoMeta{
+			Repo:        r.NameWithOwner,
+			Description: r.Description,
+			Stars:       r.StargazerCount,
+			Forks:       r.ForkCount,
+			Owner:       r.Owner.Login,
+			OwnerType:   r.Owner.Typename,
+			IsFork:      r.IsFork,
+			IsArchived:  r.IsArchived,
+			IsPrivate:   r.IsPrivate,
+			Languages:   model.LanguageBytes{},
+		}
+		if r.PrimaryLanguage != nil {
+			m.PrimaryLanguage = r.PrimaryLanguage.Name
+		}
+		for _, t := range r.RepositoryTopics.Nodes {
+			m.Topics = append(m.Topics, t.Topic.Name)
+		}
+		for _, e := range r.Languages.Edges {
+			m.Languages[e.Node.Name] += e.Size
+		}
+		metas[i] = m
+	}
+	return metas, nil
+}
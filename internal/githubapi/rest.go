@@ -1,102 +1,80 @@
-\
 package githubapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"net/url"
 	"sort"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/dennislee928/github-recap-2025/internal/model"
 	"github.com/google/go-github/v62/github"
-	"golang.org/x/oauth2"
 )
 
-func (c *Client) rest() *github.Client {
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: c.cfg.Token})
-	tc := oauth2.NewClient(context.Background(), ts)
-	// allow overriding API base? go-github supports Enterprise URLs, but keep default for now.
-	return github.NewClient(tc)
+// rest returns the shared go-github client, built on first use.
+func (c *Client) rest() (*github.Client, error) {
+	c.restOnce.Do(func() {
+		c.restClient, c.restErr = c.newREST()
+	})
+	return c.restClient, c.restErr
 }
 
-func (c *Client) FetchLanguagesForContributedRepos(cc *model.ContributionsCollection) (model.LanguageBytes, error) {
-	repos := map[string]bool{}
-	for k := range cc.ByRepoCommits { repos[k] = true }
-	for k := range cc.ByRepoPRs { repos[k] = true }
-	for k := range cc.ByRepoIssues { repos[k] = true }
-	for k := range cc.ByRepoReviews { repos[k] = true }
-
-	client := c.rest()
-
-	type job struct{ owner, repo string }
-	jobs := make([]job, 0, len(repos))
-	for full := range repos {
-		parts := strings.Split(full, "/")
-		if len(parts) != 2 { continue }
-		jobs = append(jobs, job{owner: parts[0], repo: parts[1]})
+func (c *Client) newREST() (*github.Client, error) {
+	client := github.NewClient(c.http)
+	if !c.cfg.IsEnterprise() {
+		return client, nil
 	}
-
-	agg := model.LanguageBytes{}
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	// modest concurrency to avoid rate-limit bursts
-	sem := make(chan struct{}, 6)
-
-	var firstErr error
-	for _, j := range jobs {
-		wg.Add(1)
-		go func(j job) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func(){ <-sem }()
-
-			langs, _, err := client.Repositories.ListLanguages(context.Background(), j.owner, j.repo)
-			if err != nil {
-				mu.Lock()
-				if firstErr == nil {
-					firstErr = fmt.Errorf("list languages %s/%s: %w", j.owner, j.repo, err)
-				}
-				mu.Unlock()
-				return
-			}
-			mu.Lock()
-			for lang, bytes := range langs {
-				agg[lang] += int64(bytes)
-			}
-			mu.Unlock()
-		}(j)
+	upload := c.cfg.UploadBase
+	if upload == "" {
+		// WithEnterpriseURLs appends /api/uploads/ to a bare host.
+		u, err := url.Parse(c.cfg.APIBase)
+		if err != nil {
+			return nil, fmt.Errorf("GITHUB_API_BASE: %w", err)
+		}
+		upload = u.Scheme + "://" + u.Host
 	}
-	wg.Wait()
-	return agg, firstErr
+	return client.WithEnterpriseURLs(c.cfg.APIBase, upload)
 }
 
-func (c *Client) CalcStarsForksGainedOwnedRepos(login string, from, to time.Time) (*model.GrowthMetrics, error) {
-	client := c.rest()
+// restCtx lets go-github wait for the reset instead of failing fast when its
+// own bookkeeping says the budget is gone; the shared transport handles the rest.
+func restCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+}
 
-	// list owned repos
-	var allRepos []*github.Repository
-	opt := &github.RepositoryListByUserOptions{
-		Type: "owner",
-		ListOptions: github.ListOptions{PerPage: 100},
-	}
-	for {
-		repos, resp, err := client.Repositories.ListByUser(context.Background(), login, opt)
-		if err != nil { return nil, err }
-		allRepos = append(allRepos, repos...)
-		if resp.NextPage == 0 { break }
-		opt.Page = resp.NextPage
+// CalcStarsForksGained counts stars and forks gained in the window by
+// login's own repositories and those sel adds. Forks of upstream projects are
+// left out unless sel keeps them. Repositories whose stargazers or forks
+// cannot be listed keep their current totals; every failure is returned as a
+// *RequestError, joined.
+func (c *Client) CalcStarsForksGained(ctx context.Context, login string, from, to time.Time, sel GrowthSelection) (*model.GrowthMetrics, error) {
+	client, err := c.rest()
+	if err != nil {
+		return nil, err
 	}
 
+	allRepos, forks, listErr := c.growthRepos(ctx, client, login, sel)
+
 	metrics := &model.GrowthMetrics{
 		Year: from.Year(),
+		From: from,
+		To: to,
 		Repos: make([]model.GrowthRepo, 0, len(allRepos)),
-		Note: "Stars gained derived from stargazer timestamps when available; forks gained derived from fork creation timestamps. Private repo stars/forks are usually not meaningful. Use --skip-growth to disable.",
+		ExcludedForks: forks,
+		Note: "Stars gained derived from stargazer timestamps (GraphQL, newest first); forks gained derived from fork creation timestamps. Private repo stars/forks are usually not meaningful. Use --skip-growth to disable.",
 	}
 
+	// Stars come from GraphQL, newest first, for every repo that has any.
+	var starred []starRepo
+	for _, g := range allRepos {
+		if g.stars > 0 {
+			starred = append(starred, starRepo{owner: g.owner, name: g.name})
+		}
+	}
+	stars, starErr := c.starsInRange(ctx, client, starred, from, to)
+
 	type result struct{
 		gr model.GrowthRepo
 		err error
@@ -105,41 +83,46 @@ func (c *Client) CalcStarsForksGainedOwnedRepos(login string, from, to time.Time
 	outCh := make(chan result, len(allRepos))
 	var wg sync.WaitGroup
 
-	for _, r := range allRepos {
-		r := r
-		if r.GetName() == "" || r.GetOwner() == nil { continue }
+	for _, g := range allRepos {
+		g := g
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func(){ <-sem }()
-			owner := r.GetOwner().GetLogin()
-			repo := r.GetName()
-			full := fmt.Sprintf("%s/%s", owner, repo)
+			owner, repo := g.owner, g.name
+			full := g.String()
 
 			gr := model.GrowthRepo{
 				Repo: full,
-				StarsNow: r.GetStargazersCount(),
-				ForksNow: r.GetForksCount(),
-				IsPrivate: r.GetPrivate(),
+				StarsNow: g.stars,
+				ForksNow: g.forks,
+				IsPrivate: g.private,
+				Owner: owner,
+				OwnerType: g.ownerType,
+				Source: g.source,
+				IsFork: g.fork,
 			}
-
-			// stars gained in year: /stargazers with starred_at
-			starsGained, err := c.countStarsInRange(client, owner, repo, from, to)
-			if err != nil {
-				// keep going, but report error
-				outCh <- result{gr: gr, err: fmt.Errorf("stars %s: %w", full, err)}
+			if h := stars[full]; h != nil {
+				gr.StarTimes = h.inRange
+				gr.StarsGainedInYear = len(h.inRange)
+				gr.StarsAfter = h.after
+				gr.FirstStarAt = h.first
+			}
+			if gr.ForksNow == 0 {
+				outCh <- result{gr: gr}
 				return
 			}
-			gr.StarsGainedInYear = starsGained
 
 			// forks gained in year: /forks list includes CreatedAt
-			forksGained, err := c.countForksInRange(client, owner, repo, from, to)
+			forks, after, err := c.forksInRange(ctx, client, owner, repo, from, to)
+			gr.ForkTimes = forks
+			gr.ForksGainedInYear = len(forks)
+			gr.ForksAfter = after
 			if err != nil {
-				outCh <- result{gr: gr, err: fmt.Errorf("forks %s: %w", full, err)}
+				outCh <- result{gr: gr, err: &RequestError{Op: "list_forks", Repo: full, Err: err}}
 				return
 			}
-			gr.ForksGainedInYear = forksGained
 
 			outCh <- result{gr: gr, err: nil}
 		}()
@@ -148,11 +131,11 @@ func (c *Client) CalcStarsForksGainedOwnedRepos(login string, from, to time.Time
 	wg.Wait()
 	close(outCh)
 
-	var firstErr error
+	errs := []error{listErr, starErr}
 	for r := range outCh {
 		metrics.Repos = append(metrics.Repos, r.gr)
-		if r.err != nil && firstErr == nil {
-			firstErr = r.err
+		if r.err != nil {
+			errs = append(errs, r.err)
 		}
 		metrics.TotalStarsGained += r.gr.StarsGainedInYear
 		metrics.TotalForksGained += r.gr.ForksGainedInYear
@@ -168,62 +151,67 @@ func (c *Client) CalcStarsForksGainedOwnedRepos(login string, from, to time.Time
 		return metrics.Repos[i].StarsGainedInYear > metrics.Repos[j].StarsGainedInYear
 	})
 
-	return metrics, firstErr
+	return metrics, errors.Join(errs...)
 }
 
-func (c *Client) countStarsInRange(client *github.Client, owner, repo string, from, to time.Time) (int, error) {
-	// GitHub returns starred_at only with a special accept header.
-	// go-github supports this via a custom request; easiest is to call Repositories.ListStargazers with StarListOptions.
-	// In go-github, StarListOptions returns []*github.Stargazer with StarredAt.
+// restStarsInRange is the REST fallback for starsInRange. /stargazers only
+// lists oldest first, so every page has to be read.
+func (c *Client) restStarsInRange(ctx context.Context, client *github.Client, owner, repo string, from, to time.Time) (*starHistory, error) {
+	// go-github sends the star+json Accept header, so StarredAt is set.
 	opt := &github.ListOptions{PerPage: 100}
-	count := 0
+	stars := &starHistory{}
 	for {
-		sg, resp, err := client.Activity.ListStargazers(context.Background(), owner, repo, opt)
+		sg, resp, err := client.Activity.ListStargazers(restCtx(ctx), owner, repo, opt)
 		if err != nil {
-			return count, err
+			return stars, err
 		}
 		for _, s := range sg {
 			if s.StarredAt == nil { continue }
 			t := s.StarredAt.Time
-			if !t.Before(from) && !t.After(to) {
-				count++
+			if stars.first == nil {
+				stars.first = &t
+			}
+			switch {
+			case t.After(to):
+				stars.after++
+			case !t.Before(from):
+				stars.inRange = append(stars.inRange, t)
 			}
 		}
 		if resp.NextPage == 0 { break }
 		opt.Page = resp.NextPage
 	}
-	return count, nil
+	return stars, nil
 }
 
-func (c *Client) countForksInRange(client *github.Client, owner, repo string, from, to time.Time) (int, error) {
+// forksInRange lists forks newest first and returns the creation times of
+// those created inside [from, to], and how many were created after to.
+func (c *Client) forksInRange(ctx context.Context, client *github.Client, owner, repo string, from, to time.Time) ([]time.Time, int, error) {
 	opt := &github.RepositoryListForksOptions{
 		Sort: "newest",
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
-	count := 0
+	var inRange []time.Time
+	after := 0
 	for {
-		forks, resp, err := client.Repositories.ListForks(context.Background(), owner, repo, opt)
+		forks, resp, err := client.Repositories.ListForks(restCtx(ctx), owner, repo, opt)
 		if err != nil {
-			return count, err
+			return inRange, after, err
 		}
 		for _, f := range forks {
 			t := f.GetCreatedAt().Time
-			if !t.Before(from) && !t.After(to) {
-				count++
-			}
-			// Since sorted newest, if we are past range (older than from), we can early-stop
-			if t.Before(from) {
-				// We can stop scanning this repo
-				return count, nil
+			// Sorted newest first, so the first fork older than the window ends the walk.
+			switch {
+			case t.Before(from):
+				return inRange, after, nil
+			case t.After(to):
+				after++
+			default:
+				inRange = append(inRange, t)
 			}
 		}
 		if resp.NextPage == 0 { break }
 		opt.Page = resp.NextPage
 	}
-	return count, nil
-}
-
-// NOTE: go-github's Activity.ListStargazers uses the correct Accept header internally for starred_at.
-func init() {
-	_ = http.MethodGet
+	return inRange, after, nil
 }
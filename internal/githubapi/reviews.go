@@ -0,0 +1,322 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// SearchReviewsGiven returns PRs by other people that login reviewed, with
+// their review and review-thread timelines. A PR reviewed inside the window
+// was necessarily updated after the window started, so the search runs on
+// updated:from..now and callers filter reviews by submission time.
+func (c *Client) SearchReviewsGiven(ctx context.Context, login string, from, to time.Time, maxResults int) ([]model.ReviewedPR, model.SearchCoverage, error) {
+	base := fmt.Sprintf("is:pr reviewed-by:%s -author:%s", login, login)
+	until := time.Now().In(to.Location())
+	if until.Before(to) {
+		until = to
+	}
+	return searchWindows(ctx, base, "updated", from, until, maxResults, c.searchReviewedPRs, func(p model.ReviewedPR) string { return p.URL })
+}
+
+// SearchReviewsReceived returns login's own PRs created in the window with
+// the reviews other people left on them.
+func (c *Client) SearchReviewsReceived(ctx context.Context, login string, from, to time.Time, maxResults int) ([]model.ReviewedPR, model.SearchCoverage, error) {
+	base := fmt.Sprintf("is:pr author:%s", login)
+	return searchWindows(ctx, base, "created", from, to, maxResults, c.searchReviewedPRs, func(p model.ReviewedPR) string { return p.URL })
+}
+
+// reviewActor is a review or comment author; nil for a deleted account.
+type reviewActor struct {
+	Login string `json:"login"`
+}
+
+func (a *reviewActor) login() string {
+	if a == nil {
+		return "ghost" // deleted account
+	}
+	return a.Login
+}
+
+// reviewPage is one page of a pull request's reviews.
+type reviewPage struct {
+	PageInfo searchPageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		Author      *reviewActor `json:"author"`
+		State       string       `json:"state"`
+		SubmittedAt *time.Time   `json:"submittedAt"`
+		Comments    struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"comments"`
+	} `json:"nodes"`
+}
+
+func (p *reviewPage) addTo(pr *model.ReviewedPR) {
+	for _, r := range p.Nodes {
+		if r.SubmittedAt == nil {
+			continue // pending review
+		}
+		pr.Reviews = append(pr.Reviews, model.PRReview{
+			Author:      r.Author.login(),
+			State:       r.State,
+			SubmittedAt: *r.SubmittedAt,
+			Comments:    r.Comments.TotalCount,
+		})
+	}
+}
+
+// threadPage is one page of a pull request's review threads, each with its
+// first page of comments.
+type threadPage struct {
+	PageInfo searchPageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		ID       string      `json:"id"`
+		Comments commentPage `json:"comments"`
+	} `json:"nodes"`
+}
+
+// commentPage is one page of a review thread's comments.
+type commentPage struct {
+	PageInfo searchPageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		Author    *reviewActor `json:"author"`
+		CreatedAt time.Time    `json:"createdAt"`
+	} `json:"nodes"`
+}
+
+func (p *commentPage) addTo(pr *model.ReviewedPR) {
+	for _, cm := range p.Nodes {
+		pr.ThreadComments = append(pr.ThreadComments, model.PRComment{
+			Author:    cm.Author.login(),
+			CreatedAt: cm.CreatedAt,
+		})
+	}
+}
+
+// searchReviewedPRs is one search page of pull requests with their reviews
+// and review-thread comments. Connections longer than the first page are
+// continued per pull request; one that cannot be read to the end marks the
+// pull request Truncated instead of failing the search.
+func (c *Client) searchReviewedPRs(ctx context.Context, query string, after *string) ([]model.ReviewedPR, int, *string, error) {
+	// Page sizes are kept small so the nested connections stay well under
+	// GitHub's 500,000-node limit per query.
+	const reviewedPRsQuery = `
+query($q:String!, $after:String) {
+  search(query:$q, type:ISSUE, first:25, after:$after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        number
+        url
+        createdAt
+        author { login }
+        repository { nameWithOwner }
+        reviews(first:50) {
+          pageInfo { hasNextPage endCursor }
+          nodes {
+            author { login }
+            state
+            submittedAt
+            comments { totalCount }
+          }
+        }
+        reviewThreads(first:50) {
+          pageInfo { hasNextPage endCursor }
+          nodes {
+            id
+            comments(first:20) {
+              pageInfo { hasNextPage endCursor }
+              nodes {
+                author { login }
+                createdAt
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	q := reviewedPRsQuery
+	if !c.hasField(ctx, "PullRequest", "reviewThreads") {
+		q = dropField(q, "reviewThreads")
+	}
+
+	var out struct {
+		Search struct {
+			IssueCount int            `json:"issueCount"`
+			PageInfo   searchPageInfo `json:"pageInfo"`
+			Nodes      []struct {
+				Number     int          `json:"number"`
+				URL        string       `json:"url"`
+				CreatedAt  time.Time    `json:"createdAt"`
+				Author     *reviewActor `json:"author"`
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"repository"`
+				Reviews       reviewPage `json:"reviews"`
+				ReviewThreads threadPage `json:"reviewThreads"`
+			} `json:"nodes"`
+		} `json:"search"`
+	}
+	if err := c.doGraphQL(ctx, q, map[string]any{"q": query, "after": after}, &out); err != nil {
+		return nil, 0, nil, err
+	}
+
+	var page []model.ReviewedPR
+	for _, n := range out.Search.Nodes {
+		pr := model.ReviewedPR{
+			Repo:      n.Repository.NameWithOwner,
+			Number:    n.Number,
+			URL:       n.URL,
+			Author:    n.Author.login(),
+			CreatedAt: n.CreatedAt,
+		}
+		n.Reviews.addTo(&pr)
+		err := c.moreReviews(ctx, &pr, n.Reviews.PageInfo.next())
+		err = errors.Join(err, c.addThreads(ctx, &pr, &n.ReviewThreads))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, 0, nil, err
+			}
+			pr.Truncated = true
+		}
+		page = append(page, pr)
+	}
+	return page, out.Search.IssueCount, out.Search.PageInfo.next(), nil
+}
+
+// moreReviews reads pr's reviews after cursor.
+func (c *Client) moreReviews(ctx context.Context, pr *model.ReviewedPR, cursor *string) error {
+	const q = `
+query($owner:String!, $name:String!, $number:Int!, $after:String) {
+  repository(owner:$owner, name:$name) {
+    pullRequest(number:$number) {
+      reviews(first:100, after:$after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          author { login }
+          state
+          submittedAt
+          comments { totalCount }
+        }
+      }
+    }
+  }
+}`
+	for cursor != nil {
+		var out struct {
+			Repository *struct {
+				PullRequest *struct {
+					Reviews reviewPage `json:"reviews"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := c.doGraphQL(ctx, q, pullRequestVars(pr, cursor), &out); err != nil {
+			return err
+		}
+		if out.Repository == nil || out.Repository.PullRequest == nil {
+			return fmt.Errorf("pull request not found")
+		}
+		p := &out.Repository.PullRequest.Reviews
+		p.addTo(pr)
+		cursor = p.PageInfo.next()
+	}
+	return nil
+}
+
+// addThreads adds the comments of every review thread on p and of the
+// threads after it, reading long threads to the end.
+func (c *Client) addThreads(ctx context.Context, pr *model.ReviewedPR, p *threadPage) error {
+	const q = `
+query($owner:String!, $name:String!, $number:Int!, $after:String) {
+  repository(owner:$owner, name:$name) {
+    pullRequest(number:$number) {
+      reviewThreads(first:50, after:$after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          comments(first:100) {
+            pageInfo { hasNextPage endCursor }
+            nodes {
+              author { login }
+              createdAt
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	var errs []error
+	for {
+		for _, t := range p.Nodes {
+			t.Comments.addTo(pr)
+			if err := c.moreThreadComments(ctx, pr, t.ID, t.Comments.PageInfo.next()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		cursor := p.PageInfo.next()
+		if cursor == nil {
+			return errors.Join(errs...)
+		}
+		var out struct {
+			Repository *struct {
+				PullRequest *struct {
+					ReviewThreads threadPage `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := c.doGraphQL(ctx, q, pullRequestVars(pr, cursor), &out); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if out.Repository == nil || out.Repository.PullRequest == nil {
+			return errors.Join(append(errs, fmt.Errorf("pull request not found"))...)
+		}
+		p = &out.Repository.PullRequest.ReviewThreads
+	}
+}
+
+// moreThreadComments reads the comments of review thread id after cursor.
+func (c *Client) moreThreadComments(ctx context.Context, pr *model.ReviewedPR, id string, cursor *string) error {
+	const q = `
+query($id:ID!, $after:String) {
+  node(id:$id) {
+    ... on PullRequestReviewThread {
+      comments(first:100, after:$after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          author { login }
+          createdAt
+        }
+      }
+    }
+  }
+}`
+	for cursor != nil {
+		var out struct {
+			Node *struct {
+				Comments commentPage `json:"comments"`
+			} `json:"node"`
+		}
+		if err := c.doGraphQL(ctx, q, map[string]any{"id": id, "after": cursor}, &out); err != nil {
+			return err
+		}
+		if out.Node == nil {
+			return fmt.Errorf("review thread not found")
+		}
+		out.Node.Comments.addTo(pr)
+		cursor = out.Node.Comments.PageInfo.next()
+	}
+	return nil
+}
+
+func pullRequestVars(pr *model.ReviewedPR, cursor *string) map[string]any {
+	owner, name, _ := strings.Cut(pr.Repo, "/")
+	return map[string]any{"owner": owner, "name": name, "number": pr.Number, "after": cursor}
+}
@@ -0,0 +1,109 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+)
+
+// reviewsServer answers the reviewed-PRs search with one pull request whose
+// reviews, review threads and first thread's comments all have a second
+// page. failNode makes the thread-comments continuation fail.
+func reviewsServer(t *testing.T, failNode bool) *httptest.Server {
+	const (
+		review  = `{"author":{"login":"%s"},"state":"APPROVED","submittedAt":"2025-03-01T10:00:00Z","comments":{"totalCount":1}}`
+		comment = `{"author":{"login":"%s"},"createdAt":"2025-03-01T10:00:00Z"}`
+	)
+	fill := func(tmpl string, logins ...string) string {
+		parts := make([]string, len(logins))
+		for i, l := range logins {
+			parts[i] = strings.Replace(tmpl, "%s", l, 1)
+		}
+		return strings.Join(parts, ",")
+	}
+	more := func(cursor string) string {
+		if cursor == "" {
+			return `{"hasNextPage":false,"endCursor":null}`
+		}
+		return `{"hasNextPage":true,"endCursor":"` + cursor + `"}`
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		q := req.Query
+		var data string
+		switch {
+		case strings.Contains(q, "__type("):
+			data = `{"__type":null}`
+		case strings.Contains(q, "search(query:$q"):
+			data = `{"search":{"issueCount":1,"pageInfo":` + more("") + `,"nodes":[{
+				"number":7,"url":"https://github.com/acme/api/pull/7","createdAt":"2025-03-01T09:00:00Z",
+				"author":{"login":"bob"},"repository":{"nameWithOwner":"acme/api"},
+				"reviews":{"pageInfo":` + more("r1") + `,"nodes":[` + fill(review, "octocat") + `,{"author":null,"state":"PENDING","submittedAt":null,"comments":{"totalCount":0}}]},
+				"reviewThreads":{"pageInfo":` + more("t1") + `,"nodes":[{"id":"T1","comments":{"pageInfo":` + more("c1") + `,"nodes":[` + fill(comment, "octocat", "bob") + `]}}]}
+			}]}}`
+		case strings.Contains(q, "reviews(first:100"):
+			if req.Variables["after"] != "r1" || req.Variables["number"] != float64(7) || req.Variables["owner"] != "acme" {
+				t.Errorf("reviews continuation vars = %v", req.Variables)
+			}
+			data = `{"repository":{"pullRequest":{"reviews":{"pageInfo":` + more("") + `,"nodes":[` + fill(review, "alice") + `]}}}}`
+		case strings.Contains(q, "reviewThreads(first:50, after"):
+			data = `{"repository":{"pullRequest":{"reviewThreads":{"pageInfo":` + more("") + `,"nodes":[{"id":"T2","comments":{"pageInfo":` + more("") + `,"nodes":[` + fill(comment, "alice") + `]}}]}}}}`
+		case strings.Contains(q, "node(id:$id)"):
+			if failNode {
+				io.WriteString(w, `{"errors":[{"message":"Something went wrong"}]}`)
+				return
+			}
+			if req.Variables["id"] != "T1" || req.Variables["after"] != "c1" {
+				t.Errorf("comments continuation vars = %v", req.Variables)
+			}
+			data = `{"node":{"comments":{"pageInfo":` + more("") + `,"nodes":[` + fill(comment, "octocat") + `]}}}`
+		default:
+			t.Errorf("unexpected query %s", q)
+		}
+		io.WriteString(w, `{"data":`+data+`}`)
+	}))
+}
+
+func TestSearchReviewedPRsPaginates(t *testing.T) {
+	for _, failNode := range []bool{false, true} {
+		srv := reviewsServer(t, failNode)
+		client, err := New(config.Config{Token: "ghp_fake", APIBase: srv.URL + "/api/v3", GraphQLEnd: srv.URL + "/api/graphql"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		prs, count, next, err := client.searchReviewedPRs(context.Background(), "is:pr", nil)
+		srv.Close()
+		if err != nil || count != 1 || next != nil || len(prs) != 1 {
+			t.Fatalf("failNode=%v: %d PRs of %d, next %v, %v", failNode, len(prs), count, next, err)
+		}
+		pr := prs[0]
+		// The pending review is skipped; the second page adds alice's.
+		if len(pr.Reviews) != 2 || pr.Reviews[1].Author != "alice" || !pr.Reviews[1].SubmittedAt.Equal(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("failNode=%v: Reviews = %+v", failNode, pr.Reviews)
+		}
+		want := 4 // two on T1's first page, one on its second, one on T2
+		if failNode {
+			want = 3
+		}
+		if len(pr.ThreadComments) != want {
+			t.Errorf("failNode=%v: %d thread comments, want %d: %+v", failNode, len(pr.ThreadComments), want, pr.ThreadComments)
+		}
+		if pr.Truncated != failNode {
+			t.Errorf("failNode=%v: Truncated = %v", failNode, pr.Truncated)
+		}
+	}
+}
@@ -1,4 +1,3 @@
-\
 package githubapi
 
 import (
@@ -9,23 +8,130 @@ import (
 	"github.com/dennislee928/github-recap-2025/internal/model"
 )
 
-func (c *Client) SearchPullRequests(login string, from, to time.Time, maxResults int) ([]model.PRItem, error) {
-	qstr := fmt.Sprintf("author:%s is:pr created:%s..%s", login, from.Format("2006-01-02"), to.Format("2006-01-02"))
-	return c.searchPR(qstr, maxResults)
+// searchCap is the most results GitHub search returns for one query, no matter
+// how many it reports in issueCount.
+const searchCap = 1000
+
+// searchSettle is how long after a window ends its PRs and issues are still
+// expected to change (merged, closed) enough to refetch search results. After
+// that, cached pages are reused like other closed windows (withClosedWindow).
+const searchSettle = 30 * 24 * time.Hour
+
+func (c *Client) SearchPullRequests(ctx context.Context, login string, from, to time.Time, maxResults int) ([]model.PRItem, model.SearchCoverage, error) {
+	base := fmt.Sprintf("author:%s is:pr", login)
+	ctx = withClosedWindow(ctx, to.Add(searchSettle))
+	return searchWindows(ctx, base, "created", from, to, maxResults, c.searchPR, func(p model.PRItem) string { return p.URL })
+}
+
+func (c *Client) SearchIssuesOpenedClosed(ctx context.Context, login string, from, to time.Time, maxResults int) (opened []model.IssueItem, closed []model.IssueItem, openedCov, closedCov model.SearchCoverage, err error) {
+	base := fmt.Sprintf("author:%s is:issue", login)
+	key := func(it model.IssueItem) string { return it.URL }
+	ctx = withClosedWindow(ctx, to.Add(searchSettle))
+
+	opened, openedCov, err = searchWindows(ctx, base, "created", from, to, maxResults, c.searchIssues, key)
+	if err != nil { return opened, nil, openedCov, closedCov, err }
+	closed, closedCov, err = searchWindows(ctx, base, "closed", from, to, maxResults, c.searchIssues, key)
+	if err != nil { return opened, closed, openedCov, closedCov, err }
+	return opened, closed, openedCov, closedCov, nil
+}
+
+// searchPager fetches one page of a search: its items, the issueCount GitHub
+// reports for the whole query and the cursor of the next page (nil after the
+// last one).
+type searchPager[T any] func(ctx context.Context, query string, after *string) ([]T, int, *string, error)
+
+// searchWindows runs base with a field:from..to range. When GitHub reports more
+// matches than one query can return, the range is halved recursively and the
+// sub-range results are merged (deduplicated by key), so the cap only bites
+// when maxResults does. The first page of each range carries its issueCount,
+// so deciding to split costs no extra request.
+func searchWindows[T any](ctx context.Context, base, field string, from, to time.Time, maxResults int,
+	fetch searchPager[T], key func(T) string) ([]T, model.SearchCoverage, error) {
+
+	cov := model.SearchCoverage{
+		Query: fmt.Sprintf("%s %s", base, searchRange(field, from, to)),
+		Complete: true,
+	}
+	seen := map[string]bool{}
+	var all []T
+
+	var walk func(from, to time.Time, top bool) error
+	walk = func(from, to time.Time, top bool) error {
+		if len(all) >= maxResults {
+			cov.Complete = false
+			return nil
+		}
+		q := fmt.Sprintf("%s %s", base, searchRange(field, from, to))
+		var after *string
+		fetched := 0
+		for first := true; ; first = false {
+			// Pages fetched before an error (or cancellation) are kept.
+			items, count, next, err := fetch(ctx, q, after)
+			if first {
+				if top {
+					cov.TotalCount = count
+				}
+				if count > searchCap && to.Sub(from) > time.Second && err == nil {
+					// The halves return this page's items again.
+					mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+					if err := walk(from, mid, false); err != nil { return err }
+					return walk(mid.Add(time.Second), to, false)
+				}
+			}
+			for _, it := range items {
+				if len(all) >= maxResults { break }
+				fetched++
+				k := key(it)
+				if seen[k] { continue }
+				seen[k] = true
+				all = append(all, it)
+			}
+			if err != nil { return err }
+			if len(all) >= maxResults || next == nil {
+				cov.Windows++
+				if fetched < count {
+					// Either the window is still over the cap at one-second
+					// resolution, or maxResults stopped us early.
+					cov.Complete = false
+				}
+				return nil
+			}
+			after = next
+		}
+	}
+
+	if err := walk(from, to, true); err != nil {
+		cov.Fetched = len(all)
+		cov.Complete = false
+		return all, cov, err
+	}
+	cov.Fetched = len(all)
+	if cov.Fetched < cov.TotalCount {
+		cov.Complete = false
+	}
+	return all, cov, nil
+}
+
+// searchRange formats a search date qualifier. Full timestamps keep split
+// sub-ranges from overlapping at day boundaries.
+func searchRange(field string, from, to time.Time) string {
+	const layout = "2006-01-02T15:04:05Z07:00"
+	return fmt.Sprintf("%s:%s..%s", field, from.Format(layout), to.Format(layout))
 }
 
-func (c *Client) SearchIssuesOpenedClosed(login string, from, to time.Time, maxResults int) (opened []model.IssueItem, closed []model.IssueItem, err error) {
-	qOpened := fmt.Sprintf("author:%s is:issue created:%s..%s", login, from.Format("2006-01-02"), to.Format("2006-01-02"))
-	qClosed := fmt.Sprintf("author:%s is:issue closed:%s..%s", login, from.Format("2006-01-02"), to.Format("2006-01-02"))
+// searchPageInfo is the pageInfo of a search connection.
+type searchPageInfo struct{
+	HasNextPage bool `json:"hasNextPage"`
+	EndCursor *string `json:"endCursor"`
+}
 
-	opened, err = c.searchIssues(qOpened, maxResults, true)
-	if err != nil { return nil, nil, err }
-	closed, err = c.searchIssues(qClosed, maxResults, false)
-	if err != nil { return nil, nil, err }
-	return opened, closed, nil
+// next is the cursor of the following page, nil after the last one.
+func (p searchPageInfo) next() *string {
+	if !p.HasNextPage { return nil }
+	return p.EndCursor
 }
 
-func (c *Client) searchPR(query string, maxResults int) ([]model.PRItem, error) {
+func (c *Client) searchPR(ctx context.Context, query string, after *string) ([]model.PRItem, int, *string, error) {
 	const q = `
 query($q:String!, $after:String) {
   search(query:$q, type:ISSUE, first:100, after:$after) {
@@ -47,61 +153,47 @@ query($q:String!, $after:String) {
   }
 }`
 
-	var all []model.PRItem
-	var after *string
-	for len(all) < maxResults {
-		type resp struct{
-			Search struct{
-				IssueCount int `json:"issueCount"`
-				PageInfo struct{
-					HasNextPage bool `json:"hasNextPage"`
-					EndCursor *string `json:"endCursor"`
-				} `json:"pageInfo"`
-				Nodes []struct{
-					Number int `json:"number"`
-					Title string `json:"title"`
-					URL string `json:"url"`
-					CreatedAt time.Time `json:"createdAt"`
-					Merged bool `json:"merged"`
-					MergedAt *time.Time `json:"mergedAt"`
-					Additions int `json:"additions"`
-					Deletions int `json:"deletions"`
-					Repository struct{
-						NameWithOwner string `json:"nameWithOwner"`
-						IsPrivate bool `json:"isPrivate"`
-					} `json:"repository"`
-				} `json:"nodes"`
-			} `json:"search"`
-		}
-		var out resp
-		vars := map[string]any{"q": query}
-		if after != nil { vars["after"] = *after } else { vars["after"] = nil }
-		if err := c.doGraphQL(context.Background(), q, vars, &out); err != nil {
-			return nil, err
-		}
-		for _, n := range out.Search.Nodes {
-			all = append(all, model.PRItem{
-				Repo: n.Repository.NameWithOwner,
-				Number: n.Number,
-				Title: n.Title,
-				URL: n.URL,
-				CreatedAt: n.CreatedAt,
-				Merged: n.Merged,
-				MergedAt: n.MergedAt,
-				Additions: n.Additions,
-				Deletions: n.Deletions,
-			})
-			if len(all) >= maxResults { break }
-		}
-		if !out.Search.PageInfo.HasNextPage || out.Search.PageInfo.EndCursor == nil {
-			break
-		}
-		after = out.Search.PageInfo.EndCursor
+	var out struct{
+		Search struct{
+			IssueCount int `json:"issueCount"`
+			PageInfo searchPageInfo `json:"pageInfo"`
+			Nodes []struct{
+				Number int `json:"number"`
+				Title string `json:"title"`
+				URL string `json:"url"`
+				CreatedAt time.Time `json:"createdAt"`
+				Merged bool `json:"merged"`
+				MergedAt *time.Time `json:"mergedAt"`
+				Additions int `json:"additions"`
+				Deletions int `json:"deletions"`
+				Repository struct{
+					NameWithOwner string `json:"nameWithOwner"`
+					IsPrivate bool `json:"isPrivate"`
+				} `json:"repository"`
+			} `json:"nodes"`
+		} `json:"search"`
 	}
-	return all, nil
+	if err := c.doGraphQL(ctx, q, map[string]any{"q": query, "after": after}, &out); err != nil {
+		return nil, 0, nil, err
+	}
+	page := make([]model.PRItem, 0, len(out.Search.Nodes))
+	for _, n := range out.Search.Nodes {
+		page = append(page, model.PRItem{
+			Repo: n.Repository.NameWithOwner,
+			Number: n.Number,
+			Title: n.Title,
+			URL: n.URL,
+			CreatedAt: n.CreatedAt,
+			Merged: n.Merged,
+			MergedAt: n.MergedAt,
+			Additions: n.Additions,
+			Deletions: n.Deletions,
+		})
+	}
+	return page, out.Search.IssueCount, out.Search.PageInfo.next(), nil
 }
 
-func (c *Client) searchIssues(query string, maxResults int, wantCreated bool) ([]model.IssueItem, error) {
+func (c *Client) searchIssues(ctx context.Context, query string, after *string) ([]model.IssueItem, int, *string, error) {
 	const q = `
 query($q:String!, $after:String) {
   search(query:$q, type:ISSUE, first:100, after:$after) {
@@ -120,50 +212,36 @@ query($q:String!, $after:String) {
   }
 }`
 
-	var all []model.IssueItem
-	var after *string
-	for len(all) < maxResults {
-		type resp struct{
-			Search struct{
-				IssueCount int `json:"issueCount"`
-				PageInfo struct{
-					HasNextPage bool `json:"hasNextPage"`
-					EndCursor *string `json:"endCursor"`
-				} `json:"pageInfo"`
-				Nodes []struct{
-					Number int `json:"number"`
-					Title string `json:"title"`
-					URL string `json:"url"`
-					CreatedAt time.Time `json:"createdAt"`
-					ClosedAt *time.Time `json:"closedAt"`
-					Repository struct{
-						NameWithOwner string `json:"nameWithOwner"`
-						IsPrivate bool `json:"isPrivate"`
-					} `json:"repository"`
-				} `json:"nodes"`
-			} `json:"search"`
-		}
-		var out resp
-		vars := map[string]any{"q": query}
-		if after != nil { vars["after"] = *after } else { vars["after"] = nil }
-		if err := c.doGraphQL(context.Background(), q, vars, &out); err != nil {
-			return nil, err
-		}
-		for _, n := range out.Search.Nodes {
-			all = append(all, model.IssueItem{
-				Repo: n.Repository.NameWithOwner,
-				Number: n.Number,
-				Title: n.Title,
-				URL: n.URL,
-				CreatedAt: n.CreatedAt,
-				ClosedAt: n.ClosedAt,
-			})
-			if len(all) >= maxResults { break }
-		}
-		if !out.Search.PageInfo.HasNextPage || out.Search.PageInfo.EndCursor == nil {
-			break
-		}
-		after = out.Search.PageInfo.EndCursor
+	var out struct{
+		Search struct{
+			IssueCount int `json:"issueCount"`
+			PageInfo searchPageInfo `json:"pageInfo"`
+			Nodes []struct{
+				Number int `json:"number"`
+				Title string `json:"title"`
+				URL string `json:"url"`
+				CreatedAt time.Time `json:"createdAt"`
+				ClosedAt *time.Time `json:"closedAt"`
+				Repository struct{
+					NameWithOwner string `json:"nameWithOwner"`
+					IsPrivate bool `json:"isPrivate"`
+				} `json:"repository"`
+			} `json:"nodes"`
+		} `json:"search"`
+	}
+	if err := c.doGraphQL(ctx, q, map[string]any{"q": query, "after": after}, &out); err != nil {
+		return nil, 0, nil, err
+	}
+	page := make([]model.IssueItem, 0, len(out.Search.Nodes))
+	for _, n := range out.Search.Nodes {
+		page = append(page, model.IssueItem{
+			Repo: n.Repository.NameWithOwner,
+			Number: n.Number,
+			Title: n.Title,
+			URL: n.URL,
+			CreatedAt: n.CreatedAt,
+			ClosedAt: n.ClosedAt,
+		})
 	}
-	return all, nil
+	return page, out.Search.IssueCount, out.Search.PageInfo.next(), nil
 }
@@ -0,0 +1,133 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSearch answers searchWindows over items created at the given times, 100
+// per page, as GitHub does: issueCount is every match, but no query returns
+// more than searchCap.
+type fakeSearch struct {
+	times []time.Time
+	calls []string // query and cursor of every page request
+	fail  int      // fail the nth call (1-based), 0 never
+}
+
+func (f *fakeSearch) page(_ context.Context, query string, after *string) ([]string, int, *string, error) {
+	cursor := ""
+	if after != nil {
+		cursor = *after
+	}
+	f.calls = append(f.calls, query+" after="+cursor)
+	if len(f.calls) == f.fail {
+		return nil, 0, nil, errors.New("boom")
+	}
+	rng := query[strings.Index(query, "created:")+len("created:"):]
+	parts := strings.SplitN(rng, "..", 2)
+	from, _ := time.Parse(time.RFC3339, parts[0])
+	to, _ := time.Parse(time.RFC3339, parts[1])
+	var matches []string
+	for i, t := range f.times {
+		if !t.Before(from) && !t.After(to) {
+			matches = append(matches, strconv.Itoa(i))
+		}
+	}
+	reachable := matches[:min(len(matches), searchCap)]
+	off, _ := strconv.Atoi(cursor)
+	end := min(off+100, len(reachable))
+	var next *string
+	if end < len(reachable) {
+		s := strconv.Itoa(end)
+		next = &s
+	}
+	return reachable[off:end], len(matches), next, nil
+}
+
+func TestSearchWindows(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+	spread := func(n int) []time.Time {
+		out := make([]time.Time, n)
+		for i := range out {
+			out[i] = from.Add(to.Sub(from) / time.Duration(n) * time.Duration(i)).Truncate(time.Second)
+		}
+		return out
+	}
+	id := func(s string) string { return s }
+
+	t.Run("one page", func(t *testing.T) {
+		f := &fakeSearch{times: spread(40)}
+		got, cov, err := searchWindows(context.Background(), "is:pr", "created", from, to, 10000, f.page, id)
+		if err != nil || len(got) != 40 || !cov.Complete || cov.TotalCount != 40 || cov.Windows != 1 {
+			t.Fatalf("got %d, %+v, %v", len(got), cov, err)
+		}
+		// No separate issueCount request: the page is the only call.
+		if len(f.calls) != 1 {
+			t.Errorf("calls = %v", f.calls)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		f := &fakeSearch{}
+		got, cov, err := searchWindows(context.Background(), "is:pr", "created", from, to, 10000, f.page, id)
+		if err != nil || len(got) != 0 || !cov.Complete || cov.Windows != 1 || len(f.calls) != 1 {
+			t.Fatalf("got %d, %+v, %v, calls %v", len(got), cov, err, f.calls)
+		}
+	})
+
+	t.Run("split over the cap", func(t *testing.T) {
+		f := &fakeSearch{times: spread(2500)}
+		got, cov, err := searchWindows(context.Background(), "is:pr", "created", from, to, 10000, f.page, id)
+		if err != nil || len(got) != 2500 || !cov.Complete || cov.TotalCount != 2500 || cov.Fetched != 2500 {
+			t.Fatalf("got %d, %+v, %v", len(got), cov, err)
+		}
+		// The year and both halves are split on their first page; the four
+		// quarters of 625 take 7 pages each.
+		if len(f.calls) != 3+4*7 || cov.Windows != 4 {
+			t.Errorf("%d calls over %d windows: %v", len(f.calls), cov.Windows, f.calls)
+		}
+	})
+
+	t.Run("maxResults", func(t *testing.T) {
+		f := &fakeSearch{times: spread(250)}
+		got, cov, err := searchWindows(context.Background(), "is:pr", "created", from, to, 150, f.page, id)
+		if err != nil || len(got) != 150 || cov.Complete || cov.Fetched != 150 || len(f.calls) != 2 {
+			t.Fatalf("got %d, %+v, %v, calls %v", len(got), cov, err, f.calls)
+		}
+	})
+
+	t.Run("over the cap within a second", func(t *testing.T) {
+		times := make([]time.Time, 1200)
+		for i := range times {
+			times[i] = from
+		}
+		f := &fakeSearch{times: times}
+		got, cov, err := searchWindows(context.Background(), "is:pr", "created", from, from, 10000, f.page, id)
+		if err != nil || len(got) != searchCap || cov.Complete {
+			t.Fatalf("got %d, %+v, %v", len(got), cov, err)
+		}
+	})
+
+	t.Run("error keeps earlier pages", func(t *testing.T) {
+		f := &fakeSearch{times: spread(250), fail: 3}
+		got, cov, err := searchWindows(context.Background(), "is:pr", "created", from, to, 10000, f.page, id)
+		if err == nil || len(got) != 200 || cov.Complete || cov.Fetched != 200 {
+			t.Fatalf("got %d, %+v, %v", len(got), cov, err)
+		}
+	})
+
+	t.Run("deduplicated across ranges", func(t *testing.T) {
+		f := &fakeSearch{times: spread(1500)}
+		key := func(s string) string { n, _ := strconv.Atoi(s); return fmt.Sprint(n % 700) }
+		got, _, err := searchWindows(context.Background(), "is:pr", "created", from, to, 10000, f.page, key)
+		if err != nil || len(got) != 700 {
+			t.Fatalf("got %d, %v", len(got), err)
+		}
+	})
+}
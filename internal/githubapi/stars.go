@@ -0,0 +1,205 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// starBatch is how many repositories share one aliased stargazers query. Each
+// asks for a 100-edge page, so a batch stays around 1,000 nodes.
+const starBatch = 10
+
+type starRepo struct {
+	owner, name string
+}
+
+func (r starRepo) String() string { return r.owner + "/" + r.name }
+
+// starPage is one page of a stargazers connection, newest star first.
+type starPage struct {
+	PageInfo struct {
+		HasNextPage bool    `json:"hasNextPage"`
+		EndCursor   *string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Edges []struct {
+		StarredAt time.Time `json:"starredAt"`
+	} `json:"edges"`
+}
+
+// starHistory is what the growth section needs from one repository's
+// stargazers.
+type starHistory struct {
+	inRange []time.Time // stars inside [from, to], newest first
+	after   int         // stars newer than to
+	first   *time.Time  // the repository's first star ever
+}
+
+// collect adds the page's stars to h and reports whether the walk is done: a
+// star older than from was reached, or there are no more.
+func (p *starPage) collect(from, to time.Time, h *starHistory) bool {
+	for _, e := range p.Edges {
+		switch {
+		case e.StarredAt.Before(from):
+			return true
+		case e.StarredAt.After(to):
+			h.after++
+		default:
+			h.inRange = append(h.inRange, e.StarredAt)
+		}
+	}
+	return !p.PageInfo.HasNextPage || p.PageInfo.EndCursor == nil
+}
+
+// starsInRange returns, per "owner/name", the timestamps of the stars given
+// inside [from, to], how many came later and the date of the first star.
+// Stargazers are read newest first, so a repository costs one page per 100
+// stars received since from instead of one per 100 stars ever. First pages
+// are fetched in aliased batches and only repositories with more stars since
+// from than a page holds are continued one by one. A batch GraphQL rejects
+// falls back to the REST stargazers list. Repositories that fail are left out
+// and returned as *RequestError, joined.
+func (c *Client) starsInRange(ctx context.Context, client *github.Client, repos []starRepo, from, to time.Time) (map[string]*starHistory, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		out  = make(map[string]*starHistory, len(repos))
+		errs []error
+	)
+	record := func(r starRepo, stars *starHistory, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, &RequestError{Op: "list_stargazers", Repo: r.String(), Err: err})
+			return
+		}
+		out[r.String()] = stars
+	}
+
+	sem := make(chan struct{}, 4)
+	for start := 0; start < len(repos); start += starBatch {
+		batch := repos[start:min(start+starBatch, len(repos))]
+		wg.Add(1)
+		go func(batch []starRepo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pages, err := c.firstStarPages(ctx, batch)
+			if err != nil {
+				if ctx.Err() != nil {
+					for _, r := range batch {
+						record(r, nil, err)
+					}
+					return
+				}
+				for _, r := range batch {
+					stars, err := c.restStarsInRange(ctx, client, r.owner, r.name, from, to)
+					record(r, stars, err)
+				}
+				return
+			}
+			for i, r := range batch {
+				p := pages[i]
+				if p == nil {
+					record(r, nil, fmt.Errorf("repository not found"))
+					continue
+				}
+				var err error
+				stars := &starHistory{first: p.first}
+				if !p.newest.collect(from, to, stars) {
+					err = c.moreStars(ctx, r, p.newest.PageInfo.EndCursor, from, to, stars)
+				}
+				record(r, stars, err)
+			}
+		}(batch)
+	}
+	wg.Wait()
+	return out, errors.Join(errs...)
+}
+
+// firstStarPage is a repository's newest page of stargazers and its
+// oldest star.
+type firstStarPage struct {
+	newest starPage
+	first  *time.Time
+}
+
+// firstStarPages fetches the newest page of stargazers, and the very first
+// star, of every repository in batch with one aliased query. A nil page means
+// the repository was not returned.
+func (c *Client) firstStarPages(ctx context.Context, batch []starRepo) ([]*firstStarPage, error) {
+	var q strings.Builder
+	q.WriteString("query {\n")
+	for i, r := range batch {
+		fmt.Fprintf(&q, `  r%d: repository(owner:%s, name:%s) {
+    newest: stargazers(first:100, orderBy:{field:STARRED_AT, direction:DESC}) {
+      pageInfo { hasNextPage endCursor }
+      edges { starredAt }
+    }
+    oldest: stargazers(first:1, orderBy:{field:STARRED_AT, direction:ASC}) {
+      edges { starredAt }
+    }
+  }
+`, i, strconv.Quote(r.owner), strconv.Quote(r.name))
+	}
+	q.WriteString("}")
+
+	var out map[string]*struct {
+		Newest starPage `json:"newest"`
+		Oldest starPage `json:"oldest"`
+	}
+	if err := c.doGraphQLAllowMissing(ctx, q.String(), nil, &out); err != nil {
+		return nil, err
+	}
+	pages := make([]*firstStarPage, len(batch))
+	for i := range batch {
+		r := out["r"+strconv.Itoa(i)]
+		if r == nil {
+			continue
+		}
+		pages[i] = &firstStarPage{newest: r.Newest}
+		if len(r.Oldest.Edges) > 0 {
+			pages[i].first = &r.Oldest.Edges[0].StarredAt
+		}
+	}
+	return pages, nil
+}
+
+// moreStars continues one repository's stargazers after cursor until a star
+// older than from.
+func (c *Client) moreStars(ctx context.Context, r starRepo, cursor *string, from, to time.Time, h *starHistory) error {
+	const q = `
+query($owner:String!, $name:String!, $after:String) {
+  repository(owner:$owner, name:$name) {
+    stargazers(first:100, after:$after, orderBy:{field:STARRED_AT, direction:DESC}) {
+      pageInfo { hasNextPage endCursor }
+      edges { starredAt }
+    }
+  }
+}`
+	for {
+		var out struct {
+			Repository *struct {
+				Stargazers starPage `json:"stargazers"`
+			} `json:"repository"`
+		}
+		if err := c.doGraphQL(ctx, q, map[string]any{"owner": r.owner, "name": r.name, "after": cursor}, &out); err != nil {
+			return err
+		}
+		if out.Repository == nil {
+			return fmt.Errorf("repository not found")
+		}
+		p := &out.Repository.Stargazers
+		if p.collect(from, to, h) {
+			return nil
+		}
+		cursor = p.PageInfo.EndCursor
+	}
+}
@@ -0,0 +1,122 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/config"
+	"github.com/dennislee928/github-recap-2025/internal/githubapi/githubtest"
+)
+
+func starDay(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestStarsInRangeMissingRepo(t *testing.T) {
+	day := func(s string) time.Time { return starDay(t, s) }
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{
+		User: githubtest.User{Login: "octocat"},
+		Repos: []githubtest.Repo{
+			{Owner: "octocat", Name: "hello-world", OwnerType: "User", Stargazers: []time.Time{day("2024-06-01"), day("2025-03-01"), day("2026-01-10")}},
+			{Owner: "octocat", Name: "quiet", OwnerType: "User"},
+		},
+	})
+	defer srv.Close()
+	c, err := New(srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := c.rest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := []starRepo{{"octocat", "hello-world"}, {"octocat", "gone"}, {"octocat", "quiet"}}
+	before := srv.Requests()
+	got, err := c.starsInRange(context.Background(), rc, repos, day("2025-01-01"), day("2025-12-31"))
+
+	// The missing repository is reported on its own; the rest of the batch is
+	// still read from the one GraphQL query, not the REST fallback.
+	if err == nil || !strings.Contains(err.Error(), "octocat/gone") || strings.Contains(err.Error(), "hello-world") {
+		t.Errorf("err = %v, want only octocat/gone", err)
+	}
+	if n := srv.Requests() - before; n != 1 {
+		t.Errorf("%d requests, want the single batch query", n)
+	}
+	if _, ok := got["octocat/gone"]; ok || len(got) != 2 {
+		t.Errorf("repos = %v", got)
+	}
+	h := got["octocat/hello-world"]
+	if h == nil || len(h.inRange) != 1 || h.after != 1 || h.first == nil || !h.first.Equal(day("2024-06-01")) {
+		t.Errorf("octocat/hello-world = %+v", h)
+	}
+	if q := got["octocat/quiet"]; q == nil || len(q.inRange) != 0 || q.first != nil {
+		t.Errorf("octocat/quiet = %+v", q)
+	}
+}
+
+func TestStarsInRangeContinuationFails(t *testing.T) {
+	day := func(s string) time.Time { return starDay(t, s) }
+	// 150 stars in the window: octocat/big needs a second page.
+	var big []time.Time
+	for i := 0; i < 150; i++ {
+		big = append(big, day("2025-03-01").Add(time.Duration(i)*time.Hour))
+	}
+	srv := githubtest.NewServerFixtures(&githubtest.Fixtures{
+		User: githubtest.User{Login: "octocat"},
+		Repos: []githubtest.Repo{
+			{Owner: "octocat", Name: "big", OwnerType: "User", Stargazers: big},
+			{Owner: "octocat", Name: "small", OwnerType: "User", Stargazers: []time.Time{day("2025-05-01"), day("2025-06-01")}},
+		},
+	})
+	defer srv.Close()
+	// The second page of octocat/big fails; everything else is served.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.Unmarshal(body, &req)
+		if strings.Contains(req.Query, "stargazers(first:100, after:$after") && req.Variables["name"] == "big" {
+			io.WriteString(w, `{"errors":[{"message":"Something went wrong"}]}`)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		srv.Server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	c, err := New(config.Config{Token: "ghp_fake", APIBase: proxy.URL + "/api/v3", GraphQLEnd: proxy.URL + "/api/graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := c.rest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := []starRepo{{"octocat", "big"}, {"octocat", "small"}}
+	got, err := c.starsInRange(context.Background(), rc, repos, day("2025-01-01"), day("2025-12-31"))
+
+	if err == nil || !strings.Contains(err.Error(), "octocat/big") || strings.Contains(err.Error(), "octocat/small") {
+		t.Errorf("err = %v, want only octocat/big", err)
+	}
+	if _, ok := got["octocat/big"]; ok {
+		t.Error("octocat/big returned despite its failed page")
+	}
+	if h := got["octocat/small"]; h == nil || len(h.inRange) != 2 {
+		t.Errorf("octocat/small = %+v, want its 2 stars", h)
+	}
+}
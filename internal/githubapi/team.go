@@ -0,0 +1,168 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// ListOrgMembers returns the logins of every member of org. Private members
+// are only visible to tokens that belong to an org member with read:org.
+func (c *Client) ListOrgMembers(ctx context.Context, org string) ([]string, error) {
+	const q = `
+query($org:String!, $after:String) {
+  organization(login:$org) {
+    membersWithRole(first:100, after:$after) {
+      pageInfo { hasNextPage endCursor }
+      nodes { login }
+    }
+  }
+}`
+	var logins []string
+	var after *string
+	for {
+		var out struct {
+			Organization *struct {
+				Members memberPage `json:"membersWithRole"`
+			} `json:"organization"`
+		}
+		if err := c.doGraphQL(ctx, q, map[string]any{"org": org, "after": after}, &out); err != nil {
+			return nil, err
+		}
+		if out.Organization == nil {
+			return nil, fmt.Errorf("organization %q not found", org)
+		}
+		logins = append(logins, out.Organization.Members.logins()...)
+		if !out.Organization.Members.PageInfo.HasNextPage {
+			break
+		}
+		after = out.Organization.Members.PageInfo.EndCursor
+	}
+	sort.Strings(logins)
+	return logins, nil
+}
+
+// ListTeamMembers returns the logins of a team given as "org/slug", including
+// members of child teams.
+func (c *Client) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	org, slug, ok := strings.Cut(team, "/")
+	if !ok || org == "" || slug == "" {
+		return nil, fmt.Errorf("team must be org/slug, got %q", team)
+	}
+	const q = `
+query($org:String!, $slug:String!, $after:String) {
+  organization(login:$org) {
+    team(slug:$slug) {
+      members(first:100, after:$after, membership:ALL) {
+        pageInfo { hasNextPage endCursor }
+        nodes { login }
+      }
+    }
+  }
+}`
+	var logins []string
+	var after *string
+	for {
+		var out struct {
+			Organization *struct {
+				Team *struct {
+					Members memberPage `json:"members"`
+				} `json:"team"`
+			} `json:"organization"`
+		}
+		if err := c.doGraphQL(ctx, q, map[string]any{"org": org, "slug": slug, "after": after}, &out); err != nil {
+			return nil, err
+		}
+		if out.Organization == nil || out.Organization.Team == nil {
+			return nil, fmt.Errorf("team %q not found (token needs read:org)", team)
+		}
+		logins = append(logins, out.Organization.Team.Members.logins()...)
+		if !out.Organization.Team.Members.PageInfo.HasNextPage {
+			break
+		}
+		after = out.Organization.Team.Members.PageInfo.EndCursor
+	}
+	sort.Strings(logins)
+	return logins, nil
+}
+
+type memberPage struct {
+	PageInfo struct {
+		HasNextPage bool    `json:"hasNextPage"`
+		EndCursor   *string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		Login string `json:"login"`
+	} `json:"nodes"`
+}
+
+func (p memberPage) logins() []string {
+	out := make([]string, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		out = append(out, n.Login)
+	}
+	return out
+}
+
+// SearchReviewedPRAuthors counts, per author, the PRs created in the window
+// that login reviewed (excluding their own PRs).
+func (c *Client) SearchReviewedPRAuthors(ctx context.Context, login string, from, to time.Time, maxResults int) (map[string]int, model.SearchCoverage, error) {
+	base := fmt.Sprintf("is:pr reviewed-by:%s -author:%s", login, login)
+	prs, cov, err := searchWindows(ctx, base, "created", from, to, maxResults, c.searchPRAuthors, func(p prAuthor) string { return p.URL })
+	counts := map[string]int{}
+	for _, p := range prs {
+		if p.Author != "" {
+			counts[p.Author]++
+		}
+	}
+	return counts, cov, err
+}
+
+type prAuthor struct {
+	URL    string
+	Author string
+}
+
+func (c *Client) searchPRAuthors(ctx context.Context, query string, after *string) ([]prAuthor, int, *string, error) {
+	const q = `
+query($q:String!, $after:String) {
+  search(query:$q, type:ISSUE, first:100, after:$after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        url
+        author { login }
+      }
+    }
+  }
+}`
+	var out struct {
+		Search struct {
+			IssueCount int            `json:"issueCount"`
+			PageInfo   searchPageInfo `json:"pageInfo"`
+			Nodes      []struct {
+				URL    string `json:"url"`
+				Author *struct {
+					Login string `json:"login"`
+				} `json:"author"`
+			} `json:"nodes"`
+		} `json:"search"`
+	}
+	if err := c.doGraphQL(ctx, q, map[string]any{"q": query, "after": after}, &out); err != nil {
+		return nil, 0, nil, err
+	}
+	page := make([]prAuthor, 0, len(out.Search.Nodes))
+	for _, n := range out.Search.Nodes {
+		p := prAuthor{URL: n.URL}
+		if n.Author != nil { // deleted accounts ("ghost") have no author
+			p.Author = n.Author.Login
+		}
+		page = append(page, p)
+	}
+	return page, out.Search.IssueCount, out.Search.PageInfo.next(), nil
+}
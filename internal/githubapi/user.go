@@ -0,0 +1,42 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dennislee928/github-recap-2025/internal/model"
+)
+
+// FetchUser returns the profile fields the recap needs (node ID, display name,
+// free-form location).
+func (c *Client) FetchUser(ctx context.Context, login string) (*model.UserProfile, error) {
+	const q = `
+query($login:String!) {
+  user(login:$login) {
+    id
+    login
+    name
+    location
+  }
+}`
+	var out struct {
+		User *struct {
+			ID       string `json:"id"`
+			Login    string `json:"login"`
+			Name     string `json:"name"`
+			Location string `json:"location"`
+		} `json:"user"`
+	}
+	if err := c.doGraphQL(ctx, q, map[string]any{"login": login}, &out); err != nil {
+		return nil, err
+	}
+	if out.User == nil {
+		return nil, fmt.Errorf("user %q not found", login)
+	}
+	return &model.UserProfile{
+		ID:       out.User.ID,
+		Login:    out.User.Login,
+		Name:     out.User.Name,
+		Location: out.User.Location,
+	}, nil
+}
@@ -0,0 +1,210 @@
+// Package linguist maps file paths to programming languages the way GitHub's
+// linguist does for the common cases: by file name first, then by extension,
+// with vendored and generated paths recognised so they can be left out.
+package linguist
+
+import (
+	"path"
+	"strings"
+)
+
+// filenames are matched on the whole base name, before extensions.
+var filenames = map[string]string{
+	"Dockerfile":     "Dockerfile",
+	"Containerfile":  "Dockerfile",
+	"Makefile":       "Makefile",
+	"GNUmakefile":    "Makefile",
+	"makefile":       "Makefile",
+	"CMakeLists.txt": "CMake",
+	"Rakefile":       "Ruby",
+	"Gemfile":        "Ruby",
+	"Podfile":        "Ruby",
+	"Vagrantfile":    "Ruby",
+	"Jenkinsfile":    "Groovy",
+	"BUILD":          "Starlark",
+	"BUILD.bazel":    "Starlark",
+	"WORKSPACE":      "Starlark",
+	"Justfile":       "Just",
+	"justfile":       "Just",
+	".bashrc":        "Shell",
+	".zshrc":         "Shell",
+	".profile":       "Shell",
+}
+
+// extensions maps a lower-case extension, dot included, to its language.
+// Like GitHub's language bar, only programming and markup languages are
+// listed; data (JSON, YAML...) and prose (Markdown...) don't count.
+var extensions = map[string]string{
+	".go":         "Go",
+	".py":         "Python",
+	".pyi":        "Python",
+	".pyx":        "Cython",
+	".ipynb":      "Jupyter Notebook",
+	".js":         "JavaScript",
+	".mjs":        "JavaScript",
+	".cjs":        "JavaScript",
+	".jsx":        "JavaScript",
+	".ts":         "TypeScript",
+	".mts":        "TypeScript",
+	".cts":        "TypeScript",
+	".tsx":        "TSX",
+	".vue":        "Vue",
+	".svelte":     "Svelte",
+	".astro":      "Astro",
+	".html":       "HTML",
+	".htm":        "HTML",
+	".css":        "CSS",
+	".scss":       "SCSS",
+	".sass":       "Sass",
+	".less":       "Less",
+	".java":       "Java",
+	".kt":         "Kotlin",
+	".kts":        "Kotlin",
+	".scala":      "Scala",
+	".groovy":     "Groovy",
+	".gradle":     "Groovy",
+	".clj":        "Clojure",
+	".cljs":       "Clojure",
+	".c":          "C",
+	".h":          "C",
+	".cc":         "C++",
+	".cpp":        "C++",
+	".cxx":        "C++",
+	".hh":         "C++",
+	".hpp":        "C++",
+	".hxx":        "C++",
+	".m":          "Objective-C",
+	".mm":         "Objective-C++",
+	".cs":         "C#",
+	".fs":         "F#",
+	".vb":         "Visual Basic .NET",
+	".swift":      "Swift",
+	".rs":         "Rust",
+	".zig":        "Zig",
+	".nim":        "Nim",
+	".d":          "D",
+	".rb":         "Ruby",
+	".erb":        "HTML+ERB",
+	".php":        "PHP",
+	".pl":         "Perl",
+	".pm":         "Perl",
+	".lua":        "Lua",
+	".r":          "R",
+	".jl":         "Julia",
+	".dart":       "Dart",
+	".ex":         "Elixir",
+	".exs":        "Elixir",
+	".erl":        "Erlang",
+	".hrl":        "Erlang",
+	".hs":         "Haskell",
+	".ml":         "OCaml",
+	".mli":        "OCaml",
+	".elm":        "Elm",
+	".sh":         "Shell",
+	".bash":       "Shell",
+	".zsh":        "Shell",
+	".fish":       "Fish",
+	".ps1":        "PowerShell",
+	".psm1":       "PowerShell",
+	".bat":        "Batchfile",
+	".cmd":        "Batchfile",
+	".sql":        "SQL",
+	".graphql":    "GraphQL",
+	".gql":        "GraphQL",
+	".proto":      "Protocol Buffer",
+	".thrift":     "Thrift",
+	".tf":         "HCL",
+	".hcl":        "HCL",
+	".nix":        "Nix",
+	".dockerfile": "Dockerfile",
+	".mk":         "Makefile",
+	".cmake":      "CMake",
+	".bzl":        "Starlark",
+	".tex":        "TeX",
+	".asm":        "Assembly",
+	".s":          "Assembly",
+	".sol":        "Solidity",
+	".v":          "Verilog",
+	".sv":         "SystemVerilog",
+	".vhd":        "VHDL",
+	".cu":         "Cuda",
+	".wgsl":       "WGSL",
+	".glsl":       "GLSL",
+	".hlsl":       "HLSL",
+	".tmpl":       "Go Template",
+	".gotmpl":     "Go Template",
+}
+
+// vendoredDirs are path segments whose contents linguist treats as vendored
+// or generated. Names such as build, out or target are left out: they are
+// as often hand-written packages (Go's build, Rust's target triples in
+// source) as they are output directories.
+var vendoredDirs = map[string]bool{
+	"vendor":           true,
+	"node_modules":     true,
+	"bower_components": true,
+	"third_party":      true,
+	"thirdparty":       true,
+	"dist":             true,
+	".yarn":            true,
+	"Pods":             true,
+	"Carthage":         true,
+	"__generated__":    true,
+}
+
+// generatedFiles are lock files and other files tools write, not people.
+var generatedFiles = map[string]bool{
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"bun.lockb":         true,
+	"Cargo.lock":        true,
+	"Gemfile.lock":      true,
+	"poetry.lock":       true,
+	"Pipfile.lock":      true,
+	"composer.lock":     true,
+	"go.sum":            true,
+	"flake.lock":        true,
+}
+
+// generatedSuffixes are name endings of minified or generated sources.
+var generatedSuffixes = []string{
+	".min.js", ".min.css", ".map",
+	".pb.go", "_pb2.py", ".pb.cc", ".pb.h",
+	"_generated.go", ".gen.go", "_gen.go", ".generated.ts",
+	".designer.cs", ".g.dart", ".freezed.dart",
+}
+
+// Language returns the language of the file at p (a slash-separated path),
+// or "" if it is not recognised.
+func Language(p string) string {
+	base := path.Base(p)
+	if lang, ok := filenames[base]; ok {
+		return lang
+	}
+	if strings.HasPrefix(base, "Dockerfile.") {
+		return "Dockerfile"
+	}
+	return extensions[strings.ToLower(path.Ext(base))]
+}
+
+// Vendored reports whether p is vendored, a lock file or generated, and
+// should not count as the author's code.
+func Vendored(p string) bool {
+	base := path.Base(p)
+	if generatedFiles[base] {
+		return true
+	}
+	for _, s := range generatedSuffixes {
+		if strings.HasSuffix(base, s) {
+			return true
+		}
+	}
+	dirs := strings.Split(path.Dir(p), "/")
+	for _, d := range dirs {
+		if vendoredDirs[d] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package linguist
+
+import "testing"
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"main.go", "Go"},
+		{"cmd/recap/main.go", "Go"},
+		{"web/app.TSX", "TSX"},
+		{"lib/index.d.ts", "TypeScript"},
+		{"scripts/release.sh", "Shell"},
+		{"Makefile", "Makefile"},
+		{"deploy/Dockerfile", "Dockerfile"},
+		{"deploy/Dockerfile.dev", "Dockerfile"},
+		{"api.dockerfile", "Dockerfile"},
+		{"tools/BUILD.bazel", "Starlark"},
+		{"CMakeLists.txt", "CMake"},
+		{"Gemfile", "Ruby"},
+		{"home/.zshrc", "Shell"},
+		// Data and prose don't count.
+		{"README.md", ""},
+		{"config.yaml", ""},
+		{"package.json", ""},
+		{"notes.txt", ""},
+		{"LICENSE", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Language(tt.path); got != tt.want {
+			t.Errorf("Language(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestVendored(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"vendor/github.com/x/y/z.go", true},
+		{"web/node_modules/react/index.js", true},
+		{"third_party/zlib/inflate.c", true},
+		{"dist/app.js", true},
+		{"ios/Pods/Alamofire/Source.swift", true},
+		{"package-lock.json", true},
+		{"go.sum", true},
+		{"web/static/app.min.js", true},
+		{"api/v1/service.pb.go", true},
+		{"models/user_generated.go", true},
+		{"lib/model.g.dart", true},
+		// Hand-written code in directories often named for build output.
+		{"internal/build/build.go", false},
+		{"cmd/out/main.go", false},
+		{"src/target/triple.rs", false},
+		{"build.gradle", false},
+		{"main.go", false},
+		{"internal/vendoring/vendor.go", false},
+		{"src/distance.go", false},
+	}
+	for _, tt := range tests {
+		if got := Vendored(tt.path); got != tt.want {
+			t.Errorf("Vendored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,11 +2,88 @@ package model
 
 import "time"
 
+type UserProfile struct {
+	ID string `json:"id"` // GraphQL node ID
+	Login string `json:"login"`
+	Name string `json:"name"`
+	Location string `json:"location"`
+}
+
 type ContributionDay struct {
 	Date  string `json:"date"`  // YYYY-MM-DD
 	Count int    `json:"count"`
 }
 
+// Streak is a run of consecutive days: with contributions for streaks,
+// without for gaps. Start and End are YYYY-MM-DD, inclusive.
+type Streak struct {
+	Start         string `json:"start"`
+	End           string `json:"end"`
+	Days          int    `json:"days"`
+	Contributions int    `json:"contributions,omitempty"`
+}
+
+// StreakStats answers when the streaks were, not just how long.
+type StreakStats struct {
+	Longest *Streak `json:"longest,omitempty"`
+	// Current is the streak still running at the window end. While the
+	// window is open, a today without contributions yet doesn't end it.
+	Current *Streak `json:"current,omitempty"`
+	Top []Streak `json:"top"` // longest first
+	// LongestGap is the longest run of days without contributions between
+	// two active days.
+	LongestGap *Streak `json:"longest_gap,omitempty"`
+
+	ActiveDays  int `json:"active_days"`
+	WeekdayDays int `json:"weekday_days"` // active days Monday to Friday
+	WeekendDays int `json:"weekend_days"`
+	// Weekday is the longest streak of active weekdays, where weekends
+	// neither break nor extend it; set with --weekday-streak.
+	Weekday *Streak `json:"weekday,omitempty"`
+}
+
+// PunchCard counts the user's timestamped events by local weekday and hour.
+type PunchCard struct {
+	Days []string `json:"days"` // row labels, "Mon".."Sun"
+	// Cells[d][h] counts the events on Days[d] in hour h (00-23).
+	Cells [7][24]int `json:"cells"`
+	Total int        `json:"total"`
+	// Sources counts the events of each kind: "pr_created", "pr_merged",
+	// "issue_opened", "issue_closed" and, with --commits, "commit".
+	Sources map[string]int `json:"sources"`
+	Busiest *PunchSlot     `json:"busiest,omitempty"`
+}
+
+// PunchSlot is one weekday-hour cell of a PunchCard.
+type PunchSlot struct {
+	Day   string `json:"day"` // "Mon".."Sun"
+	Hour  int    `json:"hour"`
+	Count int    `json:"count"`
+}
+
+// PeriodTotal is the calendar contributions in one month or quarter of the
+// window. Days is how many of its days the window covers, so partial
+// periods at the edges can be told apart.
+type PeriodTotal struct {
+	Period        string `json:"period"` // "2025-03", "2025Q1" or "FY2025Q1"
+	Contributions int    `json:"contributions"`
+	ActiveDays    int    `json:"active_days"`
+	Days          int    `json:"days"`
+	// VsPrevious compares Contributions with the previous quarter; only when
+	// the window covers both quarters in full.
+	VsPrevious *Delta `json:"vs_previous,omitempty"`
+}
+
+// Seasons breaks the contribution calendar down by month and quarter.
+type Seasons struct {
+	Monthly []PeriodTotal `json:"monthly"`
+	// BusiestMonth and QuietestMonth are picked among the months the window
+	// covers in full, if there are any; ties go to the earliest.
+	BusiestMonth  *PeriodTotal  `json:"busiest_month,omitempty"`
+	QuietestMonth *PeriodTotal  `json:"quietest_month,omitempty"`
+	Quarters      []PeriodTotal `json:"quarters"` // fiscal quarters for a fiscal year, else calendar
+}
+
 type RepoContrib struct {
 	Repo           string `json:"repo"` // owner/name
 	CommitCount    int    `json:"commit_count"`
@@ -15,6 +92,30 @@ type RepoContrib struct {
 	ReviewCount    int    `json:"review_count"`
 	IsPrivate      bool   `json:"is_private"`
 	TotalActivity  int    `json:"total_activity"`
+
+	// From the repository metadata, when it could be fetched.
+	Description     string   `json:"description,omitempty"`
+	PrimaryLanguage string   `json:"primary_language,omitempty"`
+	Topics          []string `json:"topics,omitempty"`
+	Stars           int      `json:"stars,omitempty"`
+	OwnerType       string   `json:"owner_type,omitempty"` // "User" or "Organization"
+}
+
+// RepoMeta is what GitHub reports about a repository today, fetched once and
+// shared by every collector.
+type RepoMeta struct {
+	Repo            string        `json:"repo"` // owner/name, as GitHub spells it
+	Description     string        `json:"description"`
+	PrimaryLanguage string        `json:"primary_language"`
+	Topics          []string      `json:"topics"`
+	Stars           int           `json:"stars"`
+	Forks           int           `json:"forks"`
+	Owner           string        `json:"owner"`
+	OwnerType       string        `json:"owner_type"` // "User" or "Organization"
+	IsFork          bool          `json:"is_fork"`
+	IsArchived      bool          `json:"is_archived"`
+	IsPrivate       bool          `json:"is_private"`
+	Languages       LanguageBytes `json:"languages"`
 }
 
 type PRItem struct {
@@ -38,8 +139,94 @@ type IssueItem struct {
 	ClosedAt  *time.Time `json:"closed_at,omitempty"`
 }
 
+// SearchCoverage records how much of a GitHub search the recap actually saw.
+// GitHub returns at most 1000 results per query, so large ranges are split
+// into sub-ranges; Complete is false if anything was still left out.
+type SearchCoverage struct {
+	Query string `json:"query"`
+	TotalCount int `json:"total_count"` // issueCount GitHub reported for the full range
+	Fetched int `json:"fetched"`
+	Windows int `json:"windows"` // sub-ranges queried
+	Complete bool `json:"complete"`
+}
+
+// CommitItem is one default-branch commit authored by the user.
+type CommitItem struct {
+	Repo string `json:"repo"`
+	SHA string `json:"sha"`
+	URL string `json:"url"`
+	CommittedAt time.Time `json:"committed_at"`
+	AuthoredAt time.Time `json:"authored_at"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Headline string `json:"headline"`
+	MessageLength int `json:"message_length"`
+	HasBody bool `json:"has_body"`
+}
+
+// ReviewedPR is a pull request with its review timeline.
+type ReviewedPR struct {
+	Repo string `json:"repo"`
+	Number int `json:"number"`
+	URL string `json:"url"`
+	Author string `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	Reviews []PRReview `json:"reviews"`
+	ThreadComments []PRComment `json:"thread_comments"`
+	// Truncated is set when some reviews or review comments could not be
+	// read past their first page.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type PRReview struct {
+	Author string `json:"author"`
+	State string `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	SubmittedAt time.Time `json:"submitted_at"`
+	Comments int `json:"comments"`
+}
+
+type PRComment struct {
+	Author string `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type LanguageBytes map[string]int64
 
+// RepoLanguages is one repository's language breakdown in bytes, as GitHub
+// reports it today.
+type RepoLanguages struct {
+	Repo string `json:"repo"`
+	Languages LanguageBytes `json:"languages"`
+	IsFork bool `json:"is_fork"`
+	IsArchived bool `json:"is_archived"`
+}
+
+// ChangedFile is one file changed by one of the user's pull requests, dated
+// by the pull request's creation.
+type ChangedFile struct {
+	Repo string `json:"repo"`
+	Number int `json:"number"`
+	Path string `json:"path"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	At time.Time `json:"at"`
+}
+
+// LanguageShare is one language's weight and share of the total. Bytes is
+// in the unit of the recap's language mode: changed lines or repo bytes.
+type LanguageShare struct {
+	Language string `json:"language"`
+	Bytes int64 `json:"bytes"`
+	Share float64 `json:"share"`
+}
+
+// LanguageMonth is the language breakdown of the lines changed in a month.
+type LanguageMonth struct {
+	Month string `json:"month"` // "2025-03"
+	Lines int64 `json:"lines"`
+	Top []LanguageShare `json:"top"`
+}
+
 type GrowthRepo struct {
 	Repo              string `json:"repo"`
 	StarsGainedInYear int    `json:"stars_gained_in_year"`
@@ -47,18 +234,81 @@ type GrowthRepo struct {
 	StarsNow          int    `json:"stars_now"`
 	ForksNow          int    `json:"forks_now"`
 	IsPrivate         bool   `json:"is_private"`
+	Owner             string `json:"owner"`
+	OwnerType         string `json:"owner_type"` // "User" or "Organization"
+	// Source is why the repo is counted: "owner", "org_maintained",
+	// "top_contributed" or "allowlist".
+	Source string `json:"source"`
+	IsFork bool   `json:"is_fork"`
+
+	FirstStarAt *time.Time    `json:"first_star_at,omitempty"`
+	Series      *GrowthSeries `json:"series,omitempty"`
+	Breakout    *Breakout     `json:"breakout,omitempty"`
+
+	// The collectors keep the raw timestamps inside the window, and how many
+	// stars/forks arrived after it, so analyze can build the series.
+	StarTimes  []time.Time `json:"-"`
+	ForkTimes  []time.Time `json:"-"`
+	StarsAfter int         `json:"-"`
+	ForksAfter int         `json:"-"`
 }
 
 type GrowthMetrics struct {
 	Year int `json:"year"`
+	From time.Time `json:"from"`
+	To time.Time `json:"to"`
 	Repos []GrowthRepo `json:"repos"`
 	TotalStarsGained int `json:"total_stars_gained"`
 	TotalForksGained int `json:"total_forks_gained"`
 	TotalStarsNow int `json:"total_stars_now"`
 	TotalForksNow int `json:"total_forks_now"`
+	// ByOwner attributes the totals to each user or organization owning a
+	// counted repo, most stars gained first.
+	ByOwner []OwnerGrowth `json:"by_owner,omitempty"`
+	// ExcludedForks are forks of upstream projects left out (see --growth-forks).
+	ExcludedForks []string `json:"excluded_forks,omitempty"`
+	// Series sums every repo; Breakout is the biggest single-day star spike
+	// across all of them.
+	Series *GrowthSeries `json:"series,omitempty"`
+	Breakout *Breakout `json:"breakout,omitempty"`
 	Note string `json:"note"`
 }
 
+type OwnerGrowth struct {
+	Owner string `json:"owner"`
+	Type string `json:"type"` // "User" or "Organization"
+	Repos int `json:"repos"`
+	StarsGained int `json:"stars_gained"`
+	ForksGained int `json:"forks_gained"`
+	StarsNow int `json:"stars_now"`
+	ForksNow int `json:"forks_now"`
+}
+
+// GrowthSeries is stars and forks gained per month and per ISO week of the
+// window, with running totals.
+type GrowthSeries struct {
+	Monthly []GrowthPoint `json:"monthly"`
+	Weekly []GrowthPoint `json:"weekly"`
+}
+
+type GrowthPoint struct {
+	Period string `json:"period"` // "2025-03", or ISO week "2025-W09"
+	Stars int `json:"stars"` // gained in the period
+	Forks int `json:"forks"`
+	// Cumulative totals at the end of the period, worked back from today's
+	// counts (unstars and deleted forks make them approximate).
+	CumulativeStars int `json:"cumulative_stars"`
+	CumulativeForks int `json:"cumulative_forks"`
+}
+
+// Breakout is the day a repository gained the most stars.
+type Breakout struct {
+	Repo string `json:"repo"`
+	Date string `json:"date"` // YYYY-MM-DD in the recap time zone
+	Stars int `json:"stars"`
+	Share float64 `json:"share"` // of the stars the repo gained in the window
+}
+
 type ContributionsCollection struct {
 	TotalCommits int `json:"total_commits"`
 	TotalPRs int `json:"total_prs"`
@@ -70,6 +320,21 @@ type ContributionsCollection struct {
 	ByRepoPRs map[string]RepoContribLite `json:"by_repo_prs"`
 	ByRepoIssues map[string]RepoContribLite `json:"by_repo_issues"`
 	ByRepoReviews map[string]RepoContribLite `json:"by_repo_reviews"`
+
+	RepoCoverage []RepoCoverage `json:"repo_coverage"`
+}
+
+// RepoCoverage compares a per-repo breakdown with the matching total.
+// Complete is false when GitHub's 100-repository cap still cut the list
+// short; Consistent is false when the per-repo counts don't add up to the
+// total (e.g. restricted contributions the token cannot see).
+type RepoCoverage struct {
+	Kind string `json:"kind"` // commits, pull_requests, issues, reviews
+	Total int `json:"total"`
+	SumByRepo int `json:"sum_by_repo"`
+	Repos int `json:"repos"`
+	Complete bool `json:"complete"`
+	Consistent bool `json:"consistent"`
 }
 
 type RepoContribLite struct {
@@ -78,11 +343,33 @@ type RepoContribLite struct {
 	IsPrivate bool `json:"is_private"`
 }
 
+// APIUsage is the rate-limit budget a run consumed for one GitHub resource
+// ("core", "search", "graphql").
+type APIUsage struct {
+	Resource string `json:"resource"`
+	Requests int `json:"requests"`
+	Used int `json:"used"` // approximate: points spent by this run, across reset windows
+	Remaining int `json:"remaining"`
+	Limit int `json:"limit"`
+	Reset time.Time `json:"reset"`
+	Retries int `json:"retries"`
+	WaitedSeconds float64 `json:"waited_seconds"`
+}
+
 type Recap struct {
 	Meta struct {
 		User string `json:"user"`
-		Year int `json:"year"`
+		Year int `json:"year"` // year the window starts in
+		From time.Time `json:"from"`
+		To time.Time `json:"to"`
+		Period string `json:"period"` // e.g. "2025", "2025Q3", "FY2025"
+		Timezone string `json:"timezone"` // IANA zone used for window bounds, days and hours
 		GeneratedAt time.Time `json:"generated_at"`
+		Complete bool `json:"complete"` // false if any section is known to be truncated or had failed requests
+		// Interrupted is why collection stopped early (signal or --timeout);
+		// sections collected after that point are partial or missing.
+		Interrupted string `json:"interrupted,omitempty"`
+		APIUsage []APIUsage `json:"api_usage,omitempty"`
 	} `json:"meta"`
 
 	Totals struct {
@@ -96,6 +383,7 @@ type Recap struct {
 	Calendar struct {
 		Days []ContributionDay `json:"days"`
 		LongestStreak int `json:"longest_streak"`
+		Streaks StreakStats `json:"streaks"`
 		MostProductiveDay ContributionDay `json:"most_productive_day"`
 		MostProductiveISOWeek struct{
 			ISOWeek string `json:"iso_week"`
@@ -103,37 +391,333 @@ type Recap struct {
 		} `json:"most_productive_iso_week"`
 	} `json:"calendar"`
 
+	Seasons Seasons `json:"seasons"`
+	// PunchCard is every timestamped event in the window: PRs created and
+	// merged, issues opened and closed, and commits when collected.
+	PunchCard PunchCard `json:"punch_card"`
+
 	TopRepos []RepoContrib `json:"top_repos"`
+	RepoCoverage []RepoCoverage `json:"repo_coverage"`
 
 	PRStats struct {
 		Opened int `json:"opened"`
 		Merged int `json:"merged"`
 		MergeRate float64 `json:"merge_rate"`
 		AvgTimeToMergeHours float64 `json:"avg_time_to_merge_hours"`
+		// MergeTime is only present when a merged PR has a usable duration.
+		MergeTime *MergeTimeStats `json:"merge_time,omitempty"`
 		BiggestPR *PRItem `json:"biggest_pr,omitempty"`
 		TimeOfDayHistogram map[string]int `json:"time_of_day_histogram"` // hour "00".."23"
+		WeekdayHistogram map[string]int `json:"weekday_histogram"` // "Mon".."Sun"
+		Search SearchCoverage `json:"search"`
 	} `json:"pr_stats"`
 
 	IssueStats struct {
 		Opened int `json:"opened"` // issues authored, created in year
 		Closed int `json:"closed"` // issues authored, closed in year
 		TimeOfDayHistogram map[string]int `json:"time_of_day_histogram"`
+		WeekdayHistogram map[string]int `json:"weekday_histogram"`
+		SearchOpened SearchCoverage `json:"search_opened"`
+		SearchClosed SearchCoverage `json:"search_closed"`
 	} `json:"issue_stats"`
 
+	// CommitStats is only present when commit history was collected (--commits).
+	CommitStats *CommitStats `json:"commit_stats,omitempty"`
+
 	Reviews struct {
 		Total int `json:"total"`
 		ByRepo []RepoContribLite `json:"by_repo"`
+		// Depth is only present when review timelines were collected (--reviews).
+		Depth *ReviewDepth `json:"depth,omitempty"`
 	} `json:"reviews"`
 
 	Languages struct {
+		// Mode is how languages were attributed: "changes" counts the lines
+		// the user's PRs added and deleted per file language; "bytes" uses
+		// today's language bytes of every contributed repo. WeightedBytes and
+		// Top are in that unit.
+		Mode string `json:"mode"`
+		// Weighting is how repos were combined in "bytes" mode: "raw" sums
+		// their bytes; "activity" and "equal" split the same total by each
+		// repo's language shares, weighted by the user's activity in the repo
+		// or equally.
+		Weighting string `json:"weighting,omitempty"`
+		// Ignored is the ignore list applied: language names, plus "forks"
+		// and "archived" for repos (bytes mode only). ExcludedRepos are the
+		// repos it left out.
+		Ignored []string `json:"ignored,omitempty"`
+		ExcludedRepos []string `json:"excluded_repos,omitempty"`
 		WeightedBytes LanguageBytes `json:"weighted_bytes"`
-		Top []struct{
-			Language string `json:"language"`
-			Bytes int64 `json:"bytes"`
-			Share float64 `json:"share"`
-		} `json:"top"`
+		Top []LanguageShare `json:"top"`
+		// Monthly is only present in "changes" mode.
+		Monthly []LanguageMonth `json:"monthly,omitempty"`
 		Note string `json:"note"`
 	} `json:"languages"`
 
 	Growth *GrowthMetrics `json:"growth,omitempty"`
+
+	Comparison *Comparison `json:"comparison,omitempty"`
+
+	Diagnostics Diagnostics `json:"diagnostics"`
+}
+
+// Diagnostics says which sections of a recap can be trusted: whether each
+// was collected and complete, every sub-request that failed, and which flags
+// turned sections off.
+type Diagnostics struct {
+	Sections []SectionStatus `json:"sections"`
+	Failures []Failure `json:"failures,omitempty"`
+	Skipped []string `json:"skipped,omitempty"` // e.g. "--skip-growth"
+	Preflight *Preflight `json:"preflight,omitempty"`
+}
+
+// Preflight is what the credentials were found to allow before collecting.
+type Preflight struct {
+	Method string `json:"method"` // token, token_file, app, gh, replay
+	TokenKind string `json:"token_kind"` // classic_pat, fine_grained_pat, oauth, app_installation, app_user, unknown
+	Viewer string `json:"viewer,omitempty"` // login the token acts as; empty for app installations
+	Scopes []string `json:"scopes,omitempty"` // classic PAT / OAuth scopes
+	Permissions map[string]string `json:"permissions,omitempty"` // GitHub App installation permissions
+	Sections []SectionAccess `json:"sections"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SectionAccess is whether the credentials cover one recap section.
+// Verified is false when the token type cannot be inspected (fine-grained
+// PATs don't expose their permissions).
+type SectionAccess struct {
+	Section string `json:"section"`
+	Needs []string `json:"needs"` // scopes or permissions the section uses
+	Missing []string `json:"missing,omitempty"`
+	Verified bool `json:"verified"`
+	Effect string `json:"effect,omitempty"` // what is lost without them
+}
+
+type SectionStatus struct {
+	Section string `json:"section"` // contributions, pull_requests, issues, commits, reviews, languages, growth
+	Collected bool `json:"collected"`
+	Complete bool `json:"complete"`
+	Failures int `json:"failures,omitempty"`
+	Note string `json:"note,omitempty"`
+}
+
+// Failure is one failed sub-request, e.g. the language breakdown of a single
+// repository.
+type Failure struct {
+	Section string `json:"section"`
+	Operation string `json:"operation"` // e.g. "list_languages", "list_stargazers"
+	Repo string `json:"repo,omitempty"`
+	Status int `json:"status,omitempty"` // HTTP status, when the server answered
+	Class string `json:"class"` // rate_limited, unauthorized, forbidden, not_found, server_error, http_error, timeout, canceled, network, graphql, other
+	Error string `json:"error"`
+}
+
+// ReviewDepth describes the reviews the user gave inside the window and the
+// reviews their own PRs received.
+type ReviewDepth struct {
+	PRsReviewed int `json:"prs_reviewed"`
+	Submitted int `json:"submitted"` // review submissions
+	Approvals int `json:"approvals"`
+	ChangesRequested int `json:"changes_requested"`
+	Commented int `json:"commented"`
+	Dismissed int `json:"dismissed"`
+	ReviewComments int `json:"review_comments"` // inline review-thread comments written
+	MedianHoursToFirstReview float64 `json:"median_hours_to_first_review"` // PR opened -> user's first review
+	TopAuthorsReviewed []PersonCount `json:"top_authors_reviewed"`
+	TopReviewers []PersonCount `json:"top_reviewers"` // who reviewed the user's PRs most
+	SearchGiven SearchCoverage `json:"search_given"`
+	SearchReceived SearchCoverage `json:"search_received"`
+	TruncatedPRs int `json:"truncated_prs"` // PRs whose reviews or review comments could not all be read
+}
+
+// MergeTimeStats is the distribution of the time from opening a PR to
+// merging it, in hours. PRs merged "before" they were created (clock skew)
+// are left out.
+type MergeTimeStats struct {
+	Count       int     `json:"count"`
+	MedianHours float64 `json:"median_hours"`
+	P75Hours    float64 `json:"p75_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+	P99Hours    float64 `json:"p99_hours"`
+	MinHours    float64 `json:"min_hours"`
+	MaxHours    float64 `json:"max_hours"`
+	// Histogram has log-scale buckets from "<10m" to ">=4w", in order,
+	// empty ones included.
+	Histogram []BucketCount `json:"histogram"`
+	Fastest   []MergedPR    `json:"fastest"`
+	Slowest   []MergedPR    `json:"slowest"`
+	// ByRepo is the repos with the most merged PRs first; BySize buckets PRs
+	// by additions + deletions ("XS" < 10 lines ... "XXL" >= 1000).
+	ByRepo []MergeTimeGroup `json:"by_repo"`
+	BySize []MergeTimeGroup `json:"by_size"`
+}
+
+type BucketCount struct {
+	Bucket string `json:"bucket"`
+	Count  int    `json:"count"`
+}
+
+// MergedPR is a merged PR and how long it took.
+type MergedPR struct {
+	Repo   string  `json:"repo"`
+	Number int     `json:"number"`
+	Title  string  `json:"title"`
+	URL    string  `json:"url"`
+	Size   int     `json:"size"` // additions + deletions
+	Hours  float64 `json:"hours"`
+}
+
+// MergeTimeGroup is the merge time of the PRs in one repo or size bucket.
+type MergeTimeGroup struct {
+	Group       string  `json:"group"`
+	Count       int     `json:"count"`
+	MedianHours float64 `json:"median_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+}
+
+type PersonCount struct {
+	Login string `json:"login"`
+	Count int `json:"count"`
+}
+
+type CommitStats struct {
+	Total int `json:"total"`
+	Repos int `json:"repos"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	TimeOfDayHistogram map[string]int `json:"time_of_day_histogram"` // by committed time, hour "00".."23"
+	WeekdayHistogram map[string]int `json:"weekday_histogram"`
+	ByRepo []CommitRepoStats `json:"by_repo"`
+	LargestCommit *CommitItem `json:"largest_commit,omitempty"`
+
+	Messages struct {
+		AvgHeadlineLength float64 `json:"avg_headline_length"`
+		AvgMessageLength float64 `json:"avg_message_length"`
+		WithBody int `json:"with_body"`
+		Merges int `json:"merges"`
+		Conventional int `json:"conventional"` // "feat: ...", "fix(scope): ..."
+		ConventionalTypes map[string]int `json:"conventional_types"`
+	} `json:"messages"`
+}
+
+// CommitRepoStats is the line churn of the user's commits in one repository.
+type CommitRepoStats struct {
+	Repo string `json:"repo"`
+	Commits int `json:"commits"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// Comparison is the change from a previous period's recap to this one.
+type Comparison struct {
+	Previous struct {
+		Period string `json:"period"`
+		From time.Time `json:"from"`
+		To time.Time `json:"to"`
+	} `json:"previous"`
+
+	Totals map[string]Delta `json:"totals"` // commits, pull_requests, issues, reviews, overall
+	MergeRate Delta `json:"merge_rate"`
+	AvgTimeToMergeHours Delta `json:"avg_time_to_merge_hours"`
+	LongestStreak Delta `json:"longest_streak"`
+	Languages []LanguageShift `json:"languages"`
+
+	TopRepos struct {
+		Entered []string `json:"entered"`
+		Left []string `json:"left"`
+		Stayed []string `json:"stayed"`
+	} `json:"top_repos"`
+}
+
+// Delta compares one metric across two periods. PercentChange is nil when the
+// previous value is zero.
+type Delta struct {
+	Previous float64 `json:"previous"`
+	Current float64 `json:"current"`
+	Change float64 `json:"change"`
+	PercentChange *float64 `json:"percent_change"`
+}
+
+// LanguageShift is a change in a language's share of the weighted bytes,
+// in percentage points.
+type LanguageShift struct {
+	Language string `json:"language"`
+	PreviousShare float64 `json:"previous_share"`
+	CurrentShare float64 `json:"current_share"`
+	ChangePoints float64 `json:"change_points"`
+}
+
+// TeamRecap aggregates the recaps of several users (an org, a team or a list
+// of logins) for the same window.
+type TeamRecap struct {
+	Meta struct {
+		Team string `json:"team"` // "org:acme", "team:acme/platform" or the members file
+		Year int `json:"year"`
+		From time.Time `json:"from"`
+		To time.Time `json:"to"`
+		Period string `json:"period"`
+		Timezone string `json:"timezone"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Members []string `json:"members"` // logins with a recap
+		Failed []MemberError `json:"failed,omitempty"` // members not collected, or whose review pairs are partial
+		Complete bool `json:"complete"`
+		Interrupted string `json:"interrupted,omitempty"`
+		APIUsage []APIUsage `json:"api_usage,omitempty"`
+	} `json:"meta"`
+
+	Totals struct {
+		Commits int `json:"commits"`
+		PullRequests int `json:"pull_requests"`
+		Issues int `json:"issues"`
+		Reviews int `json:"reviews"`
+		Overall int `json:"overall"`
+		PRsMerged int `json:"prs_merged"`
+	} `json:"totals"`
+
+	Calendar struct {
+		Days []ContributionDay `json:"days"` // summed over members
+		LongestStreak int `json:"longest_streak"`
+		MostProductiveDay ContributionDay `json:"most_productive_day"`
+	} `json:"calendar"`
+
+	// Leaderboards ranks members per metric: commits, pull_requests, issues,
+	// reviews, overall, prs_merged, longest_streak.
+	Leaderboards map[string][]LeaderboardEntry `json:"leaderboards"`
+
+	SharedRepos []SharedRepo `json:"shared_repos"`
+
+	Reviews struct {
+		CrossMember int `json:"cross_member"` // PRs by one member reviewed by another
+		Pairs []ReviewPair `json:"pairs"`
+	} `json:"reviews"`
+}
+
+type MemberError struct {
+	Login string `json:"login"`
+	Error string `json:"error"`
+}
+
+type LeaderboardEntry struct {
+	Login string `json:"login"`
+	Value float64 `json:"value"`
+}
+
+// SharedRepo is a repository at least two members contributed to.
+type SharedRepo struct {
+	Repo string `json:"repo"`
+	IsPrivate bool `json:"is_private"`
+	TotalActivity int `json:"total_activity"`
+	Members []MemberActivity `json:"members"`
+}
+
+type MemberActivity struct {
+	Login string `json:"login"`
+	Activity int `json:"activity"`
+}
+
+// ReviewPair counts PRs authored by Author that Reviewer reviewed.
+type ReviewPair struct {
+	Reviewer string `json:"reviewer"`
+	Author string `json:"author"`
+	Count int `json:"count"`
 }
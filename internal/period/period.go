@@ -0,0 +1,134 @@
+// Package period turns the recap's window flags (--year, --quarter, --from/--to,
+// fiscal years) into concrete time ranges.
+package period
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is the range a recap covers. Both ends are inclusive; To is the last
+// second of the final day.
+type Window struct {
+	From  time.Time
+	To    time.Time
+	Label string // "2025", "2025Q3", "FY2025", "2025-07-01..2025-09-30"
+}
+
+// Year is the calendar year y.
+func Year(y int, loc *time.Location) Window {
+	return Window{
+		From:  time.Date(y, 1, 1, 0, 0, 0, 0, loc),
+		To:    endOfDay(time.Date(y, 12, 31, 0, 0, 0, 0, loc)),
+		Label: strconv.Itoa(y),
+	}
+}
+
+// FiscalYear is the twelve months starting on the first of startMonth in
+// year y, so FiscalYear(2025, time.July) runs Jul 1 2025 – Jun 30 2026 and is
+// labelled "FY2025". A startMonth of January is the calendar year.
+func FiscalYear(y int, startMonth time.Month, loc *time.Location) Window {
+	if startMonth == time.January {
+		return Year(y, loc)
+	}
+	from := time.Date(y, startMonth, 1, 0, 0, 0, 0, loc)
+	return Window{
+		From:  from,
+		To:    from.AddDate(1, 0, 0).Add(-time.Second),
+		Label: fmt.Sprintf("FY%d", y),
+	}
+}
+
+var quarterRe = regexp.MustCompile(`^(\d{4})-?[Qq]([1-4])$`)
+
+// Quarter parses "2025Q3" (or "2025-Q3") into that calendar quarter.
+func Quarter(spec string, loc *time.Location) (Window, error) {
+	m := quarterRe.FindStringSubmatch(spec)
+	if m == nil {
+		return Window{}, fmt.Errorf("invalid quarter %q (want e.g. 2025Q3)", spec)
+	}
+	y, _ := strconv.Atoi(m[1])
+	q, _ := strconv.Atoi(m[2])
+	from := time.Date(y, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, loc)
+	return Window{
+		From:  from,
+		To:    from.AddDate(0, 3, 0).Add(-time.Second),
+		Label: fmt.Sprintf("%dQ%d", y, q),
+	}, nil
+}
+
+// Range parses --from/--to dates (YYYY-MM-DD). An empty to means today.
+func Range(from, to string, loc *time.Location) (Window, error) {
+	f, err := time.ParseInLocation("2006-01-02", from, loc)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid --from %q: %w", from, err)
+	}
+	t := time.Now().In(loc)
+	if to != "" {
+		t, err = time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid --to %q: %w", to, err)
+		}
+	}
+	t = endOfDay(t)
+	if t.Before(f) {
+		return Window{}, fmt.Errorf("--to %s is before --from %s", t.Format("2006-01-02"), from)
+	}
+	return Window{
+		From:  f,
+		To:    t,
+		Label: fmt.Sprintf("%s..%s", f.Format("2006-01-02"), t.Format("2006-01-02")),
+	}, nil
+}
+
+// Split cuts w into consecutive windows no longer than one year, the most
+// GitHub's contributionsCollection accepts.
+func (w Window) Split() []Window {
+	var out []Window
+	start := w.From
+	for !start.After(w.To) {
+		end := start.AddDate(1, 0, 0).Add(-time.Second)
+		if end.After(w.To) {
+			end = w.To
+		}
+		out = append(out, Window{From: start, To: end, Label: w.Label})
+		start = end.Add(time.Second)
+	}
+	return out
+}
+
+// AddYears shifts the window by n years, e.g. to compare with the same
+// quarter or fiscal year of a previous year.
+func (w Window) AddYears(n int) Window {
+	out := Window{From: addYears(w.From, n, false), To: addYears(w.To, n, true)}
+	if strings.Contains(w.Label, "..") {
+		out.Label = fmt.Sprintf("%s..%s", out.From.Format("2006-01-02"), out.To.Format("2006-01-02"))
+	} else {
+		out.Label = strings.Replace(w.Label, strconv.Itoa(w.From.Year()), strconv.Itoa(out.From.Year()), 1)
+	}
+	return out
+}
+
+// addYears moves t n years on the calendar, keeping the time of day. Unlike
+// AddDate, Feb 29 in a non-leap year becomes Feb 28 rather than Mar 1, and
+// with monthEnd the last day of a month stays the last day, so a fiscal
+// year ending in February lines up with the next one.
+func addYears(t time.Time, n int, monthEnd bool) time.Time {
+	y, m, d := t.Date()
+	last := daysIn(y+n, m)
+	if d > last || (monthEnd && d == daysIn(y, m)) {
+		d = last
+	}
+	return time.Date(y+n, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+}
@@ -0,0 +1,279 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+const stamp = "2006-01-02 15:04:05 -0700"
+
+func checkWindow(t *testing.T, name string, w Window, from, to, label string) {
+	t.Helper()
+	if got := w.From.Format(stamp); got != from {
+		t.Errorf("%s: From = %s, want %s", name, got, from)
+	}
+	if got := w.To.Format(stamp); got != to {
+		t.Errorf("%s: To = %s, want %s", name, got, to)
+	}
+	if w.Label != label {
+		t.Errorf("%s: Label = %q, want %q", name, w.Label, label)
+	}
+}
+
+func TestYear(t *testing.T) {
+	la := mustZone(t, "America/Los_Angeles")
+	auckland := mustZone(t, "Pacific/Auckland")
+	// Both ends are winter in Los Angeles and summer in Auckland; the
+	// offsets change in between.
+	checkWindow(t, "LA", Year(2025, la), "2025-01-01 00:00:00 -0800", "2025-12-31 23:59:59 -0800", "2025")
+	checkWindow(t, "Auckland", Year(2025, auckland), "2025-01-01 00:00:00 +1300", "2025-12-31 23:59:59 +1300", "2025")
+	checkWindow(t, "leap", Year(2024, time.UTC), "2024-01-01 00:00:00 +0000", "2024-12-31 23:59:59 +0000", "2024")
+}
+
+func TestFiscalYear(t *testing.T) {
+	la := mustZone(t, "America/Los_Angeles")
+	tests := []struct {
+		name     string
+		y        int
+		start    time.Month
+		loc      *time.Location
+		from, to string
+		label    string
+	}{
+		{"january is the calendar year", 2025, time.January, time.UTC, "2025-01-01 00:00:00 +0000", "2025-12-31 23:59:59 +0000", "2025"},
+		{"july", 2025, time.July, time.UTC, "2025-07-01 00:00:00 +0000", "2026-06-30 23:59:59 +0000", "FY2025"},
+		// Starts in summer time, ends in winter time.
+		{"october in a DST zone", 2025, time.October, la, "2025-10-01 00:00:00 -0700", "2026-09-30 23:59:59 -0700", "FY2025"},
+		{"march to a leap february", 2023, time.March, time.UTC, "2023-03-01 00:00:00 +0000", "2024-02-29 23:59:59 +0000", "FY2023"},
+		{"march to a plain february", 2024, time.March, time.UTC, "2024-03-01 00:00:00 +0000", "2025-02-28 23:59:59 +0000", "FY2024"},
+	}
+	for _, tt := range tests {
+		checkWindow(t, tt.name, FiscalYear(tt.y, tt.start, tt.loc), tt.from, tt.to, tt.label)
+	}
+}
+
+func TestQuarter(t *testing.T) {
+	tests := []struct {
+		spec     string
+		from, to string
+		label    string
+	}{
+		{"2025Q1", "2025-01-01 00:00:00 +0000", "2025-03-31 23:59:59 +0000", "2025Q1"},
+		{"2024-q1", "2024-01-01 00:00:00 +0000", "2024-03-31 23:59:59 +0000", "2024Q1"},
+		{"2025Q3", "2025-07-01 00:00:00 +0000", "2025-09-30 23:59:59 +0000", "2025Q3"},
+		{"2025Q4", "2025-10-01 00:00:00 +0000", "2025-12-31 23:59:59 +0000", "2025Q4"},
+	}
+	for _, tt := range tests {
+		w, err := Quarter(tt.spec, time.UTC)
+		if err != nil {
+			t.Errorf("Quarter(%q): %v", tt.spec, err)
+			continue
+		}
+		checkWindow(t, tt.spec, w, tt.from, tt.to, tt.label)
+	}
+
+	// Summer time starts inside Q1 in Los Angeles.
+	w, err := Quarter("2025Q1", mustZone(t, "America/Los_Angeles"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWindow(t, "LA Q1", w, "2025-01-01 00:00:00 -0800", "2025-03-31 23:59:59 -0700", "2025Q1")
+
+	for _, bad := range []string{"", "2025", "2025Q5", "2025Q0", "25Q1", "Q1 2025"} {
+		if _, err := Quarter(bad, time.UTC); err == nil {
+			t.Errorf("Quarter(%q) accepted", bad)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	w, err := Range("2024-02-28", "2024-03-01", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWindow(t, "leap day", w, "2024-02-28 00:00:00 +0000", "2024-03-01 23:59:59 +0000", "2024-02-28..2024-03-01")
+
+	// The spring-forward day in Los Angeles is 23 hours long.
+	w, err = Range("2025-03-09", "2025-03-09", mustZone(t, "America/Los_Angeles"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWindow(t, "DST day", w, "2025-03-09 00:00:00 -0800", "2025-03-09 23:59:59 -0700", "2025-03-09..2025-03-09")
+
+	w, err = Range("2025-01-01", "", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if today := time.Now().UTC().Format(time.DateOnly); w.To.Format(time.DateOnly) != today || w.To.Hour() != 23 {
+		t.Errorf("empty --to: To = %v, want the end of %s", w.To, today)
+	}
+
+	for _, bad := range [][2]string{{"2025-03-02", "2025-03-01"}, {"2025-13-01", ""}, {"2025-01-01", "tomorrow"}, {"", "2025-01-01"}} {
+		if _, err := Range(bad[0], bad[1], time.UTC); err == nil {
+			t.Errorf("Range(%q, %q) accepted", bad[0], bad[1])
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	w, err := Range("2023-03-15", "2025-06-30", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := w.Split()
+	want := [][2]string{
+		{"2023-03-15 00:00:00", "2024-03-14 23:59:59"},
+		{"2024-03-15 00:00:00", "2025-03-14 23:59:59"},
+		{"2025-03-15 00:00:00", "2025-06-30 23:59:59"},
+	}
+	if len(parts) != len(want) {
+		t.Fatalf("Split = %+v, want %d windows", parts, len(want))
+	}
+	const layout = "2006-01-02 15:04:05"
+	for i, p := range parts {
+		if p.From.Format(layout) != want[i][0] || p.To.Format(layout) != want[i][1] || p.Label != w.Label {
+			t.Errorf("part %d = %s..%s %q, want %s..%s", i, p.From.Format(layout), p.To.Format(layout), p.Label, want[i][0], want[i][1])
+		}
+		if i > 0 && !p.From.Equal(parts[i-1].To.Add(time.Second)) {
+			t.Errorf("part %d does not follow part %d", i, i-1)
+		}
+	}
+
+	// A year or less is one window, and a DST zone leaves no gaps.
+	la := mustZone(t, "America/Los_Angeles")
+	if parts := Year(2025, la).Split(); len(parts) != 1 {
+		t.Errorf("a year split into %d windows", len(parts))
+	}
+	w, _ = Range("2024-01-01", "2026-12-31", la)
+	parts = w.Split()
+	if len(parts) != 3 || !parts[0].From.Equal(w.From) || !parts[2].To.Equal(w.To) {
+		t.Fatalf("three years in LA: %+v", parts)
+	}
+	for i := 1; i < len(parts); i++ {
+		if !parts[i].From.Equal(parts[i-1].To.Add(time.Second)) || parts[i].From.Hour() != 0 {
+			t.Errorf("part %d starts at %v after %v", i, parts[i].From, parts[i-1].To)
+		}
+	}
+}
+
+func TestAddYears(t *testing.T) {
+	la := mustZone(t, "America/Los_Angeles")
+	q1, err := Quarter("2024Q1", la)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leapDay, err := Range("2024-02-29", "2024-03-10", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		w        Window
+		n        int
+		from, to string
+		label    string
+	}{
+		{"year", Year(2025, time.UTC), -1, "2024-01-01 00:00:00 +0000", "2024-12-31 23:59:59 +0000", "2024"},
+		{"fiscal year back onto a leap february", FiscalYear(2024, time.March, time.UTC), -1, "2023-03-01 00:00:00 +0000", "2024-02-29 23:59:59 +0000", "FY2023"},
+		{"fiscal year off a leap february", FiscalYear(2023, time.March, time.UTC), 1, "2024-03-01 00:00:00 +0000", "2025-02-28 23:59:59 +0000", "FY2024"},
+		{"quarter across DST", q1, -1, "2023-01-01 00:00:00 -0800", "2023-03-31 23:59:59 -0700", "2023Q1"},
+		{"range from a leap day", leapDay, -1, "2023-02-28 00:00:00 +0000", "2023-03-10 23:59:59 +0000", "2023-02-28..2023-03-10"},
+	}
+	for _, tt := range tests {
+		checkWindow(t, tt.name, tt.w.AddYears(tt.n), tt.from, tt.to, tt.label)
+	}
+
+	// A shifted fiscal year is the one FiscalYear builds, so the compare
+	// window never overlaps the current one.
+	for y := 2019; y <= 2026; y++ {
+		for _, start := range []time.Month{time.January, time.March, time.April, time.October} {
+			got, want := FiscalYear(y, start, la).AddYears(-1), FiscalYear(y-1, start, la)
+			if !got.From.Equal(want.From) || !got.To.Equal(want.To) || got.Label != want.Label {
+				t.Errorf("FiscalYear(%d, %s).AddYears(-1) = %+v, want %+v", y, start, got, want)
+			}
+		}
+	}
+}
+
+func TestZoneForLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string // "" for no match
+	}{
+		{"Taipei, Taiwan", "Asia/Taipei"},
+		{"  taipei  ", "Asia/Taipei"},
+		{"San Francisco, CA", "America/Los_Angeles"},
+		{"Europe/Berlin", "Europe/Berlin"},
+		{"Berlin | Remote", "Europe/Berlin"},
+		{"Vancouver (BC)", "America/Vancouver"},
+		{"London, UK", "Europe/London"},
+		{"São Paulo", "America/Sao_Paulo"},
+		// The city wins over the country.
+		{"Osaka, Japan", "Asia/Tokyo"},
+		{"Mumbai, India", "Asia/Kolkata"},
+		// Multi-zone countries only match through a city.
+		{"USA", ""},
+		{"Canada", ""},
+		// Whole words only.
+		{"Ukulele land", ""},
+		{"Parisville", ""},
+		{"Mars/Olympus_Mons", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		loc, ok := ZoneForLocation(tt.location)
+		got := ""
+		if ok {
+			got = loc.String()
+		}
+		if got != tt.want {
+			t.Errorf("ZoneForLocation(%q) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestZoneName(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want string
+	}{
+		{"named", mustZone(t, "Asia/Tokyo"), "Asia/Tokyo"},
+		{"utc", time.UTC, "UTC"},
+		{"nil", nil, "UTC"},
+		{"unnamed fixed zone", time.FixedZone("", 9*3600), "+09:00"},
+		{"negative offset", time.FixedZone("Local", -(3*3600 + 30*60)), "-03:30"},
+	}
+	for _, tt := range tests {
+		if got := ZoneName(tt.loc, from); got != tt.want {
+			t.Errorf("%s: ZoneName = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct{ tz, want string }{
+		{"Europe/Berlin", "Europe/Berlin"},
+		{":Asia/Taipei", "Asia/Taipei"},
+		{"/usr/share/zoneinfo/America/Vancouver", "America/Vancouver"},
+		{"", "UTC"},
+		{"Mars/Olympus_Mons", ""},
+	} {
+		t.Setenv("TZ", tt.tz)
+		if got := localZoneName(); got != tt.want {
+			t.Errorf("TZ=%q: localZoneName = %q, want %q", tt.tz, got, tt.want)
+		}
+	}
+	// time.Local is named from $TZ when the process starts.
+	if got := ZoneName(time.Local, from); got == "" || got == "Local" {
+		t.Errorf("ZoneName(time.Local) = %q", got)
+	}
+}
@@ -0,0 +1,216 @@
+package period
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// locationZones maps words commonly found in GitHub profile locations to an
+// IANA zone. Matching is case-insensitive on whole words or phrases; the first
+// entry found in the location wins, so cities are listed before countries.
+var locationZones = []struct {
+	match string
+	zone  string
+}{
+	// Cities
+	{"taipei", "Asia/Taipei"},
+	{"kaohsiung", "Asia/Taipei"},
+	{"taichung", "Asia/Taipei"},
+	{"hsinchu", "Asia/Taipei"},
+	{"tokyo", "Asia/Tokyo"},
+	{"osaka", "Asia/Tokyo"},
+	{"seoul", "Asia/Seoul"},
+	{"beijing", "Asia/Shanghai"},
+	{"shanghai", "Asia/Shanghai"},
+	{"shenzhen", "Asia/Shanghai"},
+	{"hangzhou", "Asia/Shanghai"},
+	{"hong kong", "Asia/Hong_Kong"},
+	{"singapore", "Asia/Singapore"},
+	{"bangalore", "Asia/Kolkata"},
+	{"bengaluru", "Asia/Kolkata"},
+	{"mumbai", "Asia/Kolkata"},
+	{"delhi", "Asia/Kolkata"},
+	{"hyderabad", "Asia/Kolkata"},
+	{"jakarta", "Asia/Jakarta"},
+	{"bangkok", "Asia/Bangkok"},
+	{"ho chi minh", "Asia/Ho_Chi_Minh"},
+	{"hanoi", "Asia/Ho_Chi_Minh"},
+	{"manila", "Asia/Manila"},
+	{"kuala lumpur", "Asia/Kuala_Lumpur"},
+	{"dubai", "Asia/Dubai"},
+	{"tel aviv", "Asia/Jerusalem"},
+	{"istanbul", "Europe/Istanbul"},
+	{"moscow", "Europe/Moscow"},
+	{"kyiv", "Europe/Kyiv"},
+	{"warsaw", "Europe/Warsaw"},
+	{"berlin", "Europe/Berlin"},
+	{"munich", "Europe/Berlin"},
+	{"hamburg", "Europe/Berlin"},
+	{"vienna", "Europe/Vienna"},
+	{"zurich", "Europe/Zurich"},
+	{"amsterdam", "Europe/Amsterdam"},
+	{"brussels", "Europe/Brussels"},
+	{"paris", "Europe/Paris"},
+	{"madrid", "Europe/Madrid"},
+	{"barcelona", "Europe/Madrid"},
+	{"lisbon", "Europe/Lisbon"},
+	{"rome", "Europe/Rome"},
+	{"milan", "Europe/Rome"},
+	{"stockholm", "Europe/Stockholm"},
+	{"oslo", "Europe/Oslo"},
+	{"copenhagen", "Europe/Copenhagen"},
+	{"helsinki", "Europe/Helsinki"},
+	{"dublin", "Europe/Dublin"},
+	{"london", "Europe/London"},
+	{"new york", "America/New_York"},
+	{"nyc", "America/New_York"},
+	{"boston", "America/New_York"},
+	{"toronto", "America/Toronto"},
+	{"montreal", "America/Toronto"},
+	{"chicago", "America/Chicago"},
+	{"austin", "America/Chicago"},
+	{"denver", "America/Denver"},
+	{"seattle", "America/Los_Angeles"},
+	{"san francisco", "America/Los_Angeles"},
+	{"bay area", "America/Los_Angeles"},
+	{"los angeles", "America/Los_Angeles"},
+	{"vancouver", "America/Vancouver"},
+	{"mexico city", "America/Mexico_City"},
+	{"são paulo", "America/Sao_Paulo"},
+	{"sao paulo", "America/Sao_Paulo"},
+	{"buenos aires", "America/Argentina/Buenos_Aires"},
+	{"sydney", "Australia/Sydney"},
+	{"melbourne", "Australia/Melbourne"},
+	{"auckland", "Pacific/Auckland"},
+	{"lagos", "Africa/Lagos"},
+	{"nairobi", "Africa/Nairobi"},
+	{"cape town", "Africa/Johannesburg"},
+
+	// Countries with a single zone
+	{"taiwan", "Asia/Taipei"},
+	{"japan", "Asia/Tokyo"},
+	{"korea", "Asia/Seoul"},
+	{"china", "Asia/Shanghai"},
+	{"india", "Asia/Kolkata"},
+	{"vietnam", "Asia/Ho_Chi_Minh"},
+	{"thailand", "Asia/Bangkok"},
+	{"philippines", "Asia/Manila"},
+	{"malaysia", "Asia/Kuala_Lumpur"},
+	{"israel", "Asia/Jerusalem"},
+	{"turkey", "Europe/Istanbul"},
+	{"ukraine", "Europe/Kyiv"},
+	{"poland", "Europe/Warsaw"},
+	{"germany", "Europe/Berlin"},
+	{"austria", "Europe/Vienna"},
+	{"switzerland", "Europe/Zurich"},
+	{"netherlands", "Europe/Amsterdam"},
+	{"belgium", "Europe/Brussels"},
+	{"france", "Europe/Paris"},
+	{"spain", "Europe/Madrid"},
+	{"italy", "Europe/Rome"},
+	{"sweden", "Europe/Stockholm"},
+	{"norway", "Europe/Oslo"},
+	{"denmark", "Europe/Copenhagen"},
+	{"finland", "Europe/Helsinki"},
+	{"ireland", "Europe/Dublin"},
+	{"united kingdom", "Europe/London"},
+	{"uk", "Europe/London"},
+	{"england", "Europe/London"},
+	{"new zealand", "Pacific/Auckland"},
+	{"nigeria", "Africa/Lagos"},
+	{"kenya", "Africa/Nairobi"},
+}
+
+// ZoneForLocation guesses a time zone from free-form profile text such as
+// "Taipei, Taiwan". The text may also be an IANA name itself. It reports false
+// when nothing matches; multi-zone countries (US, Canada, Brazil, ...) only
+// match through a city.
+func ZoneForLocation(location string) (*time.Location, bool) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return nil, false
+	}
+	if strings.Contains(location, "/") {
+		if loc, err := time.LoadLocation(location); err == nil {
+			return loc, true
+		}
+	}
+
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(location), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '/' || r == '-' || r == '(' || r == ')' || r == '.' || r == '|'
+	}), " ") + " "
+	for _, z := range locationZones {
+		if !strings.Contains(words, " "+z.match+" ") {
+			continue
+		}
+		if loc, err := time.LoadLocation(z.zone); err == nil {
+			return loc, true
+		}
+	}
+	return nil, false
+}
+
+// Local is the machine's zone, loaded by its IANA name when one can be found
+// (see ZoneName) so that it is recorded as more than "Local".
+func Local() *time.Location {
+	if name := localZoneName(); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// ZoneName is the IANA name of loc. time.Local is named from $TZ or the
+// /etc/localtime link; failing that, it is its UTC offset at t ("+09:00").
+func ZoneName(loc *time.Location, t time.Time) string {
+	if loc == nil {
+		return "UTC"
+	}
+	if name := loc.String(); name != "" && name != "Local" {
+		return name
+	}
+	if loc == time.Local {
+		if name := localZoneName(); name != "" {
+			return name
+		}
+	}
+	return t.In(loc).Format("-07:00")
+}
+
+// localZoneName is the IANA name of the machine's zone, or "" if unknown.
+func localZoneName() string {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		tz = strings.TrimPrefix(tz, ":")
+		if tz == "" {
+			return "UTC" // as the time package reads it
+		}
+		if filepath.IsAbs(tz) {
+			return zoneinfoName(tz)
+		}
+		if _, err := time.LoadLocation(tz); err == nil {
+			return tz
+		}
+		return ""
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		return zoneinfoName(target)
+	}
+	return ""
+}
+
+// zoneinfoName turns a path such as /usr/share/zoneinfo/Asia/Tokyo into
+// "Asia/Tokyo".
+func zoneinfoName(path string) string {
+	i := strings.LastIndex(path, "zoneinfo/")
+	if i < 0 {
+		return ""
+	}
+	name := path[i+len("zoneinfo/"):]
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
@@ -68,6 +68,34 @@ function calculateAchievements(recap) {
   return achievements;
 }
 
+// incompleteReasons lists, in plain words, why a recap is not complete.
+function incompleteReasons(recap) {
+  const reasons = [];
+  if (recap.meta?.interrupted) reasons.push(`Collection was interrupted: ${recap.meta.interrupted}.`);
+  const diag = recap.diagnostics || {};
+  for (const s of diag.sections || []) {
+    if (!s.collected || s.complete) continue;
+    let why = s.note || "partially collected";
+    if (s.failures) why = `${fmt(s.failures)} failed request${s.failures === 1 ? "" : "s"}` + (s.note ? `; ${s.note}` : "");
+    reasons.push(`${s.section.replace("_", " ")}: ${why}.`);
+  }
+  if (reasons.length === 0 && recap.meta?.complete === false) reasons.push("Some results were capped or failed.");
+  return reasons;
+}
+
+function renderIncomplete(recap) {
+  if (recap.meta?.complete !== false) return;
+  const reasons = incompleteReasons(recap);
+  const badge = document.getElementById("incomplete");
+  const details = document.getElementById("incomplete_details");
+  if (badge) {
+    badge.hidden = false;
+    badge.title = reasons.join("\n");
+    if (details) badge.addEventListener("click", () => { details.hidden = !details.hidden; });
+  }
+  if (details) details.textContent = reasons.join(" ");
+}
+
 async function main(){
   const dataFile = qs("data") || "recap_2025.json";
   const res = await fetch(`./${dataFile}`);
@@ -77,6 +105,7 @@ async function main(){
   document.getElementById("user").textContent = recap.meta.user;
   document.getElementById("year").textContent = recap.meta.year;
   document.getElementById("gen").textContent = new Date(recap.meta.generated_at).toISOString().replace("T"," ").slice(0,19) + " UTC";
+  renderIncomplete(recap);
 
   // Totals card
   document.getElementById("t_commits").textContent = fmt(recap.totals.commits);
@@ -89,6 +118,10 @@ async function main(){
 
   // Calendar / streak
   document.getElementById("streak").textContent = fmt(recap.calendar.longest_streak);
+  const streaks = recap.calendar.streaks || {};
+  if (streaks.longest) document.getElementById("streak_dates").textContent = `(${streaks.longest.start} → ${streaks.longest.end})`;
+  document.getElementById("streak_current").textContent = fmt(streaks.current?.days || 0);
+  document.getElementById("streak_gap").textContent = streaks.longest_gap ? `${fmt(streaks.longest_gap.days)} days (${streaks.longest_gap.start} → ${streaks.longest_gap.end})` : "—";
   document.getElementById("best_day").textContent = `${recap.calendar.most_productive_day.date} (${fmt(recap.calendar.most_productive_day.count)})`;
   document.getElementById("best_week").textContent = `${recap.calendar.most_productive_iso_week.iso_week} (${fmt(recap.calendar.most_productive_iso_week.count)})`;
 
@@ -107,6 +140,63 @@ async function main(){
     heat.appendChild(div);
   }
 
+  // Punch card: shade each weekday/hour relative to the busiest one
+  const punch = document.getElementById("punchcard");
+  const pc = recap.punch_card;
+  if (punch && pc) {
+    punch.innerHTML = "";
+    const peak = Math.max(1, ...pc.cells.flat());
+    pc.cells.forEach((row, d) => {
+      const label = document.createElement("div");
+      label.textContent = pc.days[d];
+      punch.appendChild(label);
+      row.forEach((n, h) => {
+        const div = document.createElement("div");
+        div.className = "cell";
+        div.dataset.l = String(n > 0 ? Math.ceil((n / peak) * 4) : 0);
+        div.title = `${pc.days[d]} ${String(h).padStart(2, "0")}:00: ${n}`;
+        punch.appendChild(div);
+      });
+    });
+    if (pc.busiest) {
+      document.getElementById("punch_busiest").textContent = `Busiest: ${pc.busiest.day} ${String(pc.busiest.hour).padStart(2, "0")}:00 (${fmt(pc.busiest.count)} events)`;
+    }
+  }
+
+  // Seasons
+  const seasons = recap.seasons || {};
+  const monthsChart = document.getElementById("months_chart");
+  if (monthsChart && (seasons.monthly || []).length) {
+    monthsChart.innerHTML = "";
+    const peak = Math.max(1, ...seasons.monthly.map((m) => m.contributions));
+    seasons.monthly.forEach((m) => {
+      const bar = document.createElement("div");
+      bar.className = "bar";
+      bar.style.height = `${(m.contributions / peak) * 100}%`;
+      bar.title = `${m.period}: ${fmt(m.contributions)} contributions on ${fmt(m.active_days)} days`;
+      monthsChart.appendChild(bar);
+    });
+  }
+  const monthText = (m) => m ? `${m.period} (${fmt(m.contributions)})` : "—";
+  document.getElementById("busiest_month").textContent = monthText(seasons.busiest_month);
+  document.getElementById("quietest_month").textContent = monthText(seasons.quietest_month);
+  const quarters = document.getElementById("quarters");
+  quarters.innerHTML = "";
+  (seasons.quarters || []).forEach((q) => {
+    const row = document.createElement("div");
+    row.className = "row";
+    const left = document.createElement("div");
+    left.className = "name";
+    left.textContent = q.period;
+    const right = document.createElement("div");
+    right.className = "meta";
+    const pct = q.vs_previous?.percent_change;
+    right.textContent = `${fmt(q.contributions)}` + (pct != null ? ` (${pct >= 0 ? "+" : ""}${pct.toFixed(0)}% vs previous)` : "");
+    row.appendChild(left);
+    row.appendChild(right);
+    quarters.appendChild(row);
+  });
+
   // Top repos
   const repoList = document.getElementById("top_repos");
   repoList.innerHTML = "";
@@ -133,12 +223,39 @@ async function main(){
   const big = recap.pr_stats.biggest_pr;
   document.getElementById("big_pr").textContent = big ? `${big.repo}#${big.number} (+${big.additions}/-${big.deletions})` : "—";
 
+  // Merge time distribution: the median resists the odd stale PR
+  const mt = recap.pr_stats.merge_time;
+  if (mt) {
+    const pr = (p) => `${p.repo}#${p.number} in ${hoursToHuman(p.hours)}`;
+    document.getElementById("pr_merge_pcts").textContent = [mt.median_hours, mt.p90_hours, mt.p99_hours].map(hoursToHuman).join(" / ");
+    if (mt.fastest?.length) document.getElementById("pr_fastest").textContent = pr(mt.fastest[0]);
+    if (mt.slowest?.length) document.getElementById("pr_slowest").textContent = pr(mt.slowest[0]);
+    const hist = document.getElementById("merge_hist");
+    hist.innerHTML = "";
+    const peak = Math.max(1, ...mt.histogram.map((b) => b.count));
+    mt.histogram.forEach((b) => {
+      const bar = document.createElement("div");
+      bar.className = "bar";
+      bar.style.height = `${(b.count / peak) * 100}%`;
+      bar.title = `${b.bucket}: ${fmt(b.count)} PRs`;
+      hist.appendChild(bar);
+    });
+    document.getElementById("merge_by_size").textContent = "Median by size: " + (mt.by_size || []).map((g) => `${g.group} ${hoursToHuman(g.median_hours)}`).join(" · ");
+  }
+
   // Issue stats
   document.getElementById("iss_opened").textContent = fmt(recap.issue_stats.opened);
   document.getElementById("iss_closed").textContent = fmt(recap.issue_stats.closed);
 
   // Languages
   const langs = recap.languages?.weighted_bytes || {};
+  const byLines = recap.languages?.mode === "changes";
+  const weighting = { activity: "weighted by your activity in each repo", equal: "every repo weighted equally" }[recap.languages?.weighting];
+  if (byLines) {
+    document.getElementById("langs_mode").textContent = "Weighted by lines your PRs added and deleted, by file extension.";
+  } else if (weighting) {
+    document.getElementById("langs_mode").textContent = `Current repo language breakdown across repos you contributed to, ${weighting}.`;
+  }
   const items = Object.entries(langs).map(([k,v])=>[k, Number(v)]).sort(byDesc).slice(0, 10);
   const langList = document.getElementById("langs");
   langList.innerHTML = "";
@@ -164,7 +281,7 @@ async function main(){
     left.appendChild(text);
     const right = document.createElement("div");
     right.className = "meta";
-    right.textContent = `${(v/total*100).toFixed(1)}% (${fmt(v)})`;
+    right.textContent = `${(v/total*100).toFixed(1)}% (${fmt(v)}${byLines ? " lines" : ""})`;
     row.appendChild(left);
     row.appendChild(right);
     langList.appendChild(row);
@@ -216,14 +333,43 @@ if (rr) {
   // Growth
   const g = recap.growth;
   const growthBox = document.getElementById("growth");
+  const growthSkipped = (recap.diagnostics?.skipped || []).includes("--skip-growth");
   if (!g) {
-    growthBox.innerHTML = `<div class="small">Growth metrics were skipped or unavailable. Re-run without <code>--skip-growth</code>.</div>`;
+    growthBox.innerHTML = growthSkipped
+      ? `<div class="small">Growth metrics were skipped. Re-run without <code>--skip-growth</code>.</div>`
+      : `<div class="small">Growth metrics are unavailable for this recap.</div>`;
   } else {
     document.getElementById("stars_gained").textContent = fmt(g.total_stars_gained);
     document.getElementById("forks_gained").textContent = fmt(g.total_forks_gained);
     document.getElementById("stars_now").textContent = fmt(g.total_stars_now);
     document.getElementById("forks_now").textContent = fmt(g.total_forks_now);
 
+    // Monthly stars gained, as bars
+    const chart = document.getElementById("growth_chart");
+    const months = g.series?.monthly || [];
+    if (chart && months.length) {
+      chart.innerHTML = "";
+      const peak = Math.max(1, ...months.map((m) => m.stars));
+      months.forEach((m) => {
+        const bar = document.createElement("div");
+        bar.className = "bar";
+        bar.style.height = `${(m.stars / peak) * 100}%`;
+        bar.title = `${m.period}: +${fmt(m.stars)}★ (${fmt(m.cumulative_stars)} total)`;
+        chart.appendChild(bar);
+      });
+    }
+    const bo = g.breakout;
+    const boEl = document.getElementById("breakout");
+    if (bo && boEl) {
+      boEl.textContent = `Breakout: ${bo.repo} gained ${fmt(bo.stars)}★ on ${bo.date} (${(bo.share * 100).toFixed(0)}% of its stars this period)`;
+    }
+
+    const owners = g.by_owner || [];
+    const ownersEl = document.getElementById("growth_owners");
+    if (owners.length > 1 && ownersEl) {
+      ownersEl.textContent = "By owner: " + owners.slice(0, 5).map((o)=>`${o.owner} +★${fmt(o.stars_gained)}`).join(" · ");
+    }
+
     const top = (g.repos || []).slice(0, 8);
     const list = document.getElementById("growth_repos");
     list.innerHTML = "";